@@ -1,7 +1,10 @@
 package ai
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"regexp"
 	"strings"
 )
 
@@ -330,15 +333,132 @@ func Use(modelID string) *Builder {
 // Template Processing
 // ═══════════════════════════════════════════════════════════════════════════
 
-// Vars is a shorthand for template variables
-type Vars map[string]string
-
-// applyTemplate replaces {{key}} with values
+// Vars is a shorthand for template variables. Values can be any type:
+// strings are substituted as-is, other scalars render with %v, and
+// slices/maps/structs render as JSON.
+type Vars map[string]any
+
+// templatePlaceholder matches {{key}}, {{key|json}}, and {{key:-fallback}}.
+var templatePlaceholder = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// applyTemplate replaces {{key}} placeholders in text with values from vars.
+//
+//   - {{key}} renders the value: strings directly, other scalars via %v,
+//     and slices/maps/structs as JSON.
+//   - {{key|json}} always renders the value as JSON, even for strings.
+//   - {{key:-fallback}} substitutes fallback (a literal string) when key
+//     isn't present in vars.
+//
+// A placeholder whose key is missing from vars and has no :-fallback is
+// left unresolved in the output; in Debug mode a warning is printed naming
+// the missing key.
 func applyTemplate(text string, vars Vars) string {
-	for k, v := range vars {
-		text = strings.ReplaceAll(text, "{{"+k+"}}", v)
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(match string) string {
+		expr := match[2 : len(match)-2]
+
+		key := expr
+		forceJSON := false
+		fallback := ""
+		hasFallback := false
+
+		switch {
+		case strings.HasSuffix(expr, "|json"):
+			key = strings.TrimSuffix(expr, "|json")
+			forceJSON = true
+		case strings.Contains(expr, ":-"):
+			parts := strings.SplitN(expr, ":-", 2)
+			key, fallback = parts[0], parts[1]
+			hasFallback = true
+		}
+
+		v, ok := vars[key]
+		if !ok {
+			if hasFallback {
+				return fallback
+			}
+			if Debug {
+				fmt.Printf("%s Warning: template variable %q is not set\n", colorYellow("⚠"), key)
+			}
+			return match
+		}
+
+		if forceJSON {
+			return renderTemplateJSON(v)
+		}
+		return renderTemplateValue(v)
+	})
+}
+
+// renderTemplateValue renders v for substitution into a prompt: strings
+// pass through unchanged, other scalars use %v, and everything else
+// (slices, maps, structs, pointers) renders as JSON.
+func renderTemplateValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", val)
+	default:
+		return renderTemplateJSON(val)
+	}
+}
+
+// renderTemplateJSON marshals v to JSON, falling back to %v if it can't be
+// marshaled (e.g. a channel or func value).
+func renderTemplateJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// unresolvedTemplateVars returns the keys of every {{key}}, {{key|json}}, or
+// {{key:-fallback}} placeholder still present in text after applyTemplate
+// has run. A placeholder only survives applyTemplate when its key was
+// missing from vars and it had no :-fallback, so any match here names a
+// genuinely missing variable.
+func unresolvedTemplateVars(text string) []string {
+	var missing []string
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+		key := match[1]
+		if idx := strings.Index(key, "|json"); idx != -1 {
+			key = key[:idx]
+		}
+		missing = append(missing, key)
+	}
+	return missing
+}
+
+// MissingVarsError is returned by Builder.Send/SendWithMeta when StrictVars
+// is enabled and the prompt still contains unresolved {{key}} placeholders
+// after template substitution.
+type MissingVarsError struct {
+	Keys []string
+}
+
+// Error implements the error interface.
+func (e *MissingVarsError) Error() string {
+	return fmt.Sprintf("missing template variables: %s", strings.Join(e.Keys, ", "))
+}
+
+// FileLoadError is returned by Builder.Err/SendWithMeta when one or more
+// calls to SystemFile, SystemFS, Context, ContextFS, or ContextWith failed
+// to read their file. Errors accumulates in the order the failing calls were
+// made, rather than aborting the chain at the first one, so a single typo in
+// a path surfaces as a clear error instead of a confusing model response
+// built from whatever context did load.
+type FileLoadError struct {
+	Errors []error
+}
+
+// Error implements the error interface.
+func (e *FileLoadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
 	}
-	return text
+	return fmt.Sprintf("failed to load %d file(s): %s", len(e.Errors), strings.Join(msgs, "; "))
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
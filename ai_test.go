@@ -177,6 +177,67 @@ func TestApplyTemplate(t *testing.T) {
 	}
 }
 
+func TestApplyTemplate_NonStringValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		vars     Vars
+		expected string
+	}{
+		{
+			name:     "int scalar",
+			text:     "You have {{count}} items.",
+			vars:     Vars{"count": 3},
+			expected: "You have 3 items.",
+		},
+		{
+			name:     "bool scalar",
+			text:     "Enabled: {{on}}",
+			vars:     Vars{"on": true},
+			expected: "Enabled: true",
+		},
+		{
+			name:     "slice renders as JSON",
+			text:     "Tags: {{tags}}",
+			vars:     Vars{"tags": []string{"a", "b"}},
+			expected: `Tags: ["a","b"]`,
+		},
+		{
+			name:     "struct renders as JSON",
+			text:     "User: {{user}}",
+			vars:     Vars{"user": struct{ Name string }{Name: "Ada"}},
+			expected: `User: {"Name":"Ada"}`,
+		},
+		{
+			name:     "json modifier forces JSON even for strings",
+			text:     "Name: {{name|json}}",
+			vars:     Vars{"name": "Ada"},
+			expected: `Name: "Ada"`,
+		},
+		{
+			name:     "fallback used when key missing",
+			text:     "Hello {{name:-stranger}}!",
+			vars:     Vars{},
+			expected: "Hello stranger!",
+		},
+		{
+			name:     "fallback ignored when key present",
+			text:     "Hello {{name:-stranger}}!",
+			vars:     Vars{"name": "Ada"},
+			expected: "Hello Ada!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := applyTemplate(tt.text, tt.vars)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestApplyVars(t *testing.T) {
 	text := "Hello {{name}}!"
 	vars := Vars{"name": "Test"}
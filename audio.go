@@ -28,8 +28,9 @@ type STTModel string
 
 const (
 	// OpenAI STT Models
-	STTWhisper1   STTModel = "whisper-1"
-	STTGpt4oAudio STTModel = "gpt-4o-transcribe"
+	STTWhisper1          STTModel = "whisper-1"
+	STTGpt4oAudio        STTModel = "gpt-4o-transcribe"
+	STTGpt4oAudioDiarize STTModel = "gpt-4o-transcribe-diarize" // supports speaker diarization
 
 	// Google STT (via Gemini)
 	STTGemini STTModel = "gemini-2.5-flash-preview-stt"
@@ -96,20 +97,72 @@ type TTSResponse struct {
 	ContentType string
 }
 
+// InvalidAudioFormatError is returned by TextToSpeech implementations when
+// TTSRequest.Format isn't one of the supported AudioFormat values, instead
+// of letting the provider reject it with an opaque HTTP error.
+type InvalidAudioFormatError struct {
+	Format string
+}
+
+// Error implements the error interface.
+func (e *InvalidAudioFormatError) Error() string {
+	return fmt.Sprintf("unsupported audio format %q: must be one of mp3, opus, aac, flac, wav, pcm", e.Format)
+}
+
+// validAudioFormats is the set of AudioFormat values supported across
+// providers.
+var validAudioFormats = map[string]bool{
+	string(AudioFormatMP3):  true,
+	string(AudioFormatOpus): true,
+	string(AudioFormatAAC):  true,
+	string(AudioFormatFLAC): true,
+	string(AudioFormatWAV):  true,
+	string(AudioFormatPCM):  true,
+}
+
+// audioContentTypes maps AudioFormat values to the MIME type a provider
+// would normally report via Content-Type, for providers that omit the
+// header in their audio response.
+var audioContentTypes = map[string]string{
+	string(AudioFormatMP3):  "audio/mpeg",
+	string(AudioFormatOpus): "audio/opus",
+	string(AudioFormatAAC):  "audio/aac",
+	string(AudioFormatFLAC): "audio/flac",
+	string(AudioFormatWAV):  "audio/wav",
+	string(AudioFormatPCM):  "audio/pcm",
+}
+
+// ValidateAudioFormat checks format against the supported AudioFormat set,
+// returning an *InvalidAudioFormatError if it isn't one of them.
+func ValidateAudioFormat(format string) error {
+	if !validAudioFormats[format] {
+		return &InvalidAudioFormatError{Format: format}
+	}
+	return nil
+}
+
+// defaultContentType returns the MIME type for an AudioFormat, or "" if
+// format isn't recognized.
+func defaultContentType(format string) string {
+	return audioContentTypes[format]
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // STT Request/Response
 // ═══════════════════════════════════════════════════════════════════════════
 
 // STTRequest is a provider-agnostic request for speech-to-text.
 type STTRequest struct {
-	Model       string
-	Audio       []byte // audio data
-	AudioURL    string // or audio URL
-	Filename    string // filename for format detection
-	Language    string // optional: language hint (ISO 639-1)
-	Prompt      string // optional: context/prompt to guide transcription
-	Temperature float64
-	Timestamps  bool // include word-level timestamps
+	Model        string
+	Audio        []byte // audio data
+	AudioURL     string // or audio URL
+	Filename     string // filename for format detection
+	Language     string // optional: language hint (ISO 639-1)
+	Prompt       string // optional: context/prompt to guide transcription
+	Temperature  float64
+	Timestamps   bool // include word-level timestamps
+	Diarize      bool // request speaker diarization (diarization-capable models only)
+	SpeakerCount int  // optional hint for the expected number of distinct speakers; 0 lets the model decide
 }
 
 // STTResponse is a provider-agnostic response from speech-to-text.
@@ -118,6 +171,7 @@ type STTResponse struct {
 	Language string
 	Duration float64 // audio duration in seconds
 	Words    []WordTimestamp
+	Segments []TranscriptSegment // per-speaker segments, populated when Diarize was requested
 }
 
 // WordTimestamp represents a word with timing information.
@@ -127,6 +181,28 @@ type WordTimestamp struct {
 	End   float64 // seconds
 }
 
+// TranscriptSegment represents a speaker-attributed span of a diarized transcript.
+type TranscriptSegment struct {
+	Speaker string
+	Text    string
+	Start   float64 // seconds
+	End     float64 // seconds
+}
+
+// Speakers returns the distinct speaker labels present in Segments, in the
+// order they first appear. Empty if the transcript wasn't diarized.
+func (r *STTResponse) Speakers() []string {
+	var speakers []string
+	seen := make(map[string]bool)
+	for _, seg := range r.Segments {
+		if !seen[seg.Speaker] {
+			seen[seg.Speaker] = true
+			speakers = append(speakers, seg.Speaker)
+		}
+	}
+	return speakers
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // TTS Builder - Fluent API
 // ═══════════════════════════════════════════════════════════════════════════
@@ -282,16 +358,18 @@ func (t *TTSBuilder) Save(path string) error {
 
 // STTBuilder provides a fluent API for speech-to-text
 type STTBuilder struct {
-	model       STTModel
-	audio       []byte
-	audioURL    string
-	filename    string
-	language    string
-	prompt      string
-	temperature float64
-	timestamps  bool
-	client      *Client
-	ctx         context.Context
+	model        STTModel
+	audio        []byte
+	audioURL     string
+	filename     string
+	language     string
+	prompt       string
+	temperature  float64
+	timestamps   bool
+	diarize      bool
+	speakerCount int
+	client       *Client
+	ctx          context.Context
 }
 
 // Transcribe creates a new STT builder from a file path
@@ -356,6 +434,15 @@ func (s *STTBuilder) WithTimestamps() *STTBuilder {
 	return s
 }
 
+// Diarize enables speaker diarization. Requires a diarization-capable model
+// (e.g. STTGpt4oAudioDiarize). speakers is an optional hint for the expected
+// number of distinct speakers; pass 0 to let the model decide.
+func (s *STTBuilder) Diarize(speakers int) *STTBuilder {
+	s.diarize = true
+	s.speakerCount = speakers
+	return s
+}
+
 // WithClient sets a specific client/provider
 func (s *STTBuilder) WithClient(client *Client) *STTBuilder {
 	s.client = client
@@ -400,14 +487,16 @@ func (s *STTBuilder) DoWithMeta() (*STTResponse, error) {
 	}
 
 	req := &STTRequest{
-		Model:       string(s.model),
-		Audio:       s.audio,
-		AudioURL:    s.audioURL,
-		Filename:    s.filename,
-		Language:    s.language,
-		Prompt:      s.prompt,
-		Temperature: s.temperature,
-		Timestamps:  s.timestamps,
+		Model:        string(s.model),
+		Audio:        s.audio,
+		AudioURL:     s.audioURL,
+		Filename:     s.filename,
+		Language:     s.language,
+		Prompt:       s.prompt,
+		Temperature:  s.temperature,
+		Timestamps:   s.timestamps,
+		Diarize:      s.diarize,
+		SpeakerCount: s.speakerCount,
 	}
 
 	if Debug {
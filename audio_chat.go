@@ -0,0 +1,43 @@
+package ai
+
+import "encoding/base64"
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Audio Input (in-conversation, for audio-capable chat models)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// AudioInput represents audio included directly in a chat message, for
+// audio-capable chat models such as gpt-audio and gpt-4o-audio-preview. This
+// is distinct from the standalone TTS/STT endpoints in audio.go, which call
+// separate /audio/speech and /audio/transcriptions APIs rather than sending
+// audio as part of a conversation.
+type AudioInput struct {
+	Data   []byte // raw audio bytes
+	Format string // "wav", "mp3"
+}
+
+// AudioOutput holds audio returned by an audio-capable chat model, alongside
+// a text transcript of it.
+type AudioOutput struct {
+	Data       []byte // raw decoded audio bytes
+	Transcript string
+}
+
+// Audio adds audio to the request as an input_audio content part, for
+// audio-capable chat models. Like Image and PDF, it converts the last user
+// message to multimodal content.
+func (b *Builder) Audio(audio AudioInput) *Builder {
+	b.audios = append(b.audios, audio)
+	return b
+}
+
+// audioContentPart builds the wire-format content part for a.
+func audioContentPart(a AudioInput) ContentPart {
+	return ContentPart{
+		Type: "input_audio",
+		InputAudio: &InputAudio{
+			Data:   base64.StdEncoding.EncodeToString(a.Data),
+			Format: a.Format,
+		},
+	}
+}
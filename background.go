@@ -0,0 +1,119 @@
+package ai
+
+import (
+	gocontext "context"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Background Responses (deep-research models, etc.)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Background submits the request as an async background job instead of
+// blocking for the result, returning a handle the caller can Poll or Await.
+// Use this for long-running models (e.g. OpenAI's o3-deep-research) whose
+// synchronous response would otherwise time out. Only providers implementing
+// BackgroundResponder support this; others return an error.
+func (b *Builder) Background() (*BackgroundJob, error) {
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	ctx := b.getContext()
+
+	if len(b.attachedFiles) > 0 {
+		b.resolveAttachedFiles(ctx, client)
+	}
+
+	msgs := b.buildMessages(client.providerType)
+
+	starter, ok := client.provider.(BackgroundResponder)
+	if !ok {
+		return nil, &ProviderError{Provider: client.provider.Name(), Message: "provider does not support background responses"}
+	}
+
+	req := &ProviderRequest{
+		Model:                string(b.model),
+		Messages:             msgs,
+		Thinking:             b.thinking,
+		ThinkingBudgetTokens: b.thinkingBudget,
+		Tools:                b.tools,
+		BuiltinTools:         b.builtinTools,
+		Headers:              b.headers,
+		EndUser:              b.endUser,
+		ReasoningSummary:     b.reasoningSummary,
+		Verbosity:            b.verbosity,
+		Background:           true,
+	}
+
+	id, err := starter.StartBackground(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackgroundJob{id: id, model: b.model, client: client, start: time.Now()}, nil
+}
+
+// BackgroundJob is a handle to an in-flight background response, returned by
+// Builder.Background.
+type BackgroundJob struct {
+	id     string
+	model  Model
+	client *Client
+	start  time.Time
+}
+
+// ID returns the provider-assigned ID backing this job, for persisting and
+// resuming a poll later (e.g. across process restarts).
+func (j *BackgroundJob) ID() string {
+	return j.id
+}
+
+// Poll checks the job's status once, without blocking. done is false (with
+// a nil meta and error) while the job is still queued or running.
+func (j *BackgroundJob) Poll(ctx gocontext.Context) (meta *ResponseMeta, done bool, err error) {
+	poller, ok := j.client.provider.(BackgroundResponder)
+	if !ok {
+		return nil, false, &ProviderError{Provider: j.client.provider.Name(), Message: "provider does not support background responses"}
+	}
+
+	resp, status, err := poller.PollResponse(ctx, j.id)
+	if err != nil {
+		return nil, false, err
+	}
+	if status != "completed" {
+		return nil, false, nil
+	}
+
+	return &ResponseMeta{
+		Content:          resp.Content,
+		Model:            j.model,
+		Latency:          time.Since(j.start),
+		Tokens:           resp.TotalTokens,
+		PromptTokens:     resp.PromptTokens,
+		CompletionTokens: resp.CompletionTokens,
+		ResponsesOutput:  resp.ResponsesOutput,
+		Reasoning:        resp.Reasoning,
+		Raw:              resp.Raw,
+	}, true, nil
+}
+
+// Await polls every interval until the job completes or ctx is canceled.
+func (j *BackgroundJob) Await(ctx gocontext.Context, interval time.Duration) (*ResponseMeta, error) {
+	for {
+		meta, done, err := j.Poll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return meta, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
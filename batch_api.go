@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Offline Batch API (OpenAI Batch API, etc.)
+// ═══════════════════════════════════════════════════════════════════════════
+//
+// This is a different feature from the BatchBuilder in batch.go: BatchBuilder
+// fans requests out concurrently and blocks for every result, while this
+// uploads a whole set of requests as a single file for a provider to process
+// asynchronously (typically within 24h) at a price discount. Use SubmitBatch
+// for large, non-urgent workloads where cost matters more than latency.
+
+// BatchJob references a batch job previously submitted via SubmitBatch. It
+// carries the client the job was submitted through, so GetBatch and
+// DownloadBatchResults route back to the same provider instead of assuming
+// every batch was submitted against the default client.
+type BatchJob struct {
+	ID     string
+	client *Client
+}
+
+// SubmitBatch resolves and serializes each builder's request, then submits
+// them as a single offline batch job via BatchProcessor, returning a
+// BatchJob referencing the job. All builders must share a BatchProcessor
+// provider - the first builder's client (via WithClient) is used, falling
+// back to the default client. Only providers implementing BatchProcessor
+// support this; others return an error.
+func SubmitBatch(ctx context.Context, builders []*Builder) (*BatchJob, error) {
+	if len(builders) == 0 {
+		return nil, fmt.Errorf("ai: SubmitBatch requires at least one builder")
+	}
+
+	client := builders[0].client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	processor, ok := client.provider.(BatchProcessor)
+	if !ok {
+		return nil, &ProviderError{Provider: client.provider.Name(), Message: "provider does not support the Batch API"}
+	}
+
+	reqs := make([]*ProviderRequest, len(builders))
+	for i, b := range builders {
+		bc := b.client
+		if bc == nil {
+			bc = client
+		}
+
+		msgs := b.buildMessages(bc.providerType)
+		reqs[i] = b.buildProviderRequest(b.model, msgs)
+	}
+
+	id, err := processor.SubmitBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchJob{ID: id, client: client}, nil
+}
+
+// GetBatch reports the status of batch (e.g. "validating", "in_progress",
+// "completed", "failed"), using the client it was submitted through.
+func GetBatch(ctx context.Context, batch *BatchJob) (string, error) {
+	processor, ok := batch.client.provider.(BatchProcessor)
+	if !ok {
+		return "", &ProviderError{Provider: batch.client.provider.Name(), Message: "provider does not support the Batch API"}
+	}
+
+	return processor.BatchStatus(ctx, batch.ID)
+}
+
+// DownloadBatchResults downloads and parses the results of a completed batch
+// job, in the same order the builders were passed to SubmitBatch. Call
+// GetBatch first to confirm the job has completed.
+func DownloadBatchResults(ctx context.Context, batch *BatchJob) ([]*ProviderResponse, error) {
+	processor, ok := batch.client.provider.(BatchProcessor)
+	if !ok {
+		return nil, &ProviderError{Provider: batch.client.provider.Name(), Message: "provider does not support the Batch API"}
+	}
+
+	return processor.BatchResults(ctx, batch.ID)
+}
@@ -0,0 +1,153 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAIBatch_SubmitGetAndDownloadResultsRoundTrip(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var uploadedJSONL string
+	var batchCreateBody string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files":
+			if err := r.ParseMultipartForm(10 << 20); err != nil {
+				t.Fatalf("failed to parse multipart form: %v", err)
+			}
+			if got := r.FormValue("purpose"); got != "batch" {
+				t.Fatalf("expected purpose=batch, got %q", got)
+			}
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("missing uploaded file: %v", err)
+			}
+			data, _ := io.ReadAll(file)
+			uploadedJSONL = string(data)
+			_, _ = w.Write([]byte(`{"id":"file-input-1"}`))
+
+		case r.Method == "POST" && r.URL.Path == "/batches":
+			data, _ := io.ReadAll(r.Body)
+			batchCreateBody = string(data)
+			_, _ = w.Write([]byte(`{"id":"batch-1","status":"validating"}`))
+
+		case r.Method == "GET" && r.URL.Path == "/batches/batch-1":
+			_, _ = w.Write([]byte(`{"id":"batch-1","status":"completed","output_file_id":"file-output-1"}`))
+
+		case r.Method == "GET" && r.URL.Path == "/files/file-output-1/content":
+			lines := []string{
+				`{"custom_id":"req-1","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"second"},"finish_reason":"stop"}],"usage":{"total_tokens":5}}}}`,
+				`{"custom_id":"req-0","response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":"first"},"finish_reason":"stop"}],"usage":{"total_tokens":4}}}}`,
+			}
+			_, _ = w.Write([]byte(strings.Join(lines, "\n") + "\n"))
+
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(ProviderOpenAI, WithBaseURL(srv.URL), WithAPIKey("k"))
+	SetDefaultClient(client)
+
+	builders := []*Builder{
+		New(ModelGPT5).WithClient(client).User("first"),
+		New(ModelGPT5).WithClient(client).User("second"),
+	}
+
+	batch, err := SubmitBatch(context.Background(), builders)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+	if batch.ID != "batch-1" {
+		t.Fatalf("expected batch ID batch-1, got %q", batch.ID)
+	}
+	if !strings.Contains(uploadedJSONL, `"custom_id":"req-0"`) || !strings.Contains(uploadedJSONL, `"custom_id":"req-1"`) {
+		t.Fatalf("expected uploaded JSONL to carry both requests, got %q", uploadedJSONL)
+	}
+	if !strings.Contains(batchCreateBody, `"endpoint":"/v1/chat/completions"`) {
+		t.Fatalf("expected batch creation to target chat completions, got %q", batchCreateBody)
+	}
+
+	status, err := GetBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if status != "completed" {
+		t.Fatalf("expected status completed, got %q", status)
+	}
+
+	results, err := DownloadBatchResults(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("DownloadBatchResults failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Content != "first" || results[1].Content != "second" {
+		t.Fatalf("expected results ordered by custom_id index, got %q, %q", results[0].Content, results[1].Content)
+	}
+}
+
+func TestOpenAIBatch_SubmitBatchRequiresAtLeastOneBuilder(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	if _, err := SubmitBatch(context.Background(), nil); err == nil {
+		t.Fatal("expected an error when submitting an empty batch")
+	}
+}
+
+func TestOpenAIBatch_GetBatchRoutesThroughSubmittingClientNotDefault(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	nonDefaultSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/files":
+			_, _ = w.Write([]byte(`{"id":"file-input-1"}`))
+		case r.Method == "POST" && r.URL.Path == "/batches":
+			_, _ = w.Write([]byte(`{"id":"batch-on-non-default","status":"validating"}`))
+		case r.Method == "GET" && r.URL.Path == "/batches/batch-on-non-default":
+			_, _ = w.Write([]byte(`{"id":"batch-on-non-default","status":"completed","output_file_id":"file-output-1"}`))
+		default:
+			t.Fatalf("unexpected request to non-default client's server: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer nonDefaultSrv.Close()
+
+	// The default client points at a server that should never be hit by
+	// this test - GetBatch must route through the client the batch was
+	// actually submitted with.
+	defaultSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request to default client's server: %s %s", r.Method, r.URL.Path)
+	}))
+	defer defaultSrv.Close()
+	SetDefaultClient(NewClient(ProviderOpenAI, WithBaseURL(defaultSrv.URL), WithAPIKey("default-k")))
+
+	nonDefaultClient := NewClient(ProviderOpenAI, WithBaseURL(nonDefaultSrv.URL), WithAPIKey("non-default-k"))
+	builders := []*Builder{New(ModelGPT5).WithClient(nonDefaultClient).User("hi")}
+
+	batch, err := SubmitBatch(context.Background(), builders)
+	if err != nil {
+		t.Fatalf("SubmitBatch failed: %v", err)
+	}
+
+	status, err := GetBatch(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if status != "completed" {
+		t.Fatalf("expected status completed, got %q", status)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	gocontext "context"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Builder provides a fluent API for constructing AI requests.
@@ -27,27 +29,105 @@ type Builder struct {
 	// vars holds template variables for prompt substitution.
 	vars Vars
 
+	// strictVars makes SendWithMeta fail if any {{...}} placeholder remains
+	// unresolved after template substitution.
+	strictVars bool
+
 	// fileContext contains file contents injected into the context.
 	fileContext []string // file contents to inject (renamed from context)
 
+	// loadErrs accumulates failures from SystemFile, SystemFS, Context,
+	// ContextFS, and ContextWith, surfaced via Err() and SendWithMeta.
+	loadErrs []error
+
+	// contextPlacement controls where fileContext is injected, set via
+	// ContextPlacement. Zero value keeps the original behavior (appended
+	// to the end of the system prompt).
+	contextPlacement ContextPlacement
+
+	// contextLabel overrides the "# Context" header fileContext is
+	// injected under, set via ContextLabel.
+	contextLabel string
+
 	// debug enables verbose logging for this request.
 	debug bool
 
+	// captureRaw requests that the unparsed provider response body be
+	// retained on ResponseMeta.Raw.
+	captureRaw bool
+
 	// maxRetries specifies the number of retry attempts on failure.
 	maxRetries int
 
 	// fallbacks is a list of models to try if the primary model fails.
 	fallbacks []Model
 
+	// fallbackTimeout caps each model attempt when fallbacks are
+	// configured, set via FallbackTimeout. Zero means no explicit
+	// per-attempt cap - the remaining context deadline (if any) is instead
+	// divided evenly across the remaining attempts.
+	fallbackTimeout time.Duration
+
 	// jsonMode forces the model to output valid JSON.
 	jsonMode bool
 
+	// jsonNative, set via JSONMode, skips the "Respond with valid JSON
+	// only" system-prompt injection for models whose Capabilities() report
+	// native JSON support, relying solely on the provider's response_format.
+	jsonNative bool
+
+	// strictJSONDecode disables AskJSON/Into's lenient extraction (brace
+	// matching past surrounding prose, tolerating trailing commas), set via
+	// StrictJSON, for callers who'd rather fail loudly than risk extracting
+	// the wrong JSON value from an odd response.
+	strictJSONDecode bool
+
 	// temperature controls randomness (0.0 to 2.0).
 	temperature *float64
 
 	// thinking controls the reasoning effort level.
 	thinking ThinkingLevel
 
+	// thinkingBudget is an exact reasoning token budget set via
+	// ThinkingBudget, in place of thinking's bucketed levels. Zero means unset.
+	thinkingBudget int
+
+	// reasoningSummary requests a reasoning summary at the given verbosity
+	// ("auto", "concise", "detailed") from OpenAI's Responses API.
+	reasoningSummary string
+
+	// verbosity controls gpt-5.1+'s answer length ("low", "medium", "high"),
+	// independent of max tokens.
+	verbosity string
+
+	// serviceTier requests OpenAI's "auto", "default", "flex", or
+	// "priority" service tier, set via ServiceTier, trading latency for
+	// cost (flex) or cost for latency (priority).
+	serviceTier string
+
+	// safetySettings overrides Gemini's default content-safety thresholds,
+	// set via SafetySettings. Ignored by providers other than Google.
+	safetySettings []SafetySetting
+
+	// metadata carries arbitrary key/value tags echoed back by OpenAI and
+	// Anthropic in their dashboards and webhooks, set via Metadata.
+	metadata map[string]string
+
+	// inputItems are pre-built Responses API input items appended after the
+	// conversation history, set via InputItems.
+	inputItems []any
+
+	// forceTool names a tool the model must call, set via ForceTool; empty
+	// means the provider's default ("auto").
+	forceTool string
+
+	// prefill seeds the start of the assistant's response, set via Prefill.
+	// Anthropic models continue directly from a trailing assistant message,
+	// so it's sent that way and stitched back onto the completion. Other
+	// providers don't support this, so it's folded into a system
+	// instruction instead.
+	prefill string
+
 	// Tool calling (function tools)
 	tools        []Tool
 	toolHandlers map[string]ToolHandler
@@ -61,6 +141,17 @@ type Builder struct {
 	// Documents (PDF)
 	documents []DocumentInput
 
+	// Audio (in-conversation input for audio-capable chat models)
+	audios []AudioInput
+
+	// attachedFiles holds paths queued by AttachFile, resolved (uploaded or
+	// read as text context) once SendWithMeta has a client to upload through.
+	attachedFiles []string
+
+	// fileRefs holds file attachments successfully uploaded via a
+	// FileUploader provider, to be referenced by ID in the request.
+	fileRefs []FileRef
+
 	// Schema enforcement
 	schema any
 
@@ -73,20 +164,61 @@ type Builder struct {
 	// Smart retry with backoff
 	retryConfig *RetryConfig
 
+	// onRetry is called before each re-attempt (smart or legacy retry),
+	// set via OnRetry. Useful for surfacing "retrying (attempt N)..." to a
+	// UI without waiting for the final result.
+	onRetry func(attempt int, err error)
+
+	// circuitBreaker, set via CircuitBreaker, opts this request into
+	// skipping models whose breaker (keyed by provider+model) is open.
+	circuitBreaker *CircuitBreakerConfig
+
 	// Validation / Guardrails
 	validators []Validator
+
+	// Post-processors applied to the final content after validation
+	transforms []func(string) (string, error)
+
+	// headers holds request-scoped HTTP headers set via Header, merged on
+	// top of ProviderConfig.Headers by the provider.
+	headers map[string]string
+
+	// endUser is the hashed end-user identifier set via EndUser.
+	endUser string
 }
 
 // New creates a new Builder instance for the specified model.
 // It initializes the builder with empty messages and default settings.
-func New(model Model) *Builder {
-	return &Builder{
+// Options (see WithProvider) are applied in order after those defaults, so
+// later options win if they touch the same setting.
+func New(model Model, opts ...Option) *Builder {
+	b := &Builder{
 		model:       model,
 		messages:    []Message{},
 		vars:        Vars{},
 		fileContext: []string{},
 		maxRetries:  0,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Option configures a Builder at construction time, for New's variadic
+// options. See WithProvider.
+type Option func(*Builder)
+
+// WithProvider selects the provider used for this builder instead of the
+// default client, configuring it with the given ClientOptions. Equivalent
+// to calling Builder.Provider after New, but composes into a single
+// expression:
+//
+//	b := New(ModelClaudeOpus, WithProvider(ProviderAnthropic, WithTimeout(30*time.Second)))
+func WithProvider(providerType ProviderType, opts ...ClientOption) Option {
+	return func(b *Builder) {
+		b.client = NewClient(providerType, opts...)
+	}
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -102,17 +234,72 @@ func (b *Builder) System(prompt string) *Builder {
 
 // SystemFile loads the system prompt from a file at the given path.
 // It reads the file content and sets it as the system prompt.
-// If the file cannot be read, it logs an error and leaves the system prompt unchanged.
+// If the file cannot be read, it records the error (see Err) and leaves the
+// system prompt unchanged.
 func (b *Builder) SystemFile(path string) *Builder {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Printf("%s Error loading prompt from %s: %v\n", colorRed("✗"), path, err)
+		b.recordLoadError(fmt.Errorf("loading prompt from %s: %w", path, err))
 		return b
 	}
 	b.system = string(data)
 	return b
 }
 
+// SystemFS loads the system prompt from path within fsys, the same as
+// SystemFile but reading through an fs.FS instead of the OS filesystem - for
+// example an embed.FS so prompts can be baked into a compiled binary rather
+// than shipped as loose files. If the file cannot be read, it records the
+// error (see Err) and leaves the system prompt unchanged.
+func (b *Builder) SystemFS(fsys fs.FS, path string) *Builder {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		b.recordLoadError(fmt.Errorf("loading prompt from %s: %w", path, err))
+		return b
+	}
+	b.system = string(data)
+	return b
+}
+
+// SystemFileAppend loads a file and appends it to the existing system
+// prompt, separated by a blank line, instead of replacing it. Use this
+// over SystemFile to layer a persona file with other system instructions
+// without one clobbering the other. If the file cannot be read, it records
+// the error (see Err) and leaves the system prompt unchanged.
+func (b *Builder) SystemFileAppend(path string) *Builder {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.recordLoadError(fmt.Errorf("loading prompt from %s: %w", path, err))
+		return b
+	}
+	return b.AppendSystem(string(data))
+}
+
+// AppendSystem adds text to the end of the system prompt, separated by a
+// blank line, rather than replacing whatever System or SystemFile already
+// set. Use this to layer dynamic instructions on top of a base persona.
+func (b *Builder) AppendSystem(text string) *Builder {
+	if b.system == "" {
+		b.system = text
+		return b
+	}
+	b.system = b.system + "\n\n" + text
+	return b
+}
+
+// PrependSystem adds text to the start of the system prompt, separated by
+// a blank line, rather than replacing whatever System or SystemFile
+// already set. Use this to put dynamic instructions ahead of a base
+// persona loaded earlier.
+func (b *Builder) PrependSystem(text string) *Builder {
+	if b.system == "" {
+		b.system = text
+		return b
+	}
+	b.system = text + "\n\n" + b.system
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Message Methods
 // ═══════════════════════════════════════════════════════════════════════════
@@ -124,6 +311,16 @@ func (b *Builder) User(content string) *Builder {
 	return b
 }
 
+// UserAs adds a user message labeled with name, distinguishing it from
+// other user turns. Use this for multi-persona simulations (e.g. "Alice"
+// and "Bob" both speaking as "user") that would otherwise collapse into
+// anonymous user messages. OpenAI and OpenRouter send name through to the
+// model; other providers ignore it.
+func (b *Builder) UserAs(name, content string) *Builder {
+	b.messages = append(b.messages, Message{Role: "user", Name: name, Content: content})
+	return b
+}
+
 // Assistant adds an assistant message to the conversation history.
 // This is used to provide context from previous turns or to pre-fill the assistant's response.
 func (b *Builder) Assistant(content string) *Builder {
@@ -131,6 +328,35 @@ func (b *Builder) Assistant(content string) *Builder {
 	return b
 }
 
+// AssistantToolCalls adds an assistant message that issued the given tool
+// calls, with no text content. Use this to reconstruct a saved multi-turn
+// tool conversation - e.g. one restored from disk across a process restart -
+// pairing it with ToolResult for each call's result before continuing the
+// conversation.
+func (b *Builder) AssistantToolCalls(calls ...ToolCall) *Builder {
+	b.messages = append(b.messages, Message{Role: "assistant", ToolCalls: calls})
+	return b
+}
+
+// Prefill seeds the start of the assistant's response, useful for steering
+// the model toward a specific format (e.g. starting with "{" to bias it
+// toward JSON). On Anthropic, which continues directly from a trailing
+// assistant message, text is sent that way and stitched back onto the
+// completion so the returned content reads as one continuous response. On
+// providers without prefill support, it falls back to a system instruction
+// telling the model to begin its response with text verbatim.
+func (b *Builder) Prefill(text string) *Builder {
+	b.prefill = text
+	return b
+}
+
+// ToolResult adds a tool-result message to the conversation history, reporting
+// the output of the call identified by callID back to the model.
+func (b *Builder) ToolResult(callID, content string) *Builder {
+	b.messages = append(b.messages, Message{Role: "tool", ToolCallID: callID, Content: content})
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Context Injection - Add files as context
 // ═══════════════════════════════════════════════════════════════════════════
@@ -142,7 +368,7 @@ func (b *Builder) Context(path string) *Builder {
 	if strings.Contains(path, "*") {
 		matches, err := filepath.Glob(path)
 		if err != nil {
-			fmt.Printf("%s Error with glob pattern %s: %v\n", colorRed("✗"), path, err)
+			b.recordLoadError(fmt.Errorf("glob pattern %s: %w", path, err))
 			return b
 		}
 		for _, match := range matches {
@@ -157,12 +383,60 @@ func (b *Builder) Context(path string) *Builder {
 func (b *Builder) addFileContext(path string) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		fmt.Printf("%s Error loading context from %s: %v\n", colorRed("✗"), path, err)
+		b.recordLoadError(fmt.Errorf("loading context from %s: %w", path, err))
+		return
+	}
+	b.fileContext = append(b.fileContext, fmt.Sprintf("--- %s ---\n%s", path, string(data)))
+}
+
+// ContextFS adds the content of a file (or files matching a glob pattern)
+// within fsys to the context, the same as Context but reading through an
+// fs.FS instead of the OS filesystem - for example an embed.FS so prompt
+// assets can be baked into a compiled binary rather than shipped as loose
+// files.
+func (b *Builder) ContextFS(fsys fs.FS, pattern string) *Builder {
+	if strings.Contains(pattern, "*") {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			b.recordLoadError(fmt.Errorf("glob pattern %s: %w", pattern, err))
+			return b
+		}
+		for _, match := range matches {
+			b.addFileContextFS(fsys, match)
+		}
+	} else {
+		b.addFileContextFS(fsys, pattern)
+	}
+	return b
+}
+
+func (b *Builder) addFileContextFS(fsys fs.FS, path string) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		b.recordLoadError(fmt.Errorf("loading context from %s: %w", path, err))
 		return
 	}
 	b.fileContext = append(b.fileContext, fmt.Sprintf("--- %s ---\n%s", path, string(data)))
 }
 
+// recordLoadError accumulates a file-load failure from SystemFile, SystemFS,
+// Context, ContextFS, or ContextWith, for Err/SendWithMeta to surface.
+func (b *Builder) recordLoadError(err error) {
+	b.loadErrs = append(b.loadErrs, err)
+}
+
+// Err returns a *FileLoadError describing every failed SystemFile, SystemFS,
+// Context, ContextFS, or ContextWith call made on this builder so far, or
+// nil if none failed. Check this before Send/SendWithMeta if you want to
+// fail fast on a bad path rather than letting SendWithMeta surface it as
+// meta.Error after building the request.
+func (b *Builder) Err() error {
+	if len(b.loadErrs) == 0 {
+		return nil
+	}
+	return &FileLoadError{Errors: b.loadErrs}
+}
+
 // ContextString adds a raw string as context with a given name.
 // This is useful for adding in-memory data or snippets as context.
 func (b *Builder) ContextString(name, content string) *Builder {
@@ -170,6 +444,182 @@ func (b *Builder) ContextString(name, content string) *Builder {
 	return b
 }
 
+// ContextOptions bounds and formats the content a single ContextWith call
+// injects, so a large file can't silently blow out the context window.
+type ContextOptions struct {
+	// MaxBytes truncates content larger than this many bytes, replacing the
+	// removed middle section with a "... [N bytes omitted] ..." marker.
+	// Zero means no limit.
+	MaxBytes int
+
+	// MaxTokens truncates content whose estimated token count (using the
+	// same estimator CountTokens uses) exceeds this many tokens, cutting on
+	// a token boundary rather than splitting mid-word or mid-rune, and
+	// replacing the removed middle section with a "... [N tokens omitted]
+	// ..." marker. Applied after MaxBytes, for a tighter and more accurate
+	// budget than a byte limit alone can give. Zero means no limit.
+	MaxTokens int
+
+	// HeadTail, if non-zero, keeps only the first and last N lines of the
+	// file (joined by an omission marker) instead of the full content.
+	// Applied before MaxBytes.
+	HeadTail int
+
+	// LineNumbers prefixes each line with its 1-based line number in the
+	// original file, before HeadTail/MaxBytes trim anything.
+	LineNumbers bool
+}
+
+// ContextWith adds the content of a single file to the context like
+// Context, but applies opts to bound its size and control formatting. Use
+// this instead of Context for files that might be large enough to overflow
+// the model's context window.
+func (b *Builder) ContextWith(path string, opts ContextOptions) *Builder {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.recordLoadError(fmt.Errorf("loading context from %s: %w", path, err))
+		return b
+	}
+	b.fileContext = append(b.fileContext, fmt.Sprintf("--- %s ---\n%s", path, formatContext(string(data), opts, b.model)))
+	return b
+}
+
+// ContextPlacement controls where injected file context is placed: at the
+// top of the system prompt (ContextBefore), as a separate trailing user
+// message (ContextAfter), or the default of appending it to the end of the
+// system prompt. Putting context ahead of instructions (ContextBefore) tends
+// to improve instruction adherence on long-context models.
+func (b *Builder) ContextPlacement(p ContextPlacement) *Builder {
+	b.contextPlacement = p
+	return b
+}
+
+// ContextLabel overrides the "# Context" header injected file context is
+// placed under.
+func (b *Builder) ContextLabel(label string) *Builder {
+	b.contextLabel = label
+	return b
+}
+
+// formatContext applies opts to content, in the order: number lines against
+// the original file, then select head/tail lines, then enforce MaxBytes,
+// then enforce MaxTokens against model's estimator.
+func formatContext(content string, opts ContextOptions, model Model) string {
+	if opts.LineNumbers {
+		content = addLineNumbers(content)
+	}
+	if opts.HeadTail > 0 {
+		content = headTailLines(content, opts.HeadTail)
+	}
+	if opts.MaxBytes > 0 && len(content) > opts.MaxBytes {
+		content = truncateBytes(content, opts.MaxBytes)
+	}
+	if opts.MaxTokens > 0 {
+		content = truncateTokens(content, opts.MaxTokens, model)
+	}
+	return content
+}
+
+// addLineNumbers prefixes every line of content with its 1-based line number.
+func addLineNumbers(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("%4d: %s", i+1, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// headTailLines keeps only the first and last n lines of content, joined by
+// a "... [N lines omitted] ..." marker. It's a no-op if content already has
+// 2n lines or fewer.
+func headTailLines(content string, n int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) <= 2*n {
+		return content
+	}
+	omitted := len(lines) - 2*n
+	head := strings.Join(lines[:n], "\n")
+	tail := strings.Join(lines[len(lines)-n:], "\n")
+	return fmt.Sprintf("%s\n... [%d lines omitted] ...\n%s", head, omitted, tail)
+}
+
+// truncateBytes keeps the first and last halves of maxBytes, joined by a
+// "... [N bytes omitted] ..." marker naming how many bytes were dropped.
+// It's a no-op if content already fits within maxBytes.
+func truncateBytes(content string, maxBytes int) string {
+	if len(content) <= maxBytes {
+		return content
+	}
+	keepHead := maxBytes / 2
+	keepTail := maxBytes - keepHead
+
+	// Snap both cut points outward to the nearest UTF-8 rune boundary so a
+	// multi-byte rune straddling the cut isn't split in half.
+	for keepHead > 0 && !utf8.RuneStart(content[keepHead]) {
+		keepHead--
+	}
+	tailStart := len(content) - keepTail
+	for tailStart < len(content) && !utf8.RuneStart(content[tailStart]) {
+		tailStart++
+	}
+
+	omitted := tailStart - keepHead
+	return fmt.Sprintf("%s\n... [%d bytes omitted] ...\n%s", content[:keepHead], omitted, content[tailStart:])
+}
+
+// truncateTokens keeps a head and tail portion of content within maxTokens
+// total, as estimated by EstimateTokens for model, joined by a "... [N
+// tokens omitted] ..." marker naming the estimated tokens dropped. Unlike
+// truncateBytes, the cut points fall on word/punctuation boundaries (the
+// same boundaries EstimateTokens' tiktoken-style splitting uses) rather
+// than an arbitrary byte offset, so it can't split a multibyte rune or a
+// token in half. It's a no-op if content already fits within maxTokens.
+func truncateTokens(content string, maxTokens int, model Model) string {
+	if EstimateTokens(content, model) <= maxTokens {
+		return content
+	}
+
+	bounds := tiktokenLikeToken.FindAllStringIndex(content, -1)
+	if len(bounds) == 0 {
+		return content
+	}
+
+	keepHead := maxTokens / 2
+	keepTail := maxTokens - keepHead
+
+	headEnd := 0
+	headTokens := 0
+	for _, m := range bounds {
+		n := EstimateTokens(content[m[0]:m[1]], model)
+		if headTokens+n > keepHead {
+			break
+		}
+		headTokens += n
+		headEnd = m[1]
+	}
+
+	tailStart := len(content)
+	tailTokens := 0
+	for i := len(bounds) - 1; i >= 0; i-- {
+		m := bounds[i]
+		if m[0] < headEnd {
+			break
+		}
+		n := EstimateTokens(content[m[0]:m[1]], model)
+		if tailTokens+n > keepTail {
+			break
+		}
+		tailTokens += n
+		tailStart = m[0]
+	}
+	if tailStart < headEnd {
+		tailStart = headEnd
+	}
+
+	omitted := EstimateTokens(content[headEnd:tailStart], model)
+	return fmt.Sprintf("%s\n... [%d tokens omitted] ...\n%s", content[:headEnd], omitted, content[tailStart:])
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Template Variables
 // ═══════════════════════════════════════════════════════════════════════════
@@ -184,11 +634,21 @@ func (b *Builder) With(vars Vars) *Builder {
 }
 
 // Var adds a single template variable to replace {{key}} with the value.
-func (b *Builder) Var(key, value string) *Builder {
+// value may be any type; see Vars for how non-string values render.
+func (b *Builder) Var(key string, value any) *Builder {
 	b.vars[key] = value
 	return b
 }
 
+// StrictVars makes SendWithMeta fail with a *MissingVarsError, instead of
+// silently sending an unresolved {{key}} placeholder to the model, when a
+// template variable referenced in the prompt has no value and no
+// :-fallback.
+func (b *Builder) StrictVars() *Builder {
+	b.strictVars = true
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Retry & Fallback
 // ═══════════════════════════════════════════════════════════════════════════
@@ -207,6 +667,16 @@ func (b *Builder) Fallback(models ...Model) *Builder {
 	return b
 }
 
+// FallbackTimeout caps how long each model attempt (primary or fallback)
+// gets before SendWithMeta moves on to the next one, instead of letting a
+// hung model eat the whole deadline set via Timeout/WithContext. Without
+// this, that remaining deadline is instead divided evenly across the
+// remaining attempts before each one starts.
+func (b *Builder) FallbackTimeout(d time.Duration) *Builder {
+	b.fallbackTimeout = d
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // JSON Mode
 // ═══════════════════════════════════════════════════════════════════════════
@@ -218,6 +688,19 @@ func (b *Builder) JSON() *Builder {
 	return b
 }
 
+// JSONMode enables JSON mode like JSON, but additionally controls whether
+// the "Respond with valid JSON only" system-prompt instruction is injected.
+// With native set, the instruction is skipped for any model whose
+// Capabilities() report native JSON support, relying solely on the
+// provider's response_format instead - useful when that instruction
+// conflicts with a carefully-worded system prompt. Models without native
+// support still get the instruction injected as a fallback.
+func (b *Builder) JSONMode(native bool) *Builder {
+	b.jsonMode = true
+	b.jsonNative = native
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Temperature
 // ═══════════════════════════════════════════════════════════════════════════
@@ -254,6 +737,93 @@ func (b *Builder) ThinkMedium() *Builder { return b.Thinking(ThinkingMedium) }
 // ThinkHigh sets thinking to high effort.
 func (b *Builder) ThinkHigh() *Builder { return b.Thinking(ThinkingHigh) }
 
+// ThinkingBudget sets an exact reasoning token budget rather than a coarse
+// effort level. Providers that take a token budget directly (Anthropic,
+// Gemini) spend exactly tokens on reasoning. OpenAI has no token-budget
+// parameter, so it maps tokens to the nearest effort level instead - this
+// also sets that level as a fallback for any other provider that doesn't
+// support an explicit budget.
+func (b *Builder) ThinkingBudget(tokens int) *Builder {
+	b.thinkingBudget = tokens
+	b.thinking = thinkingLevelForBudget(tokens)
+	return b
+}
+
+// thinkingLevelForBudget maps a token budget to the nearest coarse effort
+// level, for providers (OpenAI) that only accept minimal/low/medium/high.
+// The thresholds mirror the fixed budgets Anthropic's own low/medium/high
+// levels already use (1024/4096/16384), so switching a provider mid-chain
+// doesn't change the resulting effort bucket.
+func thinkingLevelForBudget(tokens int) ThinkingLevel {
+	switch {
+	case tokens <= 0:
+		return ThinkingNone
+	case tokens <= 1024:
+		return ThinkingLow
+	case tokens <= 4096:
+		return ThinkingMedium
+	default:
+		return ThinkingHigh
+	}
+}
+
+// ReasoningSummary requests a reasoning summary from OpenAI's Responses API
+// at the given verbosity: "auto", "concise", or "detailed". Combined with
+// Thinking, this surfaces a summary of the model's reasoning on
+// ResponseMeta.Reasoning instead of just the final answer.
+func (b *Builder) ReasoningSummary(level string) *Builder {
+	b.reasoningSummary = level
+	return b
+}
+
+// Verbosity sets gpt-5.1+'s answer length ("low", "medium", or "high"),
+// independent of max tokens. Use this for terse answers without capping the
+// model's max_tokens (and risking it getting cut off mid-thought).
+func (b *Builder) Verbosity(level string) *Builder {
+	b.verbosity = level
+	return b
+}
+
+// ServiceTier requests OpenAI's "auto", "default", "flex", or "priority"
+// service tier, trading latency for cost. "flex" cuts cost for latency-
+// tolerant batch work; "priority" pays more for faster, more consistent
+// latency. The tier actually used (OpenAI can downgrade it) is reported
+// back on ResponseMeta.ServiceTier. Ignored by providers other than OpenAI.
+func (b *Builder) ServiceTier(tier string) *Builder {
+	b.serviceTier = tier
+	return b
+}
+
+// SafetySettings overrides Gemini's default content-safety thresholds, per
+// harm category (harassment, hate speech, sexually explicit, dangerous
+// content). Requests that trip a threshold come back as a distinct
+// ErrBlockedBySafety error instead of empty content. Ignored by providers
+// other than Google.
+func (b *Builder) SafetySettings(settings ...SafetySetting) *Builder {
+	b.safetySettings = settings
+	return b
+}
+
+// Metadata attaches arbitrary key/value tags to the request, sent as
+// OpenAI's and Anthropic's "metadata" field and echoed back in their
+// dashboards and webhooks. Useful for correlating usage exports with your
+// own logs (e.g. a tenant ID and trace ID). Ignored by providers that don't
+// support request metadata.
+func (b *Builder) Metadata(kv map[string]string) *Builder {
+	b.metadata = kv
+	return b
+}
+
+// InputItems appends pre-built Responses API input items to the request,
+// sent after the conversation history. Use it to post the result of a tool
+// call back to the model - e.g. FunctionCallOutput{...}.InputItem(),
+// ComputerCallOutput{...}.InputItem() - so a multi-turn tool conversation
+// can continue. Ignored by providers other than OpenAI's Responses API.
+func (b *Builder) InputItems(items ...any) *Builder {
+	b.inputItems = append(b.inputItems, items...)
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Debug Mode
 // ═══════════════════════════════════════════════════════════════════════════
@@ -265,12 +835,42 @@ func (b *Builder) Debug() *Builder {
 	return b
 }
 
+// CaptureRaw makes SendWithMeta populate ResponseMeta.Raw with the
+// provider's unparsed response body, for debugging responses that don't
+// parse the way you expect. It's opt-in so large bodies aren't retained by
+// default.
+func (b *Builder) CaptureRaw() *Builder {
+	b.captureRaw = true
+	return b
+}
+
+// Header sets a request-scoped HTTP header (e.g. "X-Request-Id" or an
+// end-user tracking header), sent alongside this request only. It wins over
+// any header set at the client/provider level via ProviderConfig.Headers,
+// avoiding the need to construct a new client just to change one header.
+func (b *Builder) Header(key, value string) *Builder {
+	if b.headers == nil {
+		b.headers = make(map[string]string)
+	}
+	b.headers[key] = value
+	return b
+}
+
+// EndUser sets a stable end-user identifier, sent as OpenAI's
+// "safety_identifier" and Anthropic's "metadata.user_id" to aid abuse
+// detection and per-user rate limiting. id is hashed with SHA-256 before
+// being sent, so the raw identifier never leaves this process.
+func (b *Builder) EndUser(id string) *Builder {
+	b.endUser = hashEndUserID(id)
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Execution
 // ═══════════════════════════════════════════════════════════════════════════
 
 // buildMessages constructs the final message list by processing templates and context.
-func (b *Builder) buildMessages() []Message {
+func (b *Builder) buildMessages(providerType ProviderType) []Message {
 	var msgs []Message
 
 	// Build system message
@@ -279,21 +879,49 @@ func (b *Builder) buildMessages() []Message {
 		system = applyTemplate(system, b.vars)
 	}
 
-	// Add JSON instruction if enabled
-	if b.jsonMode && system != "" {
-		system += "\n\nIMPORTANT: Respond with valid JSON only. No markdown, no explanation."
-	} else if b.jsonMode {
-		system = "Respond with valid JSON only. No markdown, no explanation."
+	// Add JSON instruction if enabled, unless JSONMode(true) was used and
+	// the model natively supports JSON mode - the provider's
+	// response_format alone is then enough.
+	if b.jsonMode && !(b.jsonNative && Capabilities(b.model).JSON) {
+		if system != "" {
+			system += "\n\nIMPORTANT: Respond with valid JSON only. No markdown, no explanation."
+		} else {
+			system = "Respond with valid JSON only. No markdown, no explanation."
+		}
+	}
+
+	// Providers other than Anthropic have no native prefill, so fold it
+	// into a system instruction instead. Anthropic's case is handled below
+	// by appending a trailing assistant message once msgs is built.
+	if b.prefill != "" && providerType != ProviderAnthropic {
+		system += fmt.Sprintf("\n\nIMPORTANT: Begin your response with exactly the following text, verbatim, then continue naturally from it:\n%s", b.prefill)
+	}
+
+	contextLabel := b.contextLabel
+	if contextLabel == "" {
+		contextLabel = "# Context"
 	}
 
-	// Add context to system if present
-	if len(b.fileContext) > 0 {
-		contextStr := "\n\n# Context\n" + strings.Join(b.fileContext, "\n\n")
-		system += contextStr
+	// Add context to the system prompt, positioned per contextPlacement.
+	// ContextAfter is injected later as a trailing user message instead,
+	// once the conversation history is built.
+	if len(b.fileContext) > 0 && b.contextPlacement != ContextAfter {
+		contextBlock := contextLabel + "\n" + strings.Join(b.fileContext, "\n\n")
+		if b.contextPlacement == ContextBefore {
+			if system == "" {
+				system = contextBlock
+			} else {
+				system = contextBlock + "\n\n" + system
+			}
+		} else if system == "" {
+			system = contextBlock
+		} else {
+			system += "\n\n" + contextBlock
+		}
 	}
 
 	if system != "" {
-		msgs = append(msgs, Message{Role: "system", Content: system})
+		msgs = append(msgs, Message{Role: systemMessageRole(b.model), Content: system})
 	}
 
 	// Add user/assistant messages with template vars applied
@@ -302,11 +930,18 @@ func (b *Builder) buildMessages() []Message {
 		if str, ok := content.(string); ok && len(b.vars) > 0 {
 			content = applyTemplate(str, b.vars)
 		}
-		msgs = append(msgs, Message{Role: m.Role, Content: content, ToolCalls: m.ToolCalls, ToolCallID: m.ToolCallID})
+		msgs = append(msgs, Message{Role: m.Role, Name: m.Name, Content: content, ToolCalls: m.ToolCalls, ToolCallID: m.ToolCallID})
+	}
+
+	// ContextAfter puts file context in its own trailing user message,
+	// after the system prompt and the whole conversation history.
+	if len(b.fileContext) > 0 && b.contextPlacement == ContextAfter {
+		contextStr := contextLabel + "\n" + strings.Join(b.fileContext, "\n\n")
+		msgs = append(msgs, Message{Role: "user", Content: contextStr})
 	}
 
-	// If we have images or documents, convert the last user message to multimodal
-	if (len(b.images) > 0 || len(b.documents) > 0) && len(msgs) > 0 {
+	// If we have images, documents, or audio, convert the last user message to multimodal
+	if (len(b.images) > 0 || len(b.documents) > 0 || len(b.fileRefs) > 0 || len(b.audios) > 0) && len(msgs) > 0 {
 		// Find last user message
 		for i := len(msgs) - 1; i >= 0; i-- {
 			if msgs[i].Role == "user" {
@@ -342,15 +977,169 @@ func (b *Builder) buildMessages() []Message {
 					})
 				}
 
+				// Add uploaded file attachments
+				for _, ref := range b.fileRefs {
+					parts = append(parts, ContentPart{
+						Type: "file",
+						File: &FileRef{FileID: ref.FileID, Name: ref.Name},
+					})
+				}
+
+				// Add audio
+				for _, a := range b.audios {
+					parts = append(parts, audioContentPart(a))
+				}
+
 				msgs[i].Content = parts
 				break
 			}
 		}
 	}
 
+	// Anthropic continues generation directly from a trailing assistant
+	// message, so this has to be the last message in the request.
+	if b.prefill != "" && providerType == ProviderAnthropic {
+		msgs = append(msgs, Message{Role: "assistant", Content: b.prefill})
+	}
+
 	return msgs
 }
 
+// Render resolves template substitution, context injection, and the JSON
+// instruction exactly as SendWithMeta would, and returns the resulting
+// messages without sending anything to a provider. It's meant for inspecting
+// or testing prompt logic, and for a CLI's --dry-run mode. If StrictVars is
+// set and a {{key}} placeholder is left unresolved, it returns
+// *MissingVarsError instead of the messages.
+func (b *Builder) Render() ([]Message, error) {
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	msgs := b.buildMessages(client.providerType)
+
+	if b.strictVars {
+		if missing := missingTemplateVars(msgs); len(missing) > 0 {
+			return nil, &MissingVarsError{Keys: missing}
+		}
+	}
+
+	return msgs, nil
+}
+
+// buildProviderRequest assembles the provider-agnostic request for model
+// against msgs, from the options accumulated on b. Shared by the
+// SendWithMeta fallback loop and DryRun.
+func (b *Builder) buildProviderRequest(model Model, msgs []Message) *ProviderRequest {
+	req := &ProviderRequest{
+		Model:                string(model),
+		Messages:             msgs,
+		Temperature:          b.temperature,
+		Thinking:             b.thinking,
+		ThinkingBudgetTokens: b.thinkingBudget,
+		Tools:                b.tools,
+		BuiltinTools:         b.builtinTools,
+		JSONMode:             b.jsonMode,
+		Schema:               b.schema,
+		CaptureRaw:           b.captureRaw,
+		Headers:              b.headers,
+		EndUser:              b.endUser,
+		ReasoningSummary:     b.reasoningSummary,
+		Verbosity:            b.verbosity,
+		ServiceTier:          b.serviceTier,
+		SafetySettings:       b.safetySettings,
+		Metadata:             b.metadata,
+		InputItems:           b.inputItems,
+	}
+	if b.forceTool != "" {
+		req.ToolChoice = b.forceTool
+	}
+	return req
+}
+
+// degradeThinkingForFallback clears req.Thinking (and the ReasoningSummary
+// that rides with it) if model's capabilities say it doesn't support
+// reasoning, logging what was dropped in Debug. Models not in the registry
+// are assumed not to support it, since sending Thinking to an unknown model
+// is the more likely way to provoke a 400.
+func degradeThinkingForFallback(req *ProviderRequest, model Model) {
+	if req.Thinking == "" && req.ReasoningSummary == "" {
+		return
+	}
+	if caps, ok := GetModelCapabilities(model); ok && caps.Thinking {
+		return
+	}
+
+	if Debug {
+		fmt.Printf("%s Dropping thinking/reasoning params for fallback model %s: not supported\n", colorYellow("⚠"), model)
+	}
+	req.Thinking = ""
+	req.ReasoningSummary = ""
+}
+
+// DryRun builds the ProviderRequest and the provider-specific JSON body that
+// would be sent for this request, without sending it, via the same
+// buildRequest path each provider's Send uses. It's useful for inspecting
+// model resolution, tool serialization, and thinking/effort mapping in
+// tests. Like Render, it respects StrictVars.
+func (b *Builder) DryRun() (*ProviderRequest, string, error) {
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	msgs := b.buildMessages(client.providerType)
+	if b.strictVars {
+		if missing := missingTemplateVars(msgs); len(missing) > 0 {
+			return nil, "", &MissingVarsError{Keys: missing}
+		}
+	}
+
+	req := b.buildProviderRequest(b.model, msgs)
+
+	previewer, ok := client.provider.(RequestPreviewer)
+	if !ok {
+		return req, "", fmt.Errorf("provider %s does not support dry-run previews", client.provider.Name())
+	}
+
+	body, err := json.Marshal(previewer.BuildRawRequest(req))
+	if err != nil {
+		return req, "", err
+	}
+
+	return req, string(body), nil
+}
+
+// missingTemplateVars collects the keys of every unresolved {{key}}
+// placeholder across msgs' text content, for Builder.StrictVars.
+func missingTemplateVars(msgs []Message) []string {
+	var missing []string
+	for _, m := range msgs {
+		switch content := m.Content.(type) {
+		case string:
+			missing = append(missing, unresolvedTemplateVars(content)...)
+		case []ContentPart:
+			for _, p := range content {
+				missing = append(missing, unresolvedTemplateVars(p.Text)...)
+			}
+		}
+	}
+	return missing
+}
+
+// hasNonSystemMessage reports whether msgs contains at least one message
+// that isn't the system/developer prompt, for SendWithMeta's empty-request
+// guard.
+func hasNonSystemMessage(msgs []Message) bool {
+	for _, m := range msgs {
+		if m.Role != "system" && m.Role != "developer" {
+			return true
+		}
+	}
+	return false
+}
+
 // Send executes the request and returns the response content as a string.
 // It handles retries, fallbacks, and error handling as configured.
 func (b *Builder) Send() (string, error) {
@@ -387,12 +1176,119 @@ type ResponseMeta struct {
 	// Responses API output (populated when using built-in tools)
 	// Contains citations, sources, and tool call details
 	ResponsesOutput *ResponsesOutput
+
+	// FinishReason indicates why the model stopped generating (e.g. "stop", "length", "tool_calls").
+	FinishReason string
+
+	// ServedModel is the upstream model ID the provider actually used, when it
+	// differs from Model (e.g. OpenRouter routing the request to a fallback).
+	// Empty if the provider doesn't report one.
+	ServedModel string
+
+	// Reasoning holds the provider's reasoning/thinking summary, when one
+	// was surfaced by the model and API. Empty if the provider or model
+	// doesn't report one. Intended for auditing, not end-user display.
+	Reasoning string
+
+	// Raw holds the provider's unparsed response body, populated only when
+	// the request used Builder.CaptureRaw. Nil otherwise.
+	Raw json.RawMessage
+
+	// Cached reports whether Content was served from the local response
+	// cache (see the Cache global) instead of a live provider call.
+	Cached bool
+
+	// CachedTokens is the number of prompt tokens the provider itself
+	// served from its prompt cache, e.g. OpenAI's
+	// usage.prompt_tokens_details.cached_tokens or Anthropic's
+	// cache_read_input_tokens. 0 if the provider doesn't report one or
+	// didn't hit its cache. Distinct from Cached, which reflects this
+	// library's own local cache.
+	CachedTokens int
+
+	// ReasoningTokens is the portion of CompletionTokens spent on internal
+	// reasoning rather than visible output, e.g. OpenAI's
+	// usage.completion_tokens_details.reasoning_tokens. Billed the same as
+	// other completion tokens, but worth separating out for cost estimation
+	// and for comparing e.g. ThinkLow against ThinkHigh. 0 if the provider
+	// doesn't report one.
+	ReasoningTokens int
+
+	// AudioOutput holds audio the model returned alongside (or instead of)
+	// text, for audio-capable chat models. Nil unless the provider's
+	// response included one.
+	AudioOutput *AudioOutput
+
+	// RateLimit holds the rate-limit budget the provider reported alongside
+	// this response. Nil if the provider didn't report one.
+	RateLimit *RateLimitInfo
+
+	// ServiceTier is the service tier OpenAI actually used, which can
+	// differ from the one requested via Builder.ServiceTier if OpenAI
+	// downgraded it. Empty if the provider doesn't report one.
+	ServiceTier string
+
+	// ToolCalls holds the function calls the model made, assembled from
+	// streamed fragments. Populated by Builder.StreamWithToolDeltas; nil
+	// otherwise.
+	ToolCalls []ToolCall
+}
+
+// RequiresToolCall reports whether the model stopped in order to request a
+// tool call rather than because it finished answering. When true, an empty
+// Content is expected, not a failure - callers building their own tool loop
+// on top of SendWithMeta (rather than using RunTools) should check this
+// before treating an empty Content as an error.
+func (m *ResponseMeta) RequiresToolCall() bool {
+	return m.FinishReason == "tool_calls"
+}
+
+// Truncated reports whether the response was cut off by the model's output
+// length cap rather than finishing naturally, so UIs can show a "response
+// truncated" notice. See Builder.AutoContinue for automatically resuming a
+// truncated response.
+func (m *ResponseMeta) Truncated() bool {
+	return m.FinishReason == "length"
+}
+
+// Text returns the Responses API text output, or "" if ResponsesOutput is
+// nil. For most requests this duplicates Content; it's provided so callers
+// working with ResponsesOutput don't need to nil-check it themselves.
+func (m *ResponseMeta) Text() string {
+	if m.ResponsesOutput == nil {
+		return ""
+	}
+	return m.ResponsesOutput.Text
+}
+
+// Citations returns the Responses API citations, or nil if ResponsesOutput
+// is nil or the response didn't include any.
+func (m *ResponseMeta) Citations() []Citation {
+	if m.ResponsesOutput == nil {
+		return nil
+	}
+	return m.ResponsesOutput.Citations
+}
+
+// ToolCallsByType returns the Responses API tool calls of the given type
+// (e.g. "web_search_call", "file_search_call"), or nil if ResponsesOutput is
+// nil or none match.
+func (m *ResponseMeta) ToolCallsByType(t string) []ResponsesToolCall {
+	if m.ResponsesOutput == nil {
+		return nil
+	}
+	var matched []ResponsesToolCall
+	for _, tc := range m.ResponsesOutput.ToolCalls {
+		if tc.Type == t {
+			matched = append(matched, tc)
+		}
+	}
+	return matched
 }
 
 // SendWithMeta executes the request and returns the response with full metadata.
 // This includes token usage, latency, and the specific model used.
 func (b *Builder) SendWithMeta() *ResponseMeta {
-	msgs := b.buildMessages()
 	start := time.Now()
 
 	// Enable debug for this request if set
@@ -411,21 +1307,111 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 	// Get context
 	ctx := b.getContext()
 
+	// Upload (or inject as text context) any files queued by AttachFile
+	// before building messages, so the upload result can be referenced and
+	// any failures are caught by the b.Err() check below.
+	if len(b.attachedFiles) > 0 {
+		b.resolveAttachedFiles(ctx, client)
+	}
+
+	if err := b.Err(); err != nil {
+		return &ResponseMeta{Error: err, Model: b.model}
+	}
+
+	msgs := b.buildMessages(client.providerType)
+
+	if b.system == "" && !hasNonSystemMessage(msgs) {
+		return &ResponseMeta{
+			Error: &ProviderError{Provider: client.provider.Name(), Message: "no user message provided"},
+			Model: b.model,
+		}
+	}
+
+	if b.strictVars {
+		if missing := missingTemplateVars(msgs); len(missing) > 0 {
+			return &ResponseMeta{Error: &MissingVarsError{Keys: missing}, Model: b.model}
+		}
+	}
+
+	cacheOpts := SendOptions{Temperature: b.temperature, Thinking: b.thinking}
+	if cached, ok := getCached(b.model, msgs, cacheOpts); ok {
+		if Debug {
+			printDebugCacheHit()
+		}
+		return &ResponseMeta{Content: cached, Model: b.model, Cached: true, Latency: time.Since(start)}
+	}
+
 	// Try primary model with fallbacks
 	models := append([]Model{b.model}, b.fallbacks...)
 	var lastErr error
 	var totalRetries int
 
-	for _, model := range models {
+	for i, model := range models {
+		// Skip fallback models that can't satisfy what this request needs
+		// (e.g. an Anthropic model when WebSearch() was requested). The
+		// primary model is never skipped this way - a capability mismatch on
+		// it should surface normally rather than be silently swallowed.
+		if model != b.model {
+			if reason := capabilityMismatch(model, b); reason != "" {
+				if Debug {
+					fmt.Printf("%s Skipping fallback model %s: %s\n", colorYellow("⚠"), model, reason)
+				}
+				lastErr = fmt.Errorf("model %s does not support %s", model, reason)
+				continue
+			}
+		}
+
+		// Skip models whose circuit breaker is open, moving straight to the
+		// next fallback instead of paying the cost of a call very likely to
+		// fail.
+		var breaker *circuitBreaker
+		if b.circuitBreaker != nil {
+			breaker = circuitBreakerFor(client.provider.Name(), string(model))
+			if !breaker.allow(b.circuitBreaker) {
+				if Debug {
+					fmt.Printf("%s Skipping model %s: circuit breaker open\n", colorYellow("⚠"), model)
+				}
+				lastErr = &CircuitOpenError{Provider: client.provider.Name(), Model: string(model)}
+				continue
+			}
+		}
+
+		// Budget this attempt so a hung model can't eat the time remaining
+		// fallbacks need. With FallbackTimeout set, each attempt gets that
+		// fixed cap (clamped to whatever's left on the deadline). Otherwise,
+		// if a deadline is set, split what's left evenly across this and
+		// the remaining attempts.
+		attemptCtx := ctx
+		var cancelAttempt gocontext.CancelFunc
+		if len(b.fallbacks) > 0 {
+			if deadline, ok := ctx.Deadline(); ok {
+				remaining := time.Until(deadline)
+				if remaining <= 0 {
+					lastErr = ctx.Err()
+					break
+				}
+				budget := remaining / time.Duration(len(models)-i)
+				if b.fallbackTimeout > 0 {
+					budget = b.fallbackTimeout
+					if budget > remaining {
+						budget = remaining
+					}
+				}
+				attemptCtx, cancelAttempt = gocontext.WithTimeout(ctx, budget)
+			} else if b.fallbackTimeout > 0 {
+				attemptCtx, cancelAttempt = gocontext.WithTimeout(ctx, b.fallbackTimeout)
+			}
+		}
+
 		// Build provider request
-		req := &ProviderRequest{
-			Model:        string(model),
-			Messages:     msgs,
-			Temperature:  b.temperature,
-			Thinking:     b.thinking,
-			Tools:        b.tools,
-			BuiltinTools: b.builtinTools,
-			JSONMode:     b.jsonMode,
+		req := b.buildProviderRequest(model, msgs)
+
+		// Falling back from a reasoning model to a non-reasoning one makes
+		// Thinking meaningless - and some providers reject it outright - so
+		// strip it (and the reasoning-only knobs that ride with it) instead
+		// of letting the fallback fail for an avoidable parameter reason.
+		if model != b.model {
+			degradeThinkingForFallback(req, model)
 		}
 
 		// Check capability warnings
@@ -468,16 +1454,21 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 		if b.retryConfig != nil {
 			// Smart retry with exponential backoff + jitter
 			var retries int
-			resp, err = WithRetry(ctx, b.retryConfig, func() (*ProviderResponse, error) {
+			var lastRetryErr error
+			resp, err = WithRetry(attemptCtx, b.retryConfig, func() (*ProviderResponse, error) {
 				retries++
 				if retries > 1 {
 					totalRetries++
+					if b.onRetry != nil {
+						b.onRetry(retries-1, lastRetryErr)
+					}
 				}
 				invokeBeforeRequest(model, msgs)
 				waitForRateLimit()
-				r, e := client.provider.Send(ctx, req)
+				r, e := client.provider.Send(attemptCtx, req)
 				if e != nil {
 					invokeOnError(model, e)
+					lastRetryErr = e
 				}
 				return r, e
 			})
@@ -486,11 +1477,14 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 			for attempt := 0; attempt <= b.maxRetries; attempt++ {
 				if attempt > 0 {
 					totalRetries++
+					if b.onRetry != nil {
+						b.onRetry(attempt, err)
+					}
 					time.Sleep(time.Duration(attempt*attempt) * 100 * time.Millisecond)
 				}
 				invokeBeforeRequest(model, msgs)
 				waitForRateLimit()
-				resp, err = client.provider.Send(ctx, req)
+				resp, err = client.provider.Send(attemptCtx, req)
 				if err == nil {
 					break
 				}
@@ -500,15 +1494,30 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 			// No retry
 			invokeBeforeRequest(model, msgs)
 			waitForRateLimit()
-			resp, err = client.provider.Send(ctx, req)
+			resp, err = client.provider.Send(attemptCtx, req)
 			if err != nil {
 				invokeOnError(model, err)
 			}
 		}
 
+		if cancelAttempt != nil {
+			cancelAttempt()
+		}
+
+		if breaker != nil {
+			if err == nil {
+				breaker.recordSuccess()
+			} else {
+				breaker.recordFailure(b.circuitBreaker)
+			}
+		}
+
 		if err == nil {
 			// Validate response if validators configured (and apply any content filters)
 			content := resp.Content
+			if b.prefill != "" && client.providerType == ProviderAnthropic {
+				content = b.prefill + content
+			}
 			if len(b.validators) > 0 {
 				validated, validationErr := b.runValidators(content)
 				if validationErr != nil {
@@ -523,6 +1532,20 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 				content = validated
 			}
 
+			if len(b.transforms) > 0 {
+				transformed, transformErr := b.runTransforms(content)
+				if transformErr != nil {
+					invokeOnError(model, transformErr)
+					return &ResponseMeta{
+						Error:   transformErr,
+						Model:   model,
+						Latency: time.Since(start),
+						Retries: totalRetries,
+					}
+				}
+				content = transformed
+			}
+
 			meta := &ResponseMeta{
 				Content:          content,
 				Model:            model,
@@ -532,8 +1555,19 @@ func (b *Builder) SendWithMeta() *ResponseMeta {
 				PromptTokens:     resp.PromptTokens,
 				CompletionTokens: resp.CompletionTokens,
 				ResponsesOutput:  resp.ResponsesOutput,
+				FinishReason:     resp.FinishReason,
+				ServedModel:      resp.ServedModel,
+				Reasoning:        resp.Reasoning,
+				Raw:              resp.Raw,
+				CachedTokens:     resp.CachedTokens,
+				ReasoningTokens:  resp.ReasoningTokens,
+				AudioOutput:      resp.AudioOutput,
+				RateLimit:        resp.RateLimit,
+				ServiceTier:      resp.ServiceTier,
 			}
 
+			setCached(model, msgs, cacheOpts, content)
+
 			if Pretty {
 				printPrettyResponse(model, content)
 			}
@@ -557,19 +1591,22 @@ func (b *Builder) Ask(prompt string) (string, error) {
 	return b.User(prompt).Send()
 }
 
-// AskJSON sends a request and attempts to unmarshal the JSON response into the target struct.
-// It automatically enables JSON mode and strips code blocks from the response.
+// AskJSON sends a request and attempts to unmarshal the JSON response into
+// the target struct. It automatically enables JSON mode and, unless
+// StrictJSON was set, strips markdown code blocks and extracts the first
+// balanced JSON object/array from the response - tolerating a leading or
+// trailing sentence of prose and trailing commas - before decoding.
 func (b *Builder) AskJSON(prompt string, target any) error {
 	resp, err := b.JSON().User(prompt).Send()
 	if err != nil {
 		return err
 	}
 
-	// Clean response (remove markdown code blocks if present)
-	resp = strings.TrimPrefix(resp, "```json")
-	resp = strings.TrimPrefix(resp, "```")
-	resp = strings.TrimSuffix(resp, "```")
-	resp = strings.TrimSpace(resp)
+	if b.strictJSONDecode {
+		resp = cleanJSONResponse(resp)
+	} else {
+		resp = lenientJSON(resp)
+	}
 
 	return json.Unmarshal([]byte(resp), target)
 }
@@ -632,6 +1669,41 @@ func (b *Builder) Provider(providerType ProviderType) *Builder {
 	return b
 }
 
+// AutoProvider switches to the provider implied by this builder's model
+// namespace (e.g. "anthropic/claude-opus-4.5" -> ProviderAnthropic), instead
+// of requiring an explicit Provider call. This catches the common mistake of
+// picking a Claude or Gemini model while still pointed at the default
+// (OpenAI) client. If the model's namespace doesn't match a provider this
+// package implements directly, the client is left unchanged.
+func (b *Builder) AutoProvider() *Builder {
+	if providerType, ok := detectProviderType(b.model); ok {
+		b.client = NewClient(providerType)
+	}
+	return b
+}
+
+// detectProviderType infers the ProviderType implied by m's "namespace/model"
+// form, the convention every Model constant in this package follows (e.g.
+// ModelClaudeOpus = "anthropic/claude-opus-4.5"). Models namespaced under a
+// family this package has no direct provider for (e.g. "x-ai/grok-...")
+// report ok=false; route those through ProviderOpenRouter explicitly instead.
+func detectProviderType(m Model) (ProviderType, bool) {
+	namespace, _, ok := strings.Cut(string(m), "/")
+	if !ok {
+		return "", false
+	}
+	switch namespace {
+	case "openai":
+		return ProviderOpenAI, true
+	case "anthropic":
+		return ProviderAnthropic, true
+	case "google":
+		return ProviderGoogle, true
+	default:
+		return "", false
+	}
+}
+
 // GetClient returns the current client associated with the builder.
 // It returns nil if the default client is being used.
 func (b *Builder) GetClient() *Client {
@@ -647,25 +1719,33 @@ func (b *Builder) Clone() *Builder {
 		tempCopy = &v
 	}
 	newB := &Builder{
-		model:        b.model,
-		system:       b.system,
-		messages:     make([]Message, len(b.messages)),
-		vars:         make(Vars),
-		fileContext:  make([]string, len(b.fileContext)),
-		debug:        b.debug,
-		maxRetries:   b.maxRetries,
-		fallbacks:    make([]Model, len(b.fallbacks)),
-		jsonMode:     b.jsonMode,
-		temperature:  tempCopy,
-		thinking:     b.thinking,
-		tools:        make([]Tool, len(b.tools)),
-		builtinTools: make([]BuiltinTool, len(b.builtinTools)),
-		images:       make([]ImageInput, len(b.images)),
-		documents:    make([]DocumentInput, len(b.documents)),
-		client:       b.client,
-		ctx:          b.ctx,
-		retryConfig:  b.retryConfig,
-		validators:   make([]Validator, len(b.validators)),
+		model:            b.model,
+		system:           b.system,
+		messages:         make([]Message, len(b.messages)),
+		vars:             make(Vars),
+		fileContext:      make([]string, len(b.fileContext)),
+		debug:            b.debug,
+		maxRetries:       b.maxRetries,
+		fallbacks:        make([]Model, len(b.fallbacks)),
+		jsonMode:         b.jsonMode,
+		jsonNative:       b.jsonNative,
+		strictJSONDecode: b.strictJSONDecode,
+		temperature:      tempCopy,
+		thinking:         b.thinking,
+		thinkingBudget:   b.thinkingBudget,
+		tools:            make([]Tool, len(b.tools)),
+		builtinTools:     make([]BuiltinTool, len(b.builtinTools)),
+		images:           make([]ImageInput, len(b.images)),
+		documents:        make([]DocumentInput, len(b.documents)),
+		audios:           make([]AudioInput, len(b.audios)),
+		attachedFiles:    make([]string, len(b.attachedFiles)),
+		fileRefs:         make([]FileRef, len(b.fileRefs)),
+		schema:           b.schema,
+		client:           b.client,
+		ctx:              b.ctx,
+		retryConfig:      b.retryConfig,
+		validators:       make([]Validator, len(b.validators)),
+		transforms:       make([]func(string) (string, error), len(b.transforms)),
 	}
 	copy(newB.messages, b.messages)
 	copy(newB.fileContext, b.fileContext)
@@ -674,7 +1754,11 @@ func (b *Builder) Clone() *Builder {
 	copy(newB.builtinTools, b.builtinTools)
 	copy(newB.images, b.images)
 	copy(newB.documents, b.documents)
+	copy(newB.audios, b.audios)
+	copy(newB.attachedFiles, b.attachedFiles)
+	copy(newB.fileRefs, b.fileRefs)
 	copy(newB.validators, b.validators)
+	copy(newB.transforms, b.transforms)
 	maps.Copy(newB.vars, b.vars)
 	if b.toolHandlers != nil {
 		newB.toolHandlers = make(map[string]ToolHandler)
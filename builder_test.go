@@ -1,8 +1,18 @@
 package ai
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+	"unicode/utf8"
 )
 
 func TestNewBuilder(t *testing.T) {
@@ -28,6 +38,25 @@ func TestNewBuilder(t *testing.T) {
 	}
 }
 
+func TestNewBuilder_WithProviderOption_ConfiguresClient(t *testing.T) {
+	b := New(ModelClaudeOpus, WithProvider(ProviderAnthropic, WithAPIKey("sk-ant-test")))
+
+	if b.client == nil {
+		t.Fatal("expected WithProvider to set a client")
+	}
+	if b.client.providerType != ProviderAnthropic {
+		t.Errorf("expected provider type %q, got %q", ProviderAnthropic, b.client.providerType)
+	}
+}
+
+func TestNewBuilder_NoOptions_MatchesZeroOptionBehavior(t *testing.T) {
+	b := New(ModelGPT5)
+
+	if b.client != nil {
+		t.Error("expected nil client when no options are given")
+	}
+}
+
 func TestBuilderSystem(t *testing.T) {
 	b := New(ModelGPT5).System("You are helpful")
 
@@ -46,6 +75,158 @@ func TestBuilderSystemChaining(t *testing.T) {
 	}
 }
 
+func TestBuilderAppendSystem_ConcatenatesRatherThanReplacing(t *testing.T) {
+	b := New(ModelGPT5).System("You are a pirate.").AppendSystem("Always answer in haiku.")
+
+	if b.system != "You are a pirate.\n\nAlways answer in haiku." {
+		t.Errorf("unexpected system prompt: %q", b.system)
+	}
+}
+
+func TestBuilderAppendSystem_SetsWhenEmpty(t *testing.T) {
+	b := New(ModelGPT5).AppendSystem("Always answer in haiku.")
+
+	if b.system != "Always answer in haiku." {
+		t.Errorf("unexpected system prompt: %q", b.system)
+	}
+}
+
+func TestBuilderPrependSystem_PutsTextBeforeExisting(t *testing.T) {
+	b := New(ModelGPT5).System("You are a pirate.").PrependSystem("Always answer in haiku.")
+
+	if b.system != "Always answer in haiku.\n\nYou are a pirate." {
+		t.Errorf("unexpected system prompt: %q", b.system)
+	}
+}
+
+func TestBuilderSystemFileAppend_AppendsRatherThanClobbering(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "persona.txt")
+	if err := os.WriteFile(path, []byte("You are a pirate."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := New(ModelGPT5).System("Always answer in haiku.").SystemFileAppend(path)
+
+	if b.system != "Always answer in haiku.\n\nYou are a pirate." {
+		t.Errorf("unexpected system prompt: %q", b.system)
+	}
+}
+
+func TestBuilderSystemFS_ReadsFromEmbeddedFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"prompts/persona.txt": {Data: []byte("You are a pirate.")},
+	}
+
+	b := New(ModelGPT5).SystemFS(fsys, "prompts/persona.txt")
+
+	if b.system != "You are a pirate." {
+		t.Errorf("unexpected system prompt: %q", b.system)
+	}
+}
+
+func TestBuilderErr_AccumulatesFailedFileLoads(t *testing.T) {
+	b := New(ModelGPT5).
+		SystemFile("does-not-exist.txt").
+		Context("also-does-not-exist.txt")
+
+	err := b.Err()
+	var loadErr *FileLoadError
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("expected a *FileLoadError, got %v", err)
+	}
+	if len(loadErr.Errors) != 2 {
+		t.Fatalf("expected 2 accumulated errors, got %d", len(loadErr.Errors))
+	}
+}
+
+func TestBuilderErr_NilWhenNothingFailed(t *testing.T) {
+	b := New(ModelGPT5).System("fine").ContextString("name", "content")
+
+	if err := b.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestBuilderSendWithMeta_SurfacesFileLoadErrorBeforeCallingProvider(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		SystemFile("does-not-exist.txt").
+		User("hi")
+
+	meta := b.SendWithMeta()
+
+	if calls != 0 {
+		t.Fatalf("expected the provider to never be called, got %d calls", calls)
+	}
+
+	var loadErr *FileLoadError
+	if !errors.As(meta.Error, &loadErr) {
+		t.Fatalf("expected a *FileLoadError, got %v", meta.Error)
+	}
+}
+
+func TestBuilderThinkingBudget_MapsTokensToNearestLevel(t *testing.T) {
+	cases := []struct {
+		tokens int
+		want   ThinkingLevel
+	}{
+		{0, ThinkingNone},
+		{512, ThinkingLow},
+		{1024, ThinkingLow},
+		{4096, ThinkingMedium},
+		{16384, ThinkingHigh},
+	}
+	for _, c := range cases {
+		b := New(ModelGPT5).ThinkingBudget(c.tokens)
+		if b.thinking != c.want {
+			t.Errorf("ThinkingBudget(%d): expected level %q, got %q", c.tokens, c.want, b.thinking)
+		}
+		if b.thinkingBudget != c.tokens {
+			t.Errorf("ThinkingBudget(%d): expected thinkingBudget stored as %d, got %d", c.tokens, c.tokens, b.thinkingBudget)
+		}
+	}
+}
+
+func TestBuilderAutoProvider_SelectsMatchingClient(t *testing.T) {
+	cases := []struct {
+		model Model
+		want  ProviderType
+	}{
+		{ModelClaudeOpus, ProviderAnthropic},
+		{ModelGemini3Flash, ProviderGoogle},
+		{ModelGPT5, ProviderOpenAI},
+	}
+	for _, c := range cases {
+		b := New(c.model).AutoProvider()
+		if b.client == nil {
+			t.Fatalf("AutoProvider(%s): expected a client to be set", c.model)
+		}
+		if b.client.providerType != c.want {
+			t.Errorf("AutoProvider(%s): expected provider %q, got %q", c.model, c.want, b.client.providerType)
+		}
+	}
+}
+
+func TestBuilderAutoProvider_LeavesClientUnchangedForUnknownFamily(t *testing.T) {
+	b := New(ModelLlama4).AutoProvider()
+
+	if b.client != nil {
+		t.Errorf("expected client to remain unset for a model family with no direct provider, got %+v", b.client)
+	}
+}
+
 func TestBuilderUser(t *testing.T) {
 	b := New(ModelGPT5).User("Hello")
 
@@ -60,6 +241,22 @@ func TestBuilderUser(t *testing.T) {
 	}
 }
 
+func TestBuilderUserAs_SetsNameOnMessage(t *testing.T) {
+	b := New(ModelGPT5).UserAs("Alice", "hi Bob").UserAs("Bob", "hi Alice")
+
+	if len(b.messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(b.messages))
+	}
+	if b.messages[0].Name != "Alice" || b.messages[1].Name != "Bob" {
+		t.Fatalf("expected distinct names, got %+v", b.messages)
+	}
+
+	msgs := b.buildMessages(ProviderOpenAI)
+	if msgs[0].Name != "Alice" || msgs[1].Name != "Bob" {
+		t.Fatalf("expected names preserved through buildMessages, got %+v", msgs)
+	}
+}
+
 func TestBuilderAssistant(t *testing.T) {
 	b := New(ModelGPT5).Assistant("Hi there")
 
@@ -71,6 +268,48 @@ func TestBuilderAssistant(t *testing.T) {
 	}
 }
 
+func TestBuilderToolResult(t *testing.T) {
+	b := New(ModelGPT5).ToolResult("call_123", "42")
+
+	if len(b.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(b.messages))
+	}
+	if b.messages[0].Role != "tool" {
+		t.Errorf("expected role 'tool', got %q", b.messages[0].Role)
+	}
+	if b.messages[0].ToolCallID != "call_123" {
+		t.Errorf("expected tool call id 'call_123', got %q", b.messages[0].ToolCallID)
+	}
+	if b.messages[0].Content != "42" {
+		t.Errorf("expected content '42', got %q", b.messages[0].Content)
+	}
+}
+
+func TestBuilderAssistantToolCalls(t *testing.T) {
+	call := ToolCall{ID: "call_123", Type: "function"}
+	call.Function.Name = "get_weather"
+	call.Function.Arguments = `{"city":"Paris"}`
+
+	b := New(ModelGPT5).
+		AssistantToolCalls(call).
+		ToolResult("call_123", "22C")
+
+	if len(b.messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(b.messages))
+	}
+	if b.messages[0].Role != "assistant" {
+		t.Errorf("expected role 'assistant', got %q", b.messages[0].Role)
+	}
+	if len(b.messages[0].ToolCalls) != 1 || b.messages[0].ToolCalls[0].ID != "call_123" {
+		t.Fatalf("expected the tool call to be preserved, got %+v", b.messages[0].ToolCalls)
+	}
+
+	msgs := b.buildMessages(ProviderOpenAI)
+	if len(msgs[0].ToolCalls) != 1 || msgs[0].ToolCalls[0].Function.Name != "get_weather" {
+		t.Fatalf("expected buildMessages to preserve ToolCalls, got %+v", msgs[0].ToolCalls)
+	}
+}
+
 func TestBuilderMessageChaining(t *testing.T) {
 	b := New(ModelGPT5).
 		User("Hello").
@@ -158,6 +397,124 @@ func TestBuilderContextMultiple(t *testing.T) {
 	}
 }
 
+func TestBuilderContextFS_ReadsSingleFileFromEmbeddedFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/notes.txt": {Data: []byte("content here")},
+	}
+
+	b := New(ModelGPT5).ContextFS(fsys, "assets/notes.txt")
+
+	if len(b.fileContext) != 1 {
+		t.Fatalf("expected 1 context, got %d", len(b.fileContext))
+	}
+	if !strings.Contains(b.fileContext[0], "content here") {
+		t.Error("context should contain the content")
+	}
+}
+
+func TestBuilderContextFS_ExpandsGlobPattern(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/one.txt": {Data: []byte("content1")},
+		"assets/two.txt": {Data: []byte("content2")},
+	}
+
+	b := New(ModelGPT5).ContextFS(fsys, "assets/*.txt")
+
+	if len(b.fileContext) != 2 {
+		t.Fatalf("expected 2 contexts, got %d", len(b.fileContext))
+	}
+}
+
+func TestBuilderContextWith_TruncatesByMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "big.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 1000)), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	b := New(ModelGPT5).ContextWith(path, ContextOptions{MaxBytes: 100})
+
+	if len(b.fileContext) != 1 {
+		t.Fatalf("expected 1 context, got %d", len(b.fileContext))
+	}
+	if !strings.Contains(b.fileContext[0], "bytes omitted") {
+		t.Errorf("expected a bytes-omitted marker, got %q", b.fileContext[0])
+	}
+}
+
+func TestBuilderContextWith_SmallFileUnaffected(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "small.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	b := New(ModelGPT5).ContextWith(path, ContextOptions{MaxBytes: 100})
+
+	if !strings.Contains(b.fileContext[0], "hello") {
+		t.Errorf("expected content unchanged, got %q", b.fileContext[0])
+	}
+	if strings.Contains(b.fileContext[0], "omitted") {
+		t.Errorf("small file should not be truncated, got %q", b.fileContext[0])
+	}
+}
+
+func TestTruncateBytes_DoesNotSplitMultibyteRuneAtCut(t *testing.T) {
+	// Each "世" is 3 bytes. A cut landing mid-rune would corrupt it into
+	// replacement characters once re-decoded as a string.
+	content := strings.Repeat("世", 50)
+
+	got := truncateBytes(content, 31)
+
+	if !utf8.ValidString(got) {
+		t.Fatalf("expected valid UTF-8 after truncation, got %q", got)
+	}
+	if strings.Contains(got, "�") {
+		t.Fatalf("expected no replacement characters from a split rune, got %q", got)
+	}
+}
+
+func TestFormatContext_HeadTail(t *testing.T) {
+	content := "1\n2\n3\n4\n5\n6\n7\n8"
+	got := formatContext(content, ContextOptions{HeadTail: 2}, ModelGPT5)
+
+	if !strings.Contains(got, "1\n2") || !strings.Contains(got, "7\n8") {
+		t.Errorf("expected head and tail lines preserved, got %q", got)
+	}
+	if !strings.Contains(got, "lines omitted") {
+		t.Errorf("expected a lines-omitted marker, got %q", got)
+	}
+}
+
+func TestFormatContext_MaxTokens(t *testing.T) {
+	content := strings.Repeat("word ", 500)
+	got := formatContext(content, ContextOptions{MaxTokens: 20}, ModelGPT5)
+
+	if !strings.Contains(got, "tokens omitted") {
+		t.Errorf("expected a tokens-omitted marker, got %q", got)
+	}
+	if n := EstimateTokens(got, ModelGPT5); n >= EstimateTokens(content, ModelGPT5) {
+		t.Errorf("expected truncated content to estimate fewer tokens, got %d (original %d)", n, EstimateTokens(content, ModelGPT5))
+	}
+}
+
+func TestFormatContext_MaxTokensNoOpWhenUnderBudget(t *testing.T) {
+	content := "a short string"
+	got := formatContext(content, ContextOptions{MaxTokens: 1000}, ModelGPT5)
+
+	if got != content {
+		t.Errorf("expected content unchanged when under token budget, got %q", got)
+	}
+}
+
+func TestFormatContext_LineNumbers(t *testing.T) {
+	got := formatContext("alpha\nbeta", ContextOptions{LineNumbers: true}, ModelGPT5)
+
+	if !strings.Contains(got, "1: alpha") || !strings.Contains(got, "2: beta") {
+		t.Errorf("expected line numbers, got %q", got)
+	}
+}
+
 func TestBuilderRetry(t *testing.T) {
 	b := New(ModelGPT5).Retry(3)
 
@@ -180,6 +537,109 @@ func TestBuilderFallback(t *testing.T) {
 	}
 }
 
+func TestBuilderFallbackTimeout(t *testing.T) {
+	b := New(ModelGPT5).FallbackTimeout(5 * time.Second)
+
+	if b.fallbackTimeout != 5*time.Second {
+		t.Errorf("expected fallbackTimeout=5s, got %s", b.fallbackTimeout)
+	}
+}
+
+func TestBuilderFallbackTimeout_CutsOffHungPrimaryBeforeFallback(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var primaryHadDeadline bool
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if req.Model == string(ModelGPT5) {
+				_, primaryHadDeadline = ctx.Deadline()
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &ProviderResponse{Content: "fallback answer"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Fallback(ModelClaudeOpus).
+		FallbackTimeout(20 * time.Millisecond)
+
+	start := time.Now()
+	meta := b.User("hi").SendWithMeta()
+	elapsed := time.Since(start)
+
+	if !primaryHadDeadline {
+		t.Fatal("expected the primary attempt's context to carry a deadline from FallbackTimeout")
+	}
+	if meta.Error != nil {
+		t.Fatalf("expected the fallback model to succeed, got error: %v", meta.Error)
+	}
+	if meta.Content != "fallback answer" {
+		t.Fatalf("expected fallback content, got %q", meta.Content)
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("expected the hung primary to be cut off promptly, took %s", elapsed)
+	}
+}
+
+func TestBuilderPrefill_AppendsTrailingAssistantMessageForAnthropic(t *testing.T) {
+	b := New(ModelClaudeOpus).System("be concise").User("give me json").Prefill("{")
+
+	msgs := b.buildMessages(ProviderAnthropic)
+
+	last := msgs[len(msgs)-1]
+	if last.Role != "assistant" || last.Content != "{" {
+		t.Fatalf("expected trailing assistant prefill message, got %+v", last)
+	}
+}
+
+func TestBuilderPrefill_FallsBackToSystemInstructionForOtherProviders(t *testing.T) {
+	b := New(ModelGPT5).System("be concise").User("give me json").Prefill("{")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	if msgs[len(msgs)-1].Role != "user" {
+		t.Fatalf("expected no trailing assistant message for a non-Anthropic provider, got %+v", msgs[len(msgs)-1])
+	}
+	sys := msgs[0].Content.(string)
+	if !strings.Contains(sys, "{") {
+		t.Fatalf("expected prefill text folded into system instruction, got %q", sys)
+	}
+}
+
+func TestBuilder_SendWithMeta_PrependsPrefillToAnthropicCompletion(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotMsgs []Message
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			gotMsgs = req.Messages
+			return &ProviderResponse{Content: `"name": "alice"}`}, nil
+		},
+	}
+
+	b := New(ModelClaudeOpus).WithClient(&Client{provider: p, providerType: ProviderAnthropic}).
+		User("give me json").
+		Prefill(`{`)
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.Content != `{"name": "alice"}` {
+		t.Fatalf("expected prefill stitched onto completion, got %q", meta.Content)
+	}
+
+	last := gotMsgs[len(gotMsgs)-1]
+	if last.Role != "assistant" || last.Content != "{" {
+		t.Fatalf("expected the provider to receive a trailing assistant prefill message, got %+v", last)
+	}
+}
+
 func TestBuilderJSON(t *testing.T) {
 	b := New(ModelGPT5).JSON()
 
@@ -279,13 +739,13 @@ func TestBuilderClone(t *testing.T) {
 }
 
 func TestBuildMessages(t *testing.T) {
-	b := New(ModelGPT5).
+	b := New(ModelGPT4o).
 		System("You are {{role}}").
 		With(Vars{"role": "helpful"}).
 		User("Hello {{name}}").
 		Var("name", "World")
 
-	msgs := b.buildMessages()
+	msgs := b.buildMessages(ProviderOpenAI)
 
 	if len(msgs) != 2 {
 		t.Fatalf("expected 2 messages, got %d", len(msgs))
@@ -308,13 +768,87 @@ func TestBuildMessages(t *testing.T) {
 	}
 }
 
+func TestBuildMessagesWithAudio(t *testing.T) {
+	b := New(ModelGPTAudio).
+		User("What's being said?").
+		Audio(AudioInput{Data: []byte("raw-audio-bytes"), Format: "wav"})
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	parts, ok := msgs[len(msgs)-1].Content.([]ContentPart)
+	if !ok {
+		t.Fatalf("expected last message content to be []ContentPart, got %T", msgs[len(msgs)-1].Content)
+	}
+
+	var audioPart *ContentPart
+	for i := range parts {
+		if parts[i].Type == "input_audio" {
+			audioPart = &parts[i]
+		}
+	}
+	if audioPart == nil {
+		t.Fatalf("expected an input_audio content part, got %+v", parts)
+	}
+	if audioPart.InputAudio.Format != "wav" {
+		t.Errorf("expected format %q, got %q", "wav", audioPart.InputAudio.Format)
+	}
+	if audioPart.InputAudio.Data != base64.StdEncoding.EncodeToString([]byte("raw-audio-bytes")) {
+		t.Errorf("expected base64-encoded audio data, got %q", audioPart.InputAudio.Data)
+	}
+}
+
+func TestBuildMessagesWithImages_PreservesPerImageDetail(t *testing.T) {
+	b := New(ModelGPT5).
+		User("compare these").
+		ImageURLWithDetail("https://example.com/thumb.png", ImageDetailLow).
+		ImageURLWithDetail("https://example.com/hero.png", ImageDetailHigh)
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	parts, ok := msgs[len(msgs)-1].Content.([]ContentPart)
+	if !ok {
+		t.Fatalf("expected last message content to be []ContentPart, got %T", msgs[len(msgs)-1].Content)
+	}
+
+	var details []string
+	for _, p := range parts {
+		if p.Type == "image_url" {
+			details = append(details, p.ImageURL.Detail)
+		}
+	}
+	if len(details) != 2 || details[0] != "low" || details[1] != "high" {
+		t.Fatalf("expected details [low high], got %v", details)
+	}
+}
+
+func TestImageURLWithDetail_WarnsInDebugOnUnknownDetail(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	Debug = true
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	New(ModelGPT5).ImageURLWithDetail("https://example.com/a.png", ImageDetail("ultra"))
+
+	_ = w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	_ = r.Close()
+
+	if !strings.Contains(string(out), "ultra") {
+		t.Fatalf("expected warning mentioning unknown detail, got %q", out)
+	}
+}
+
 func TestBuildMessagesWithContext(t *testing.T) {
 	b := New(ModelGPT5).
 		System("You are helpful").
 		ContextString("data", "some content").
 		User("Analyze")
 
-	msgs := b.buildMessages()
+	msgs := b.buildMessages(ProviderOpenAI)
 
 	// System should contain context
 	sysContent := msgs[0].Content.(string)
@@ -326,13 +860,71 @@ func TestBuildMessagesWithContext(t *testing.T) {
 	}
 }
 
+func TestBuildMessagesWithContext_BeforePlacesContextAheadOfInstructions(t *testing.T) {
+	b := New(ModelGPT5).
+		System("You are helpful").
+		ContextString("data", "some content").
+		ContextPlacement(ContextBefore).
+		User("Analyze")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	sysContent := msgs[0].Content.(string)
+	contextIdx := strings.Index(sysContent, "# Context")
+	instructionIdx := strings.Index(sysContent, "You are helpful")
+	if contextIdx == -1 || instructionIdx == -1 || contextIdx > instructionIdx {
+		t.Fatalf("expected context before instructions, got %q", sysContent)
+	}
+}
+
+func TestBuildMessagesWithContext_AfterUsesTrailingUserMessage(t *testing.T) {
+	b := New(ModelGPT5).
+		System("You are helpful").
+		ContextString("data", "some content").
+		ContextPlacement(ContextAfter).
+		User("Analyze")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	sysContent := msgs[0].Content.(string)
+	if strings.Contains(sysContent, "# Context") {
+		t.Fatalf("expected no context in system prompt, got %q", sysContent)
+	}
+
+	last := msgs[len(msgs)-1]
+	if last.Role != "user" {
+		t.Fatalf("expected a trailing user message with context, got role %q", last.Role)
+	}
+	if content, ok := last.Content.(string); !ok || !strings.Contains(content, "some content") {
+		t.Fatalf("expected trailing user message to contain context, got %+v", last.Content)
+	}
+}
+
+func TestBuildMessagesWithContext_CustomLabel(t *testing.T) {
+	b := New(ModelGPT5).
+		System("You are helpful").
+		ContextString("data", "some content").
+		ContextLabel("# Reference Material").
+		User("Analyze")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	sysContent := msgs[0].Content.(string)
+	if !strings.Contains(sysContent, "# Reference Material") {
+		t.Fatalf("expected custom context label, got %q", sysContent)
+	}
+	if strings.Contains(sysContent, "# Context\n") {
+		t.Fatalf("expected default label to be overridden, got %q", sysContent)
+	}
+}
+
 func TestBuildMessagesWithJSON(t *testing.T) {
 	b := New(ModelGPT5).
 		System("You are helpful").
 		JSON().
 		User("Give me data")
 
-	msgs := b.buildMessages()
+	msgs := b.buildMessages(ProviderOpenAI)
 
 	if !strings.Contains(msgs[0].Content.(string), "JSON") {
 		t.Error("JSON mode should add JSON instruction to system message")
@@ -340,11 +932,11 @@ func TestBuildMessagesWithJSON(t *testing.T) {
 }
 
 func TestBuildMessagesJSONNoSystem(t *testing.T) {
-	b := New(ModelGPT5).
+	b := New(ModelGPT4o).
 		JSON().
 		User("Give me data")
 
-	msgs := b.buildMessages()
+	msgs := b.buildMessages(ProviderOpenAI)
 
 	// Should create a system message for JSON
 	if len(msgs) != 2 {
@@ -358,10 +950,56 @@ func TestBuildMessagesJSONNoSystem(t *testing.T) {
 	}
 }
 
+func TestBuildMessagesWithJSONMode_SkipsInstructionForNativeModel(t *testing.T) {
+	b := New(ModelGPT5).
+		System("You are helpful").
+		JSONMode(true).
+		User("Give me data")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	if strings.Contains(msgs[0].Content.(string), "JSON") {
+		t.Errorf("expected no JSON instruction injected for a native-JSON model, got %q", msgs[0].Content)
+	}
+}
+
+func TestBuildMessagesWithJSONMode_KeepsInstructionForNonNativeModel(t *testing.T) {
+	b := New(ModelO1Preview).
+		System("You are helpful").
+		JSONMode(true).
+		User("Give me data")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	if !strings.Contains(msgs[0].Content.(string), "JSON") {
+		t.Error("expected JSON instruction injected as a fallback for a model without native JSON support")
+	}
+}
+
+func TestBuildMessages_UsesDeveloperRoleForModelsThatPreferIt(t *testing.T) {
+	b := New(ModelGPT5).System("You are helpful").User("hi")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	if msgs[0].Role != "developer" {
+		t.Errorf("expected system message role to be translated to developer for %s, got %q", ModelGPT5, msgs[0].Role)
+	}
+}
+
+func TestBuildMessages_KeepsSystemRoleForModelsWithoutDeveloperRole(t *testing.T) {
+	b := New(ModelGPT4o).System("You are helpful").User("hi")
+
+	msgs := b.buildMessages(ProviderOpenAI)
+
+	if msgs[0].Role != "system" {
+		t.Errorf("expected system message role to stay system for %s, got %q", ModelGPT4o, msgs[0].Role)
+	}
+}
+
 func TestBuildMessagesNoSystem(t *testing.T) {
 	b := New(ModelGPT5).User("Hello")
 
-	msgs := b.buildMessages()
+	msgs := b.buildMessages(ProviderOpenAI)
 
 	if len(msgs) != 1 {
 		t.Fatalf("expected 1 message, got %d", len(msgs))
@@ -386,6 +1024,520 @@ func TestBuilderChat(t *testing.T) {
 	}
 }
 
+func TestBuilderFallback_DropsThinkingForNonReasoningFallbackModel(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotThinking []ThinkingLevel
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			gotThinking = append(gotThinking, req.Thinking)
+			if len(gotThinking) == 1 {
+				return nil, &ProviderError{Provider: "stub", Message: "primary down"}
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelO1).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		ThinkHigh().
+		Fallback(ModelGPT4o)
+
+	meta := b.User("hi").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if len(gotThinking) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(gotThinking))
+	}
+	if gotThinking[0] != ThinkingHigh {
+		t.Fatalf("expected the primary model to keep Thinking=high, got %q", gotThinking[0])
+	}
+	if gotThinking[1] != "" {
+		t.Fatalf("expected Thinking to be dropped for non-reasoning fallback model, got %q", gotThinking[1])
+	}
+}
+
+func TestBuilderFallback_SkipsModelsMissingBuiltinToolCapability(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{WebSearch: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return nil, &ProviderError{Provider: "stub", Message: "primary down"}
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		WebSearch().
+		Fallback(ModelClaudeOpus, ModelGemini3Flash)
+
+	meta := b.User("hi").SendWithMeta()
+
+	// ModelClaudeOpus doesn't support web_search and should be skipped without
+	// ever calling the provider for it; only the primary model's single call
+	// should have gone through before falling through to the next fallback.
+	if calls != 1 {
+		t.Fatalf("expected only the primary model to be sent, got %d calls", calls)
+	}
+	if meta.Error == nil {
+		t.Fatalf("expected an error since no model succeeded")
+	}
+}
+
+func TestBuilderSendWithMeta_RejectsEmptyMessageList(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI})
+
+	meta := b.SendWithMeta()
+
+	if calls != 0 {
+		t.Fatalf("expected the provider to never be called, got %d calls", calls)
+	}
+
+	var perr *ProviderError
+	if !errors.As(meta.Error, &perr) {
+		t.Fatalf("expected a *ProviderError, got %v", meta.Error)
+	}
+	if perr.Message != "no user message provided" {
+		t.Fatalf("expected message %q, got %q", "no user message provided", perr.Message)
+	}
+}
+
+func TestBuilderSendWithMeta_AllowsSystemOnlyPrompt(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		System("be concise")
+
+	meta := b.SendWithMeta()
+
+	if meta.Error != nil {
+		t.Fatalf("expected no error with a non-empty system prompt, got %v", meta.Error)
+	}
+	if meta.Content != "ok" {
+		t.Fatalf("expected content %q, got %q", "ok", meta.Content)
+	}
+}
+
+func TestBuilderStrictVars_ErrorsOnMissingPlaceholder(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		StrictVars()
+
+	meta := b.User("Hello {{name}}!").SendWithMeta()
+
+	if calls != 0 {
+		t.Fatalf("expected the provider to never be called, got %d calls", calls)
+	}
+
+	var missing *MissingVarsError
+	if !errors.As(meta.Error, &missing) {
+		t.Fatalf("expected a *MissingVarsError, got %v", meta.Error)
+	}
+	if len(missing.Keys) != 1 || missing.Keys[0] != "name" {
+		t.Fatalf("expected missing key %q, got %v", "name", missing.Keys)
+	}
+}
+
+func TestBuilderStrictVars_PassesWhenAllVarsResolved(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		StrictVars().
+		Var("name", "World")
+
+	meta := b.User("Hello {{name}}!").SendWithMeta()
+
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.Content != "ok" {
+		t.Fatalf("expected content %q, got %q", "ok", meta.Content)
+	}
+}
+
+func TestBuilderRender_ReturnsResolvedMessagesWithoutSending(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	msgs, err := New(ModelGPT5).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Var("name", "World").
+		User("Hello {{name}}!").
+		Render()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected the provider to never be called, got %d calls", calls)
+	}
+	if len(msgs) != 1 || msgs[0].Content != "Hello World!" {
+		t.Fatalf("unexpected rendered messages: %+v", msgs)
+	}
+}
+
+func TestBuilderRender_ReturnsMissingVarsErrorWhenStrict(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	_, err := New(ModelGPT5).StrictVars().User("Hello {{name}}!").Render()
+
+	var missing *MissingVarsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a *MissingVarsError, got %v", err)
+	}
+	if len(missing.Keys) != 1 || missing.Keys[0] != "name" {
+		t.Fatalf("expected missing key %q, got %v", "name", missing.Keys)
+	}
+}
+
+func TestBuilderDryRun_ReturnsRequestWithoutSending(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	req, _, err := New(ModelGPT5).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		ThinkHigh().
+		User("hi").
+		DryRun()
+	if calls != 0 {
+		t.Fatalf("expected the provider to never be called, got %d calls", calls)
+	}
+	if req.Model != string(ModelGPT5) || req.Thinking != ThinkingHigh {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	// stubProvider doesn't implement RequestPreviewer, so no JSON body is
+	// available - DryRun surfaces that rather than faking one.
+	if err == nil || !strings.Contains(err.Error(), "does not support dry-run previews") {
+		t.Fatalf("expected a dry-run-unsupported error, got %v", err)
+	}
+}
+
+func TestBuilderDryRun_ReturnsResolvedJSONBodyForOpenAI(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k"})
+
+	_, body, err := New(ModelO1).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		ThinkHigh().
+		User("hi").
+		DryRun()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("expected valid JSON body, got %q: %v", body, err)
+	}
+	if decoded["reasoning_effort"] != "high" {
+		t.Fatalf("expected reasoning_effort=high in dry-run body, got %#v", decoded)
+	}
+	if _, ok := decoded["temperature"]; ok {
+		t.Fatalf("expected temperature to be dropped for reasoning model in dry-run body, got %#v", decoded)
+	}
+}
+
+func TestBuilderServiceTier_ThreadsThroughToRequest(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k"})
+
+	_, body, err := New(ModelGPT5).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		ServiceTier("flex").
+		User("hi").
+		DryRun()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("expected valid JSON body, got %q: %v", body, err)
+	}
+	if decoded["service_tier"] != "flex" {
+		t.Fatalf("expected service_tier=flex in dry-run body, got %#v", decoded)
+	}
+}
+
+func TestBuilderCaptureRaw_PopulatesRawWhenSet(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	raw := json.RawMessage(`{"id":"resp-123"}`)
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if !req.CaptureRaw {
+				t.Errorf("expected req.CaptureRaw to be true")
+			}
+			return &ProviderResponse{Content: "ok", Raw: raw}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		CaptureRaw().
+		User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if string(meta.Raw) != string(raw) {
+		t.Errorf("expected Raw %q, got %q", raw, meta.Raw)
+	}
+}
+
+func TestBuilderCaptureRaw_NilWhenNotSet(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if req.CaptureRaw {
+				t.Errorf("expected req.CaptureRaw to be false")
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.Raw != nil {
+		t.Errorf("expected nil Raw, got %q", meta.Raw)
+	}
+}
+
+func TestResponseMeta_RequiresToolCall_WhenFinishReasonIsToolCalls(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "", FinishReason: "tool_calls"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if !meta.RequiresToolCall() {
+		t.Errorf("expected RequiresToolCall to be true when FinishReason is tool_calls")
+	}
+	if meta.Content != "" {
+		t.Errorf("expected empty Content, got %q", meta.Content)
+	}
+}
+
+func TestResponseMeta_RequiresToolCall_FalseOnNormalStop(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "hi there", FinishReason: "stop"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.RequiresToolCall() {
+		t.Errorf("expected RequiresToolCall to be false when FinishReason is stop")
+	}
+}
+
+func TestResponseMeta_Truncated_WhenFinishReasonIsLength(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "cut off mid-sen", FinishReason: "length"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if !meta.Truncated() {
+		t.Errorf("expected Truncated to be true when FinishReason is length")
+	}
+}
+
+func TestResponseMeta_Truncated_FalseOnNormalStop(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "hi there", FinishReason: "stop"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.Truncated() {
+		t.Errorf("expected Truncated to be false when FinishReason is stop")
+	}
+}
+
+func TestResponseMeta_StructuredAccessors_NilResponsesOutput(t *testing.T) {
+	meta := &ResponseMeta{Content: "plain text"}
+
+	if meta.Text() != "" {
+		t.Errorf("expected empty Text, got %q", meta.Text())
+	}
+	if meta.Citations() != nil {
+		t.Errorf("expected nil Citations, got %v", meta.Citations())
+	}
+	if meta.ToolCallsByType("web_search_call") != nil {
+		t.Errorf("expected nil ToolCallsByType, got %v", meta.ToolCallsByType("web_search_call"))
+	}
+}
+
+func TestResponseMeta_StructuredAccessors_PopulatedResponsesOutput(t *testing.T) {
+	meta := &ResponseMeta{
+		ResponsesOutput: &ResponsesOutput{
+			Text:      "the answer",
+			Citations: []Citation{{Type: "url_citation", URL: "https://example.com"}},
+			ToolCalls: []ResponsesToolCall{
+				{Type: "web_search_call", ID: "1"},
+				{Type: "file_search_call", ID: "2"},
+				{Type: "web_search_call", ID: "3"},
+			},
+		},
+	}
+
+	if meta.Text() != "the answer" {
+		t.Errorf("expected Text %q, got %q", "the answer", meta.Text())
+	}
+	if len(meta.Citations()) != 1 || meta.Citations()[0].URL != "https://example.com" {
+		t.Errorf("unexpected Citations: %v", meta.Citations())
+	}
+
+	webSearches := meta.ToolCallsByType("web_search_call")
+	if len(webSearches) != 2 {
+		t.Fatalf("expected 2 web_search_call entries, got %d", len(webSearches))
+	}
+	if webSearches[0].ID != "1" || webSearches[1].ID != "3" {
+		t.Errorf("unexpected web_search_call IDs: %v", webSearches)
+	}
+}
+
+func TestBuilderHeader_ThreadsThroughToProviderRequest(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if req.Headers["X-Request-Id"] != "req-123" {
+				t.Errorf("expected X-Request-Id header on request, got %#v", req.Headers)
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Header("X-Request-Id", "req-123").
+		User("hello")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+}
+
 func TestBuilderFluentChaining(t *testing.T) {
 	// Test that all methods return *Builder for chaining
 	b := New(ModelGPT5).
@@ -394,7 +1546,7 @@ func TestBuilderFluentChaining(t *testing.T) {
 		Assistant("test").
 		With(Vars{"k": "v"}).
 		Var("k2", "v2").
-		Context("nonexistent"). // Will print error but still chain
+		Context("nonexistent"). // Records a load error but still chains
 		ContextString("name", "content").
 		Retry(1).
 		Fallback(ModelClaudeOpus).
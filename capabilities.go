@@ -0,0 +1,70 @@
+package ai
+
+import "strings"
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-Model Capabilities
+// ═══════════════════════════════════════════════════════════════════════════
+
+// modelCapabilityOverrides holds per-model exceptions to the owning
+// provider's default ProviderCapabilities, for models that are
+// finer-grained than their provider as a whole (e.g. reasoning-only models
+// that don't support vision or tool calling, or single-purpose audio/image
+// models that aren't chat models at all).
+var modelCapabilityOverrides = map[Model]func(*ProviderCapabilities){
+	ModelO1Preview: func(c *ProviderCapabilities) { c.Tools, c.Vision, c.JSON = false, false, false },
+	ModelO1Mini:    func(c *ProviderCapabilities) { c.Tools, c.Vision, c.JSON = false, false, false },
+
+	ModelGPT4oTranscribe:        sttOnlyCapabilities,
+	ModelGPT4oTranscribeDiarize: sttOnlyCapabilities,
+	ModelGPT4oMiniTranscribe:    sttOnlyCapabilities,
+	ModelGPT4oMiniTTS:           ttsOnlyCapabilities,
+
+	ModelGPTImage15:         imageOnlyCapabilities,
+	ModelGPTImage1:          imageOnlyCapabilities,
+	ModelGPTImage1Mini:      imageOnlyCapabilities,
+	ModelChatGPTImageLatest: imageOnlyCapabilities,
+}
+
+func sttOnlyCapabilities(c *ProviderCapabilities) {
+	*c = ProviderCapabilities{STT: true}
+}
+
+func ttsOnlyCapabilities(c *ProviderCapabilities) {
+	*c = ProviderCapabilities{TTS: true}
+}
+
+func imageOnlyCapabilities(c *ProviderCapabilities) {
+	*c = ProviderCapabilities{ImageGeneration: true}
+}
+
+// Capabilities returns the effective ProviderCapabilities for a specific
+// model. This is finer-grained than calling Capabilities() on a Provider
+// directly, since not every model offered by a provider supports every
+// feature the provider as a whole advertises (e.g. o1-mini has no vision or
+// tool calling even though OpenAI's provider-level capabilities say yes).
+// It lets UIs gray out features based on the selected model.
+func Capabilities(model Model) ProviderCapabilities {
+	caps := providerCapabilitiesForModel(model)
+	if override, ok := modelCapabilityOverrides[model]; ok {
+		override(&caps)
+	}
+	return caps
+}
+
+// providerCapabilitiesForModel returns the default capabilities of the
+// provider that owns model, inferred from its "vendor/model" prefix.
+func providerCapabilitiesForModel(model Model) ProviderCapabilities {
+	raw := string(model)
+	switch {
+	case strings.HasPrefix(raw, "openai/"):
+		return (&OpenAIProvider{}).Capabilities()
+	case strings.HasPrefix(raw, "anthropic/"):
+		return (&AnthropicProvider{}).Capabilities()
+	case strings.HasPrefix(raw, "google/"):
+		return (&GoogleProvider{}).Capabilities()
+	default:
+		// xAI, Qwen, Meta, Mistral, etc. are typically accessed via OpenRouter.
+		return (&OpenRouterProvider{}).Capabilities()
+	}
+}
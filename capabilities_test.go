@@ -0,0 +1,34 @@
+package ai
+
+import "testing"
+
+func TestCapabilities_UsesProviderDefaults(t *testing.T) {
+	caps := Capabilities(ModelGPT4o)
+	if !caps.Tools || !caps.Vision || !caps.Streaming {
+		t.Errorf("expected GPT-4o to inherit OpenAI's default capabilities, got %+v", caps)
+	}
+
+	caps = Capabilities(ModelClaudeSonnet)
+	if !caps.Thinking || !caps.PDF {
+		t.Errorf("expected Claude Sonnet to inherit Anthropic's default capabilities, got %+v", caps)
+	}
+}
+
+func TestCapabilities_AppliesPerModelOverrides(t *testing.T) {
+	caps := Capabilities(ModelO1Mini)
+	if caps.Tools || caps.Vision || caps.JSON {
+		t.Errorf("expected o1-mini to have tools/vision/JSON disabled, got %+v", caps)
+	}
+
+	caps = Capabilities(ModelGPT4oTranscribe)
+	if !caps.STT || caps.Tools || caps.Vision {
+		t.Errorf("expected gpt-4o-transcribe to be STT-only, got %+v", caps)
+	}
+}
+
+func TestCapabilities_UnknownVendorFallsBackToOpenRouter(t *testing.T) {
+	caps := Capabilities(ModelGrok3)
+	if !caps.Tools || !caps.Streaming {
+		t.Errorf("expected xAI model to inherit OpenRouter's default capabilities, got %+v", caps)
+	}
+}
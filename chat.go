@@ -1,30 +1,74 @@
 package ai
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 )
 
 // Conversation maintains chat history for multi-turn conversations.
 // It wraps a Builder and automatically appends user and assistant messages to the history.
 type Conversation struct {
-	builder *Builder
-	history []Message
+	builder             *Builder
+	history             []Message
+	windowStrategy      WindowStrategy
+	autoSummarizeModel  Model
+	lastSummarizedTurns int
+}
+
+// WithAutoSummarize enables automatic history compression when a request
+// fails with a "context_length_exceeded" provider error: the oldest half of
+// the history is summarized by model (a cheaper/faster model is recommended)
+// and the original request is transparently retried once. The number of
+// turns summarized by the most recent Say call is available via
+// LastSummarizedTurns.
+func (c *Conversation) WithAutoSummarize(model Model) *Conversation {
+	c.autoSummarizeModel = model
+	return c
+}
+
+// LastSummarizedTurns returns how many turns were compressed by auto-summarize
+// (see WithAutoSummarize) during the most recent Say call. It is 0 if no
+// overflow occurred.
+func (c *Conversation) LastSummarizedTurns() int {
+	return c.lastSummarizedTurns
+}
+
+// WithModel switches the model used for subsequent turns while keeping the
+// existing history intact, e.g. moving from a cheap model for small talk to
+// a stronger one for a hard question. Message content is provider-agnostic,
+// so prior turns remain valid across the switch; buildMessages re-resolves
+// per-model details (such as OpenAI's "developer" vs "system" role) for the
+// new model on the next Say.
+func (c *Conversation) WithModel(m Model) *Conversation {
+	c.builder.model = m
+	return c
+}
+
+// Model returns the model currently used for subsequent turns.
+func (c *Conversation) Model() Model {
+	return c.builder.model
 }
 
 // Say sends a message to the AI and returns the response.
 // It appends the user's message and the AI's response to the conversation history.
 func (c *Conversation) Say(message string) (string, error) {
+	c.lastSummarizedTurns = 0
+
 	// Add user message to history
 	c.history = append(c.history, Message{Role: "user", Content: message})
 
-	// Build full message list
-	msgs := c.buildMessages()
-
-	content, _, err := Send(c.builder.model, msgs, SendOptions{
-		Temperature: c.builder.temperature,
-		Thinking:    c.builder.thinking,
-	})
+	content, err := c.send()
+	if err != nil && c.autoSummarizeModel != "" && isContextLengthExceeded(err) {
+		turns, summarizeErr := c.compressOldestHalf(c.autoSummarizeModel)
+		if summarizeErr != nil {
+			return "", err
+		}
+		c.lastSummarizedTurns = turns
+		content, err = c.send()
+	}
 	if err != nil {
 		return "", err
 	}
@@ -39,6 +83,33 @@ func (c *Conversation) Say(message string) (string, error) {
 	return content, nil
 }
 
+// send issues the current history to the model without mutating it.
+func (c *Conversation) send() (string, error) {
+	msgs := c.buildMessages()
+	content, _, err := Send(c.builder.model, msgs, SendOptions{
+		Temperature: c.builder.temperature,
+		Thinking:    c.builder.thinking,
+	})
+	return content, err
+}
+
+// isContextLengthExceeded reports whether err is a ProviderError carrying the
+// normalized ErrContextLengthExceeded code.
+func isContextLengthExceeded(err error) bool {
+	var perr *ProviderError
+	if errors.As(err, &perr) {
+		return perr.Code == ErrContextLengthExceeded
+	}
+	return false
+}
+
+// AddToolResult appends a tool-result message to the conversation history,
+// reporting the output of the call identified by callID back to the model.
+// Unlike Say, it does not itself send a request.
+func (c *Conversation) AddToolResult(callID, content string) {
+	c.history = append(c.history, Message{Role: "tool", ToolCallID: callID, Content: content})
+}
+
 // buildMessages combines the system prompt with the conversation history.
 // It also handles template substitution and context injection.
 func (c *Conversation) buildMessages() []Message {
@@ -51,11 +122,14 @@ func (c *Conversation) buildMessages() []Message {
 			system = applyTemplate(system, c.builder.vars)
 		}
 
-		// Add JSON instruction if enabled
-		if c.builder.jsonMode && system != "" {
-			system += "\n\nIMPORTANT: Respond with valid JSON only. No markdown, no explanation."
-		} else if c.builder.jsonMode {
-			system = "Respond with valid JSON only. No markdown, no explanation."
+		// Add JSON instruction if enabled, unless JSONMode(true) was used and
+		// the model natively supports JSON mode.
+		if c.builder.jsonMode && !(c.builder.jsonNative && Capabilities(c.builder.model).JSON) {
+			if system != "" {
+				system += "\n\nIMPORTANT: Respond with valid JSON only. No markdown, no explanation."
+			} else {
+				system = "Respond with valid JSON only. No markdown, no explanation."
+			}
 		}
 
 		// Add context to system if present
@@ -64,7 +138,7 @@ func (c *Conversation) buildMessages() []Message {
 			system += contextStr
 		}
 
-		msgs = append(msgs, Message{Role: "system", Content: system})
+		msgs = append(msgs, Message{Role: systemMessageRole(c.builder.model), Content: system})
 	}
 
 	// Add conversation history
@@ -147,3 +221,39 @@ func (c *Conversation) Summarize() string {
 
 	return sb.String()
 }
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Persistence
+// ═══════════════════════════════════════════════════════════════════════════
+
+// conversationFile is the on-disk JSON representation of a Conversation.
+type conversationFile struct {
+	Model   Model     `json:"model"`
+	System  string    `json:"system,omitempty"`
+	History []Message `json:"history"`
+}
+
+// Save serializes the conversation (model, system prompt, and message history,
+// including roles, tool calls, and tool results) to w as JSON.
+func (c *Conversation) Save(w io.Writer) error {
+	data := conversationFile{
+		Model:   c.builder.model,
+		System:  c.builder.system,
+		History: c.history,
+	}
+	return json.NewEncoder(w).Encode(data)
+}
+
+// LoadConversation restores a Conversation previously written by Save.
+// It creates a new Builder configured with the saved model and system prompt.
+func LoadConversation(r io.Reader) (*Conversation, error) {
+	var data conversationFile
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("load conversation: %w", err)
+	}
+
+	return &Conversation{
+		builder: New(data.Model).System(data.System),
+		history: data.History,
+	}, nil
+}
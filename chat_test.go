@@ -1,6 +1,8 @@
 package ai
 
 import (
+	"bytes"
+	"context"
 	"testing"
 )
 
@@ -153,6 +155,169 @@ func TestConversationBuildMessages(t *testing.T) {
 	if msgs[1].Content != "Hello" {
 		t.Errorf("expected 'Hello', got %q", msgs[1].Content)
 	}
+
+	// ModelGPT5 prefers the developer role over system.
+	if msgs[0].Role != "developer" {
+		t.Errorf("expected developer role for %s, got %q", ModelGPT5, msgs[0].Role)
+	}
+}
+
+func TestConversationSaveLoad(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5).System("You are helpful"),
+		history: []Message{
+			{Role: "user", Content: "Hello"},
+			{Role: "assistant", Content: "Hi!"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := chat.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadConversation(&buf)
+	if err != nil {
+		t.Fatalf("LoadConversation failed: %v", err)
+	}
+
+	if loaded.builder.model != ModelGPT5 {
+		t.Errorf("expected model %q, got %q", ModelGPT5, loaded.builder.model)
+	}
+	if loaded.builder.system != "You are helpful" {
+		t.Errorf("expected system prompt to round-trip, got %q", loaded.builder.system)
+	}
+	if len(loaded.history) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(loaded.history))
+	}
+	if loaded.history[1].Content != "Hi!" {
+		t.Errorf("expected 'Hi!', got %v", loaded.history[1].Content)
+	}
+}
+
+func TestConversationSaveLoadMultimodal(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "user", Content: []ContentPart{
+				{Type: "text", Text: "What's in this image?"},
+				{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/cat.png"}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := chat.Save(&buf); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadConversation(&buf)
+	if err != nil {
+		t.Fatalf("LoadConversation failed: %v", err)
+	}
+
+	parts, ok := loaded.history[0].Content.([]ContentPart)
+	if !ok {
+		t.Fatalf("expected Content to round-trip as []ContentPart, got %T", loaded.history[0].Content)
+	}
+	if len(parts) != 2 || parts[1].ImageURL == nil || parts[1].ImageURL.URL != "https://example.com/cat.png" {
+		t.Errorf("image part did not round-trip correctly: %+v", parts)
+	}
+}
+
+func TestConversationWithModel_SwitchesModelKeepingHistory(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotModel string
+	stub := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			gotModel = req.Model
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+	setDefaultClientForTest(t, stub, ProviderOpenAI)
+
+	chat := New(ModelGPT5Mini).Chat()
+	chat.history = []Message{
+		{Role: "user", Content: "small talk"},
+		{Role: "assistant", Content: "sure"},
+	}
+
+	chat.WithModel(ModelGPT5)
+	if chat.Model() != ModelGPT5 {
+		t.Fatalf("expected Model() to report %q, got %q", ModelGPT5, chat.Model())
+	}
+
+	if _, err := chat.Say("a hard question"); err != nil {
+		t.Fatalf("Say failed: %v", err)
+	}
+	if gotModel != string(ModelGPT5) {
+		t.Errorf("expected request to use %q, got %q", ModelGPT5, gotModel)
+	}
+	if len(chat.History()) != 4 {
+		t.Errorf("expected prior turns to be kept, got %d messages", len(chat.History()))
+	}
+}
+
+func TestConversationAutoSummarizeOnContextLengthExceeded(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	calls := 0
+	stub := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			if calls == 1 {
+				return nil, &ProviderError{Provider: "stub", Code: "context_length_exceeded", Message: "too long"}
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+	setDefaultClientForTest(t, stub, ProviderOpenAI)
+
+	chat := New(ModelGPT5).Chat().WithAutoSummarize(ModelGPT4oMini)
+	chat.history = []Message{
+		{Role: "user", Content: "first message"},
+		{Role: "assistant", Content: "first response"},
+		{Role: "user", Content: "second message"},
+		{Role: "assistant", Content: "second response"},
+	}
+
+	reply, err := chat.Say("latest question")
+	if err != nil {
+		t.Fatalf("Say failed: %v", err)
+	}
+	if reply != "ok" {
+		t.Errorf("expected 'ok', got %q", reply)
+	}
+	if chat.LastSummarizedTurns() == 0 {
+		t.Error("expected some turns to be reported as summarized")
+	}
+	if calls < 2 {
+		t.Errorf("expected the request to be retried after summarizing, got %d calls", calls)
+	}
+}
+
+func TestConversationAddToolResult(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_123"}}},
+		},
+	}
+
+	chat.AddToolResult("call_123", "42")
+
+	if len(chat.history) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(chat.history))
+	}
+	last := chat.history[1]
+	if last.Role != "tool" || last.ToolCallID != "call_123" || last.Content != "42" {
+		t.Errorf("unexpected tool result message: %+v", last)
+	}
 }
 
 func TestConversationBuildMessagesNoSystem(t *testing.T) {
@@ -0,0 +1,136 @@
+package ai
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Circuit Breaker
+// ═══════════════════════════════════════════════════════════════════════════
+
+// CircuitBreakerConfig configures the optional per-provider/model circuit
+// breaker set via Builder.CircuitBreaker. After FailureThreshold consecutive
+// failures for a given provider+model, the breaker opens and short-circuits
+// further attempts with a *CircuitOpenError for CooldownPeriod, then
+// half-opens to let a single probe attempt through.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Consecutive failures before the breaker opens (default: 5)
+	CooldownPeriod   time.Duration // How long the breaker stays open before probing again (default: 30s)
+}
+
+// DefaultCircuitBreakerConfig returns a sensible default configuration.
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		FailureThreshold: 5,
+		CooldownPeriod:   30 * time.Second,
+	}
+}
+
+// CircuitBreaker opts this request into the circuit breaker: models whose
+// breaker is open are skipped (moving straight to the next fallback, if
+// any) instead of paying the full timeout and retry cost of a call that's
+// very likely to fail. Pass nil to disable it (the default).
+func (b *Builder) CircuitBreaker(config *CircuitBreakerConfig) *Builder {
+	b.circuitBreaker = config
+	return b
+}
+
+// CircuitOpenError is returned when CircuitBreaker is enabled and a model's
+// breaker is open, short-circuiting the attempt instead of calling the
+// provider.
+type CircuitOpenError struct {
+	Provider string
+	Model    string
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s/%s", e.Provider, e.Model)
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker tracks consecutive-failure state for one provider+model
+// pair across requests.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            circuitState
+	consecutiveFails int
+	openedAt         time.Time
+}
+
+var (
+	circuitBreakersMu sync.Mutex
+	circuitBreakers   = map[string]*circuitBreaker{}
+)
+
+// circuitBreakerFor returns the breaker for provider+model, creating one on
+// first use.
+func circuitBreakerFor(provider, model string) *circuitBreaker {
+	key := provider + ":" + model
+
+	circuitBreakersMu.Lock()
+	defer circuitBreakersMu.Unlock()
+
+	cb, ok := circuitBreakers[key]
+	if !ok {
+		cb = &circuitBreaker{}
+		circuitBreakers[key] = cb
+	}
+	return cb
+}
+
+// ResetCircuitBreakers clears all circuit breaker state (useful for testing).
+func ResetCircuitBreakers() {
+	circuitBreakersMu.Lock()
+	defer circuitBreakersMu.Unlock()
+
+	circuitBreakers = map[string]*circuitBreaker{}
+}
+
+// allow reports whether a request may proceed. An open breaker half-opens
+// itself once CooldownPeriod has elapsed, letting exactly one probe through.
+func (cb *circuitBreaker) allow(config *CircuitBreakerConfig) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state != circuitOpen {
+		return true
+	}
+	if time.Since(cb.openedAt) < config.CooldownPeriod {
+		return false
+	}
+	cb.state = circuitHalfOpen
+	return true
+}
+
+// recordSuccess closes the breaker and resets the failure count.
+func (cb *circuitBreaker) recordSuccess() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = circuitClosed
+	cb.consecutiveFails = 0
+}
+
+// recordFailure counts the failure, opening the breaker once
+// FailureThreshold consecutive failures are reached, or immediately if the
+// failing attempt was the half-open probe.
+func (cb *circuitBreaker) recordFailure(config *CircuitBreakerConfig) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.consecutiveFails++
+	if cb.state == circuitHalfOpen || cb.consecutiveFails >= config.FailureThreshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
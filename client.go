@@ -59,6 +59,29 @@ func NewClientWithProvider(provider Provider) *Client {
 	}
 }
 
+// idleConnectionCloser is implemented by providers backed by an
+// *http.Client, letting Client.Close release idle connections without the
+// Provider interface needing to expose transport details to every
+// implementation.
+type idleConnectionCloser interface {
+	CloseIdleConnections()
+}
+
+// Close releases any idle connections held by the underlying provider's
+// HTTP transport. Long-running services that create many Clients should
+// call this on shutdown (or whenever a Client is discarded) to avoid
+// leaking idle connections; it's a no-op for providers that don't hold
+// pooled connections.
+//
+// getDefaultClient's Client is a process-wide singleton reused across
+// every call that doesn't supply WithClient - don't call Close on it
+// unless the whole process is shutting down.
+func (c *Client) Close() {
+	if closer, ok := c.provider.(idleConnectionCloser); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
 // Provider returns the underlying provider interface.
 func (c *Client) Provider() Provider {
 	return c.provider
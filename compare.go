@@ -77,7 +77,7 @@ func (c *CompareBuilder) On(models ...Model) []CompareResult {
 			oldPretty := Pretty
 			Pretty = false
 
-			msgs := b.User(c.prompt).buildMessages()
+			msgs := b.User(c.prompt).buildMessages(getDefaultClient().providerType)
 			content, resp, err := Send(m, msgs)
 
 			Pretty = oldPretty
@@ -25,6 +25,20 @@ func TestSetDefaultProvider_ResetsDefaultClient(t *testing.T) {
 	}
 }
 
+func TestClientClose_ClosesIdleConnectionsOnSupportingProviders(t *testing.T) {
+	c := NewClient(ProviderOpenAI, WithAPIKey("k"))
+
+	// NewOpenAIProvider's httpClient implements CloseIdleConnections; this
+	// just needs to not panic when Client.Close reaches it.
+	c.Close()
+}
+
+func TestClientClose_NoopForProvidersWithoutIdleConnections(t *testing.T) {
+	c := &Client{provider: &stubProvider{name: "stub"}, providerType: ProviderOpenAI}
+
+	c.Close()
+}
+
 func TestNewClient_OptionsAreApplied(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -0,0 +1,208 @@
+package ai
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Directory Context Injection
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ContextDir walks root recursively and adds every non-binary file under it
+// to the context, the same way Context does for a single matched file.
+// Unlike Context's filepath.Glob (which can't recurse with "**"), ContextDir
+// always walks the full subtree. It skips the .git directory, honors the
+// .gitignore at root, and skips files that content-sniffing detects as
+// binary. opts applies to each file the way it does for ContextWith.
+func (b *Builder) ContextDir(root string, opts ContextOptions) *Builder {
+	ignore := loadGitignore(root)
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries rather than aborting the walk
+		}
+		if path == root {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" || ignore.match(rel, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ignore.match(rel, false) {
+			return nil
+		}
+
+		data, readErr := os.ReadFile(path)
+		if readErr != nil || isBinary(data) {
+			return nil
+		}
+
+		b.fileContext = append(b.fileContext, fmt.Sprintf("--- %s ---\n%s", path, formatContext(string(data), opts, b.model)))
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("%s Error walking context dir %s: %v\n", colorRed("✗"), root, err)
+	}
+	return b
+}
+
+// isBinary reports whether data looks like binary content rather than text:
+// a NUL byte in the first 512 bytes, or a sniffed MIME type that's neither
+// text nor JSON/XML.
+func isBinary(data []byte) bool {
+	sample := data
+	if len(sample) > 512 {
+		sample = sample[:512]
+	}
+	if bytes.IndexByte(sample, 0) != -1 {
+		return true
+	}
+
+	contentType := http.DetectContentType(sample)
+	if strings.HasPrefix(contentType, "text/") {
+		return false
+	}
+	return contentType != "application/json" && !strings.Contains(contentType, "xml")
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Minimal .gitignore Matching
+// ═══════════════════════════════════════════════════════════════════════════
+
+// gitignoreMatcher matches paths against the patterns loaded from a single
+// .gitignore file. It supports the common subset of gitignore syntax:
+// comments, blank lines, "!" negation, a trailing "/" for directory-only
+// patterns, a leading "/" to anchor to the .gitignore's own directory, and
+// "*"/"**"/"?" wildcards. It does not merge nested .gitignore files further
+// down the tree.
+type gitignoreMatcher struct {
+	patterns []gitignorePattern
+}
+
+type gitignorePattern struct {
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// loadGitignore reads root/.gitignore, if present, into a gitignoreMatcher.
+// A missing or unreadable file yields an empty matcher that ignores nothing.
+func loadGitignore(root string) *gitignoreMatcher {
+	m := &gitignoreMatcher{}
+
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return m
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if p, ok := compileGitignorePattern(line); ok {
+			m.patterns = append(m.patterns, p)
+		}
+	}
+	return m
+}
+
+// match reports whether relPath (relative to the .gitignore's directory) is
+// ignored. Later patterns override earlier ones, matching git's own
+// last-match-wins precedence.
+func (m *gitignoreMatcher) match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+
+	ignored := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// compileGitignorePattern parses a single .gitignore line, returning false
+// if the line is blank or a comment.
+func compileGitignorePattern(line string) (gitignorePattern, bool) {
+	line = strings.TrimRight(line, " \t")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignorePattern{}, false
+	}
+
+	p := gitignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	anchored := strings.HasPrefix(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	reSrc := gitignoreGlobToRegex(line)
+	if !anchored {
+		reSrc = "^(?:.*/)?" + strings.TrimPrefix(reSrc, "^")
+	}
+
+	re, err := regexp.Compile(reSrc)
+	if err != nil {
+		return gitignorePattern{}, false
+	}
+	p.re = re
+	return p, true
+}
+
+// gitignoreGlobToRegex translates a gitignore glob (with any anchoring
+// slashes already stripped by the caller) into an anchored regex: "**"
+// matches any number of path segments, "*" matches within a single
+// segment, "?" matches one non-slash character, and every other regex
+// metacharacter is escaped.
+func gitignoreGlobToRegex(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for i := 0; i < len(pattern); {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i += 2
+			if i < len(pattern) && pattern[i] == '/' {
+				i++
+			}
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+			i++
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+			i++
+		case strings.ContainsRune(`.+()|^$\{}[]`, rune(pattern[i])):
+			sb.WriteByte('\\')
+			sb.WriteByte(pattern[i])
+			i++
+		default:
+			sb.WriteByte(pattern[i])
+			i++
+		}
+	}
+
+	sb.WriteString("$")
+	return sb.String()
+}
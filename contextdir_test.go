@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuilderContextDir_SkipsGitignoredFilesAndBinaries(t *testing.T) {
+	dir := t.TempDir()
+
+	mustWriteFile(t, filepath.Join(dir, ".gitignore"), "node_modules/\n*.log\n")
+	mustWriteFile(t, filepath.Join(dir, "main.go"), "package main")
+	mustWriteFile(t, filepath.Join(dir, "debug.log"), "noisy")
+	mustWriteFile(t, filepath.Join(dir, "node_modules", "lib.js"), "console.log(1)")
+	mustWriteFile(t, filepath.Join(dir, "image.png"), "\x89PNG\x00\x00\x00binarydata")
+
+	b := New(ModelGPT5).ContextDir(dir, ContextOptions{})
+
+	joined := strings.Join(b.fileContext, "\n")
+	if !strings.Contains(joined, "package main") {
+		t.Errorf("expected main.go to be included, got %q", joined)
+	}
+	if strings.Contains(joined, "noisy") {
+		t.Errorf("expected debug.log to be skipped via .gitignore, got %q", joined)
+	}
+	if strings.Contains(joined, "console.log") {
+		t.Errorf("expected node_modules to be skipped via .gitignore, got %q", joined)
+	}
+	if strings.Contains(joined, "binarydata") {
+		t.Errorf("expected image.png to be skipped as binary, got %q", joined)
+	}
+}
+
+func TestGitignoreMatcher_Negation(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, ".gitignore"), "*.log\n!keep.log\n")
+
+	m := loadGitignore(dir)
+
+	if !m.match("debug.log", false) {
+		t.Error("expected debug.log to be ignored")
+	}
+	if m.match("keep.log", false) {
+		t.Error("expected keep.log to be un-ignored by negation")
+	}
+}
+
+func TestGitignoreMatcher_DoubleStarMatchesNestedPaths(t *testing.T) {
+	dir := t.TempDir()
+	mustWriteFile(t, filepath.Join(dir, ".gitignore"), "vendor/**\n")
+
+	m := loadGitignore(dir)
+
+	if !m.match("vendor/pkg/file.go", false) {
+		t.Error("expected vendor/** to match a nested file under vendor/")
+	}
+	if m.match("src/vendor_helper.go", false) {
+		t.Error("did not expect vendor/** to match an unrelated file")
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if isBinary([]byte("hello, world")) {
+		t.Error("expected plain text to not be detected as binary")
+	}
+	if !isBinary([]byte("\x00\x01\x02binary")) {
+		t.Error("expected NUL-containing content to be detected as binary")
+	}
+}
+
+func mustWriteFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("failed to create dir for %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,47 @@
+package ai
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Length-Based Continuation
+// ═══════════════════════════════════════════════════════════════════════════
+
+// AutoContinue sends the request and, if the model stops because it hit the
+// output length cap (FinishReason == "length"), automatically sends follow-up
+// requests asking it to continue from where it left off. Parts are concatenated
+// into the returned ResponseMeta.Content, and token counts are accumulated
+// across all parts. It stops once the model finishes naturally or maxParts
+// parts have been collected (default 5 if maxParts <= 0).
+func (b *Builder) AutoContinue(maxParts int) *ResponseMeta {
+	if maxParts <= 0 {
+		maxParts = 5
+	}
+
+	var content string
+	var totalTokens, totalPrompt, totalCompletion int
+	var meta *ResponseMeta
+
+	next := b
+	for part := 0; part < maxParts; part++ {
+		meta = next.SendWithMeta()
+		if meta.Error != nil {
+			meta.Content = content + meta.Content
+			return meta
+		}
+
+		content += meta.Content
+		totalTokens += meta.Tokens
+		totalPrompt += meta.PromptTokens
+		totalCompletion += meta.CompletionTokens
+
+		if meta.FinishReason != "length" {
+			break
+		}
+
+		next = next.Clone().Assistant(meta.Content).User("Continue exactly where you left off. Do not repeat anything already written.")
+	}
+
+	meta.Content = content
+	meta.Tokens = totalTokens
+	meta.PromptTokens = totalPrompt
+	meta.CompletionTokens = totalCompletion
+	return meta
+}
@@ -33,6 +33,38 @@ const (
 // DefaultEmbeddingModel is the default embedding model used by Embed and EmbedMany.
 var DefaultEmbeddingModel = EmbedTextSmall3
 
+// embeddingDimensionLimits maps embedding models that support dimension
+// reduction (EmbeddingRequest.Dimensions) to their native output size and
+// the maximum dimensions they can be reduced to. Models not listed here
+// don't support reducing dimensions, so their Dimensions is passed through
+// unvalidated.
+var embeddingDimensionLimits = map[EmbeddingModel]int{
+	EmbedTextSmall3: 1536,
+	EmbedTextLarge3: 3072,
+}
+
+// resolveEmbeddingDimensions defaults dims to model's native dimension when
+// zero, and returns a typed ProviderError naming the limit when dims
+// exceeds what model supports, instead of letting an oversized request
+// reach the provider and fail with an opaque API error.
+func resolveEmbeddingDimensions(model EmbeddingModel, dims int) (int, error) {
+	limit, ok := embeddingDimensionLimits[model]
+	if !ok {
+		return dims, nil
+	}
+	if dims == 0 {
+		return limit, nil
+	}
+	if dims > limit {
+		return 0, &ProviderError{
+			Provider: "embed",
+			Code:     "dimensions_exceeded",
+			Message:  fmt.Sprintf("%s supports at most %d dimensions, got %d", model, limit, dims),
+		}
+	}
+	return dims, nil
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Embedding Request/Response
 // ═══════════════════════════════════════════════════════════════════════════
@@ -42,6 +74,17 @@ type EmbeddingRequest struct {
 	Model      string
 	Input      []string // texts to embed
 	Dimensions int      // optional: for models that support dimension reduction
+
+	// Normalize L2-normalizes each vector in EmbeddingResponse.Embeddings to
+	// unit length before it's returned, so callers doing cosine-similarity
+	// search don't need to re-normalize (and re-drift) on every comparison.
+	Normalize bool
+
+	// EncodingFormat requests a specific wire format from the provider, e.g.
+	// OpenAI's "base64" (a base64-encoded little-endian float32 payload,
+	// about 4x smaller than the default JSON float array). Ignored by
+	// providers that don't support an alternate encoding.
+	EncodingFormat string
 }
 
 // EmbeddingResponse is a provider-agnostic response format for embeddings.
@@ -52,17 +95,50 @@ type EmbeddingResponse struct {
 	Dimensions  int
 }
 
+// Normalized returns a copy of Embeddings with each vector L2-normalized to
+// unit length. It doesn't modify Embeddings, so it's safe to call even when
+// the request didn't set EmbeddingRequest.Normalize.
+func (r *EmbeddingResponse) Normalized() [][]float64 {
+	out := make([][]float64, len(r.Embeddings))
+	for i, v := range r.Embeddings {
+		out[i] = normalizeVector(v)
+	}
+	return out
+}
+
+// normalizeVector returns a new L2-normalized copy of v. The zero vector is
+// returned unchanged, since it has no direction to normalize to.
+func normalizeVector(v []float64) []float64 {
+	var normSq float64
+	for _, x := range v {
+		normSq += x * x
+	}
+
+	out := make([]float64, len(v))
+	if normSq == 0 {
+		copy(out, v)
+		return out
+	}
+	norm := sqrt(normSq)
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Embedding Builder - Fluent API
 // ═══════════════════════════════════════════════════════════════════════════
 
 // EmbedBuilder provides a fluent API for creating embeddings.
 type EmbedBuilder struct {
-	model      EmbeddingModel
-	texts      []string
-	dimensions int
-	client     *Client
-	ctx        context.Context
+	model          EmbeddingModel
+	texts          []string
+	dimensions     int
+	normalize      bool
+	encodingFormat string
+	client         *Client
+	ctx            context.Context
 }
 
 // Embed creates a new EmbedBuilder for a single text.
@@ -93,6 +169,21 @@ func (e *EmbedBuilder) Dimensions(d int) *EmbedBuilder {
 	return e
 }
 
+// Normalize L2-normalizes each returned vector to unit length.
+func (e *EmbedBuilder) Normalize() *EmbedBuilder {
+	e.normalize = true
+	return e
+}
+
+// EncodingFormat requests a specific wire format from the provider, e.g.
+// OpenAI's "base64", which is about 4x smaller over the wire than the
+// default JSON float array and meaningfully speeds up embedding large
+// batches of documents.
+func (e *EmbedBuilder) EncodingFormat(format string) *EmbedBuilder {
+	e.encodingFormat = format
+	return e
+}
+
 // WithClient sets a specific client/provider to execute the request with.
 func (e *EmbedBuilder) WithClient(client *Client) *EmbedBuilder {
 	e.client = client
@@ -142,10 +233,17 @@ func (e *EmbedBuilder) DoWithMeta() (*EmbeddingResponse, error) {
 		return nil, fmt.Errorf("provider %s does not support embeddings", client.provider.Name())
 	}
 
+	dims, err := resolveEmbeddingDimensions(e.model, e.dimensions)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &EmbeddingRequest{
-		Model:      string(e.model),
-		Input:      e.texts,
-		Dimensions: e.dimensions,
+		Model:          string(e.model),
+		Input:          e.texts,
+		Dimensions:     dims,
+		Normalize:      e.normalize,
+		EncodingFormat: e.encodingFormat,
 	}
 
 	if Debug {
@@ -158,6 +256,10 @@ func (e *EmbedBuilder) DoWithMeta() (*EmbeddingResponse, error) {
 		return nil, err
 	}
 
+	if req.Normalize {
+		resp.Embeddings = resp.Normalized()
+	}
+
 	if Debug {
 		fmt.Printf("%s Got %d embedding(s), dim=%d, tokens=%d\n",
 			colorGreen("✓"), len(resp.Embeddings), resp.Dimensions, resp.TotalTokens)
@@ -192,6 +294,31 @@ func (c *Client) EmbedMany(texts ...string) *EmbedBuilder {
 	return EmbedMany(texts...).WithClient(c)
 }
 
+// Embeddings generates embeddings for input using this client's provider. It
+// returns a clear error if the provider doesn't implement Embedder.
+func (c *Client) Embeddings(model Model, input ...string) (*EmbeddingResponse, error) {
+	embedder, ok := c.provider.(Embedder)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support embeddings", c.provider.Name())
+	}
+
+	req := &EmbeddingRequest{
+		Model: string(model),
+		Input: input,
+	}
+
+	waitForRateLimit()
+	return embedder.Embed(context.Background(), req)
+}
+
+// Embeddings generates embeddings for input using the default client. It
+// returns a clear error if the configured provider doesn't implement
+// Embedder, instead of leaving embeddings unreachable through the normal
+// client flow.
+func Embeddings(model Model, input ...string) (*EmbeddingResponse, error) {
+	return getDefaultClient().Embeddings(model, input...)
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Similarity Functions
 // ═══════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,186 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestClientEmbeddings_ReturnsEmbeddingsFromProvider(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubEmbedderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		embedFn: func(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+			return &EmbeddingResponse{Embeddings: [][]float64{{0.1, 0.2}}, Model: req.Model}, nil
+		},
+	}
+
+	client := &Client{provider: p, providerType: ProviderOpenAI}
+
+	resp, err := client.Embeddings(ModelGPT5, "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Embeddings) != 1 {
+		t.Fatalf("expected 1 embedding, got %d", len(resp.Embeddings))
+	}
+
+	reqs := p.EmbedRequests()
+	if len(reqs) != 1 || len(reqs[0].Input) != 1 || reqs[0].Input[0] != "hello" {
+		t.Errorf("unexpected embed request: %#v", reqs)
+	}
+}
+
+func TestClientEmbeddings_ErrorsWhenProviderIsNotEmbedder(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "stub"}
+	client := &Client{provider: p, providerType: ProviderOpenAI}
+
+	_, err := client.Embeddings(ModelGPT5, "hello")
+	if err == nil {
+		t.Fatal("expected error for provider without Embedder support")
+	}
+}
+
+func TestEmbeddings_UsesDefaultClient(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubEmbedderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		embedFn: func(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+			return &EmbeddingResponse{Embeddings: [][]float64{{1, 2, 3}}}, nil
+		},
+	}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	resp, err := Embeddings(ModelGPT5, "hello")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Embeddings) != 1 {
+		t.Fatalf("expected 1 embedding, got %d", len(resp.Embeddings))
+	}
+}
+
+func TestEmbeddingResponse_Normalized_DoesNotMutateOriginal(t *testing.T) {
+	resp := &EmbeddingResponse{Embeddings: [][]float64{{3, 4}}}
+
+	normalized := resp.Normalized()
+	if len(normalized) != 1 {
+		t.Fatalf("expected 1 vector, got %d", len(normalized))
+	}
+
+	mag := math.Sqrt(normalized[0][0]*normalized[0][0] + normalized[0][1]*normalized[0][1])
+	if math.Abs(mag-1) > 1e-9 {
+		t.Errorf("expected unit vector, got magnitude %v", mag)
+	}
+
+	if resp.Embeddings[0][0] != 3 || resp.Embeddings[0][1] != 4 {
+		t.Errorf("expected original Embeddings unchanged, got %v", resp.Embeddings)
+	}
+}
+
+func TestEmbeddingResponse_Normalized_ZeroVectorUnchanged(t *testing.T) {
+	resp := &EmbeddingResponse{Embeddings: [][]float64{{0, 0}}}
+
+	normalized := resp.Normalized()
+	if normalized[0][0] != 0 || normalized[0][1] != 0 {
+		t.Errorf("expected zero vector to stay zero, got %v", normalized[0])
+	}
+}
+
+func TestEmbedBuilder_Normalize_NormalizesReturnedVectors(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubEmbedderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		embedFn: func(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+			if !req.Normalize {
+				t.Errorf("expected req.Normalize to be true")
+			}
+			return &EmbeddingResponse{Embeddings: [][]float64{{3, 4}}}, nil
+		},
+	}
+
+	vectors, err := EmbedMany("hello").WithClient(&Client{provider: p, providerType: ProviderOpenAI}).Normalize().Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mag := math.Sqrt(vectors[0][0]*vectors[0][0] + vectors[0][1]*vectors[0][1])
+	if math.Abs(mag-1) > 1e-9 {
+		t.Errorf("expected unit vector, got magnitude %v", mag)
+	}
+}
+
+func TestEmbedBuilder_Dimensions_DefaultsToModelNativeWhenZero(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotDims int
+	p := &stubEmbedderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		embedFn: func(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+			gotDims = req.Dimensions
+			return &EmbeddingResponse{Embeddings: [][]float64{{1}}}, nil
+		},
+	}
+
+	_, err := Embed("hello").Model(EmbedTextSmall3).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotDims != 1536 {
+		t.Errorf("expected default dimensions of 1536, got %d", gotDims)
+	}
+}
+
+func TestEmbedBuilder_Dimensions_ErrorsWhenOverModelMax(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubEmbedderProvider{stubProvider: &stubProvider{name: "stub"}}
+
+	_, err := Embed("hello").Model(EmbedTextSmall3).Dimensions(4096).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).Do()
+	if err == nil {
+		t.Fatal("expected error for dimensions exceeding model max")
+	}
+	var perr *ProviderError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected a *ProviderError, got %T: %v", err, err)
+	}
+	if perr.Code != "dimensions_exceeded" {
+		t.Errorf("expected code dimensions_exceeded, got %q", perr.Code)
+	}
+}
+
+func TestEmbedBuilder_Dimensions_PassesThroughForModelsWithoutLimits(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotDims int
+	p := &stubEmbedderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		embedFn: func(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+			gotDims = req.Dimensions
+			return &EmbeddingResponse{Embeddings: [][]float64{{1}}}, nil
+		},
+	}
+
+	_, err := Embed("hello").Model(EmbedTextAda002).Dimensions(4096).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotDims != 4096 {
+		t.Errorf("expected unvalidated dimensions to pass through, got %d", gotDims)
+	}
+}
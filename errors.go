@@ -0,0 +1,62 @@
+package ai
+
+import "strings"
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Normalized Error Codes
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Normalized ProviderError.Code values. Each provider reports failures in its
+// own vocabulary (OpenAI's "model_not_found", Anthropic's "not_found_error",
+// Google's "NOT_FOUND" status, ...); classifyErrorCode maps the raw value to
+// one of these so callers can switch on a stable set of codes instead of
+// matching every vendor's wording.
+const (
+	ErrModelNotFound         = "model_not_found"
+	ErrContextLengthExceeded = "context_length_exceeded"
+	ErrRateLimited           = "rate_limited"
+	ErrAuth                  = "auth_error"
+	ErrBlockedBySafety       = "blocked_by_safety"
+)
+
+// classifyErrorCode maps a provider's raw error code/type and message to a
+// normalized sentinel code. raw is whatever the provider considers its error
+// identifier (OpenAI/OpenRouter's error.code, Anthropic's error.type,
+// Google's error.status); message is the human-readable error text, used as
+// a fallback signal for providers that don't carry a dedicated code for a
+// given failure (e.g. Anthropic reports context overflow as a plain
+// invalid_request_error with an explanatory message). Returns raw unchanged
+// if it doesn't match a known classification, so existing Code values keep
+// working for callers that still match on vendor-specific strings.
+func classifyErrorCode(raw, message string) string {
+	lowerRaw := strings.ToLower(raw)
+	lowerMsg := strings.ToLower(message)
+
+	switch {
+	case strings.Contains(lowerRaw, "context_length"),
+		strings.Contains(lowerMsg, "context length"),
+		strings.Contains(lowerMsg, "maximum context"),
+		strings.Contains(lowerMsg, "too many tokens"):
+		return ErrContextLengthExceeded
+
+	case strings.Contains(lowerRaw, "not_found"),
+		strings.Contains(lowerRaw, "notfound"),
+		strings.Contains(lowerMsg, "does not exist"),
+		strings.Contains(lowerMsg, "model") && strings.Contains(lowerMsg, "not found"):
+		return ErrModelNotFound
+
+	case strings.Contains(lowerRaw, "rate_limit"),
+		strings.Contains(lowerRaw, "resource_exhausted"),
+		strings.Contains(lowerRaw, "429"):
+		return ErrRateLimited
+
+	case strings.Contains(lowerRaw, "auth"),
+		strings.Contains(lowerRaw, "permission_denied"),
+		strings.Contains(lowerRaw, "unauthenticated"),
+		strings.Contains(lowerRaw, "api_key"):
+		return ErrAuth
+
+	default:
+		return raw
+	}
+}
@@ -0,0 +1,34 @@
+package ai
+
+import "testing"
+
+func TestClassifyErrorCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		message string
+		want    string
+	}{
+		{"openai model not found", "model_not_found", "The model 'gpt-9' does not exist", ErrModelNotFound},
+		{"openai context length", "context_length_exceeded", "maximum context length is 4096 tokens", ErrContextLengthExceeded},
+		{"openai rate limit", "rate_limit_exceeded", "Rate limit reached", ErrRateLimited},
+		{"openai auth", "invalid_api_key", "Incorrect API key provided", ErrAuth},
+		{"anthropic not found", "not_found_error", "model: claude-9 not found", ErrModelNotFound},
+		{"anthropic rate limit", "rate_limit_error", "Number of requests has exceeded your rate limit", ErrRateLimited},
+		{"anthropic auth", "authentication_error", "invalid x-api-key", ErrAuth},
+		{"anthropic context length via message", "invalid_request_error", "prompt is too long: maximum context length exceeded", ErrContextLengthExceeded},
+		{"google not found", "NOT_FOUND", "Model not found", ErrModelNotFound},
+		{"google rate limit", "RESOURCE_EXHAUSTED", "Quota exceeded", ErrRateLimited},
+		{"google auth", "UNAUTHENTICATED", "API key invalid", ErrAuth},
+		{"unclassified passes through", "some_weird_error", "unrelated message", "some_weird_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyErrorCode(tt.raw, tt.message)
+			if got != tt.want {
+				t.Errorf("classifyErrorCode(%q, %q) = %q, want %q", tt.raw, tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,10 +3,13 @@ package ai
 import (
 	"context"
 	"errors"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestStreamResponse_FallsBackWhenProviderDoesNotSupportStreaming(t *testing.T) {
@@ -176,6 +179,50 @@ func TestAudio_TTSAndSTT_BuildersPassThroughFields(t *testing.T) {
 	}
 }
 
+func TestAudio_STT_DiarizePassesThroughSpeakerCount(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	core := &stubProvider{name: "stub", caps: ProviderCapabilities{STT: true}}
+	ap := &stubAudioProvider{
+		stubProvider: core,
+		sttFn: func(ctx context.Context, req *STTRequest) (*STTResponse, error) {
+			if !req.Diarize {
+				t.Fatalf("expected diarize enabled")
+			}
+			if req.SpeakerCount != 2 {
+				t.Fatalf("expected speaker count 2, got %d", req.SpeakerCount)
+			}
+			return &STTResponse{
+				Text: "hello there",
+				Segments: []TranscriptSegment{
+					{Speaker: "speaker_1", Text: "hello", Start: 0, End: 0.5},
+					{Speaker: "speaker_2", Text: "there", Start: 0.5, End: 1},
+				},
+			}, nil
+		},
+	}
+	setDefaultClientForTest(t, ap, ProviderOpenAI)
+
+	resp, err := TranscribeBytes([]byte("audio"), "a.mp3").Model(STTGpt4oAudioDiarize).Diarize(2).DoWithMeta()
+	if err != nil {
+		t.Fatalf("unexpected STT error: %v", err)
+	}
+	if len(resp.Segments) != 2 || resp.Segments[0].Speaker != "speaker_1" {
+		t.Fatalf("unexpected STT resp: %#v", resp)
+	}
+	if speakers := resp.Speakers(); len(speakers) != 2 || speakers[0] != "speaker_1" || speakers[1] != "speaker_2" {
+		t.Fatalf("unexpected Speakers(): %#v", speakers)
+	}
+}
+
+func TestSTTResponse_Speakers_EmptyWhenNotDiarized(t *testing.T) {
+	resp := &STTResponse{Text: "hello"}
+	if speakers := resp.Speakers(); speakers != nil {
+		t.Fatalf("expected nil speakers, got %#v", speakers)
+	}
+}
+
 func TestVisionAndPDF_BuildMessages_MultimodalLastUserMessage(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -190,7 +237,7 @@ func TestVisionAndPDF_BuildMessages_MultimodalLastUserMessage(t *testing.T) {
 		User("hello").
 		Image(imgPath).
 		PDF(pdfPath).
-		buildMessages()
+		buildMessages(ProviderOpenAI)
 
 	if len(msgs) == 0 {
 		t.Fatalf("expected messages")
@@ -292,6 +339,352 @@ func TestRetry_WithRetry_RetriesOnProviderError(t *testing.T) {
 	}
 }
 
+func TestCalculateBackoff_JitterNoneIsDeterministic(t *testing.T) {
+	config := DefaultRetryConfig().WithInitialDelay(time.Second).WithMultiplier(2.0).WithMaxDelay(time.Minute).WithJitterMode(JitterNone)
+
+	for attempt := 0; attempt < 3; attempt++ {
+		want := time.Duration(float64(time.Second) * math.Pow(2.0, float64(attempt)))
+		got := calculateBackoff(config, attempt)
+		if got != want {
+			t.Fatalf("attempt %d: expected %v, got %v", attempt, want, got)
+		}
+	}
+}
+
+func TestCalculateBackoff_JitterFullStaysWithinCappedDelay(t *testing.T) {
+	config := DefaultRetryConfig().WithInitialDelay(time.Second).WithMultiplier(2.0).WithMaxDelay(time.Minute).WithJitterMode(JitterFull)
+
+	maxDelay := time.Duration(float64(time.Second) * math.Pow(2.0, 2))
+	for i := 0; i < 20; i++ {
+		got := calculateBackoff(config, 2)
+		if got < 0 || got > maxDelay {
+			t.Fatalf("expected delay in [0, %v], got %v", maxDelay, got)
+		}
+	}
+}
+
+func TestCalculateBackoff_JitterEqualStaysAboveHalfCappedDelay(t *testing.T) {
+	config := DefaultRetryConfig().WithInitialDelay(time.Second).WithMultiplier(2.0).WithMaxDelay(time.Minute).WithJitterMode(JitterEqual)
+
+	maxDelay := time.Duration(float64(time.Second) * math.Pow(2.0, 2))
+	for i := 0; i < 20; i++ {
+		got := calculateBackoff(config, 2)
+		if got < maxDelay/2 || got > maxDelay {
+			t.Fatalf("expected delay in [%v, %v], got %v", maxDelay/2, maxDelay, got)
+		}
+	}
+}
+
+func TestWithRetry_StopsOnceMaxElapsedWouldBeExceeded(t *testing.T) {
+	config := DefaultRetryConfig().
+		WithInitialDelay(20 * time.Millisecond).
+		WithMultiplier(1.0).
+		WithJitterMode(JitterNone).
+		WithMaxRetries(10).
+		WithMaxElapsed(30 * time.Millisecond)
+
+	attempts := 0
+	_, err := WithRetry(context.Background(), config, func() (string, error) {
+		attempts++
+		return "", &ProviderError{Provider: "x", Code: "429", Message: "rate limit"}
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "max elapsed time") {
+		t.Fatalf("expected max elapsed time error, got: %v", err)
+	}
+	if attempts >= 10 {
+		t.Fatalf("expected MaxElapsed to cut attempts well short of MaxRetries, got %d attempts", attempts)
+	}
+}
+
+func TestWithRetry_ZeroMaxElapsedDoesNotCapRetries(t *testing.T) {
+	config := noSleepRetryConfig(2)
+
+	attempts := 0
+	_, err := WithRetry(context.Background(), config, func() (string, error) {
+		attempts++
+		return "", &ProviderError{Provider: "x", Code: "429", Message: "rate limit"}
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts (MaxRetries=2 + initial), got %d", attempts)
+	}
+}
+
+func TestBuilder_Background_ReturnsErrorWhenProviderUnsupported(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "stub"}
+	_, err := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hi").Background()
+	if err == nil || !strings.Contains(err.Error(), "does not support background") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
+
+func TestBackgroundJob_PollReturnsNotDoneUntilCompleted(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	status := "in_progress"
+	bp := &stubBackgroundProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		startBackgroundFn: func(ctx context.Context, req *ProviderRequest) (string, error) {
+			if !req.Background {
+				t.Fatal("expected req.Background to be true")
+			}
+			return "job-1", nil
+		},
+		pollResponseFn: func(ctx context.Context, id string) (*ProviderResponse, string, error) {
+			if id != "job-1" {
+				t.Fatalf("unexpected id: %q", id)
+			}
+			if status != "completed" {
+				return nil, status, nil
+			}
+			return &ProviderResponse{Content: "deep research result"}, status, nil
+		},
+	}
+
+	job, err := New(Model("o3-deep-research")).WithClient(&Client{provider: bp, providerType: ProviderOpenAI}).User("research this").Background()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.ID() != "job-1" {
+		t.Fatalf("expected job ID job-1, got %q", job.ID())
+	}
+
+	meta, done, err := job.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if done {
+		t.Fatal("expected not done while status is in_progress")
+	}
+	if meta != nil {
+		t.Fatalf("expected nil meta while pending, got %#v", meta)
+	}
+
+	status = "completed"
+	meta, done, err = job.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !done {
+		t.Fatal("expected done once status is completed")
+	}
+	if meta.Content != "deep research result" {
+		t.Fatalf("unexpected content: %q", meta.Content)
+	}
+}
+
+func TestBackgroundJob_AwaitPollsUntilCompleted(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	polls := 0
+	bp := &stubBackgroundProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		startBackgroundFn: func(ctx context.Context, req *ProviderRequest) (string, error) {
+			return "job-1", nil
+		},
+		pollResponseFn: func(ctx context.Context, id string) (*ProviderResponse, string, error) {
+			polls++
+			if polls < 3 {
+				return nil, "in_progress", nil
+			}
+			return &ProviderResponse{Content: "done"}, "completed", nil
+		},
+	}
+
+	job, err := New(Model("o3-deep-research")).WithClient(&Client{provider: bp, providerType: ProviderOpenAI}).User("research this").Background()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	meta, err := job.Await(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.Content != "done" {
+		t.Fatalf("unexpected content: %q", meta.Content)
+	}
+	if polls != 3 {
+		t.Fatalf("expected 3 polls, got %d", polls)
+	}
+}
+
+func TestBuilder_CircuitBreaker_OpensAfterThresholdAndSkipsToFallback(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	ResetCircuitBreakers()
+	defer ResetCircuitBreakers()
+
+	primaryAttempts := 0
+	primary := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			primaryAttempts++
+			return nil, &ProviderError{Provider: "stub", Code: "500", Message: "boom"}
+		},
+	}
+	breaker := (&CircuitBreakerConfig{FailureThreshold: 2, CooldownPeriod: time.Minute})
+
+	newBuilder := func() *Builder {
+		return New(ModelGPT5).
+			WithClient(&Client{provider: primary, providerType: ProviderOpenAI}).
+			CircuitBreaker(breaker).
+			NoRetry().
+			User("hi")
+	}
+
+	// First two requests fail normally and trip the breaker.
+	for i := 0; i < 2; i++ {
+		meta := newBuilder().SendWithMeta()
+		if meta.Error == nil {
+			t.Fatalf("expected error on attempt %d", i)
+		}
+	}
+	if primaryAttempts != 2 {
+		t.Fatalf("expected 2 attempts before breaker opens, got %d", primaryAttempts)
+	}
+
+	// Third request should be short-circuited without calling the provider.
+	meta := newBuilder().SendWithMeta()
+	var circuitErr *CircuitOpenError
+	if !errors.As(meta.Error, &circuitErr) {
+		t.Fatalf("expected CircuitOpenError, got %v", meta.Error)
+	}
+	if primaryAttempts != 2 {
+		t.Fatalf("expected breaker to skip the provider call, got %d attempts", primaryAttempts)
+	}
+}
+
+func TestBuilder_CircuitBreaker_SkipsOpenModelAndUsesFallback(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	ResetCircuitBreakers()
+	defer ResetCircuitBreakers()
+
+	breaker := &CircuitBreakerConfig{FailureThreshold: 1, CooldownPeriod: time.Minute}
+	client := &Client{provider: &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if req.Model == string(ModelGPT5) {
+				return nil, &ProviderError{Provider: "stub", Code: "500", Message: "boom"}
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}, providerType: ProviderOpenAI}
+
+	// Trip the breaker for the primary model.
+	meta := New(ModelGPT5).WithClient(client).CircuitBreaker(breaker).NoRetry().User("hi").SendWithMeta()
+	if meta.Error == nil {
+		t.Fatal("expected first call to fail and trip the breaker")
+	}
+
+	calls := 0
+	countingClient := &Client{provider: &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			if req.Model == string(ModelGPT5) {
+				t.Fatalf("expected primary model to be skipped while breaker is open")
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}, providerType: ProviderOpenAI}
+
+	meta = New(ModelGPT5).WithClient(countingClient).CircuitBreaker(breaker).Fallback(ModelGPT4o).NoRetry().User("hi").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("expected fallback to succeed, got %v", meta.Error)
+	}
+	if meta.Content != "ok" || meta.Model != ModelGPT4o {
+		t.Fatalf("expected fallback model response, got %#v", meta)
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly 1 provider call (fallback only), got %d", calls)
+	}
+}
+
+func TestImageEditBuilder_DoWithMeta_ReturnsErrorWhenProviderUnsupported(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "stub"}
+	_, err := EditImageBytes([]byte("fake-png"), "add a hat").WithClient(&Client{provider: p, providerType: ProviderOpenAI}).DoWithMeta()
+	if err == nil || !strings.Contains(err.Error(), "does not support image editing") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
+
+func TestImageEditBuilder_DoWithMeta_PassesMaskAndPrompt(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	ip := &stubImageProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		editImageFn: func(ctx context.Context, req *ImageEditRequest) (*ImageEditResponse, error) {
+			if req.Prompt != "add a hat" {
+				t.Fatalf("unexpected prompt: %q", req.Prompt)
+			}
+			if string(req.Mask) != "fake-mask" {
+				t.Fatalf("unexpected mask: %q", req.Mask)
+			}
+			return &ImageEditResponse{Images: [][]byte{[]byte("edited-png")}}, nil
+		},
+	}
+
+	img, err := EditImageBytes([]byte("fake-png"), "add a hat").
+		MaskBytes([]byte("fake-mask")).
+		WithClient(&Client{provider: ip, providerType: ProviderOpenAI}).
+		Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(img) != "edited-png" {
+		t.Fatalf("unexpected image bytes: %q", img)
+	}
+}
+
+func TestImageVariationBuilder_DoWithMeta_ReturnsErrorWhenProviderUnsupported(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "stub"}
+	_, err := ImageVariationBytes([]byte("fake-png")).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).DoWithMeta()
+	if err == nil || !strings.Contains(err.Error(), "does not support image variations") {
+		t.Fatalf("expected unsupported provider error, got %v", err)
+	}
+}
+
+func TestImageVariationBuilder_Do_DefaultsNToOne(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	ip := &stubImageProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		imageVariFn: func(ctx context.Context, req *ImageVariationRequest) (*ImageEditResponse, error) {
+			if req.N != 1 {
+				t.Fatalf("expected default N=1, got %d", req.N)
+			}
+			return &ImageEditResponse{Images: [][]byte{[]byte("variation-png")}}, nil
+		},
+	}
+
+	img, err := ImageVariationBytes([]byte("fake-png")).WithClient(&Client{provider: ip, providerType: ProviderOpenAI}).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(img) != "variation-png" {
+		t.Fatalf("unexpected image bytes: %q", img)
+	}
+}
+
 func TestBuilder_RetryConfig_RetriesAndCountsRetries(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -324,3 +717,140 @@ func TestBuilder_RetryConfig_RetriesAndCountsRetries(t *testing.T) {
 		t.Fatalf("expected Retries=2, got %d", meta.Retries)
 	}
 }
+
+func TestBuilder_OnRetry_CalledForEachSmartRetryAttempt(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	attempts := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, &ProviderError{Provider: "stub", Code: "429", Message: "rate limit"}
+			}
+			return &ProviderResponse{Content: "ok", TotalTokens: 1}, nil
+		},
+	}
+
+	var calls []int
+	var lastErr error
+	b := New(ModelGPT5).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		RetryConfig(noSleepRetryConfig(2)).
+		OnRetry(func(attempt int, err error) {
+			calls = append(calls, attempt)
+			lastErr = err
+		}).
+		User("hi")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if !reflect.DeepEqual(calls, []int{1, 2}) {
+		t.Fatalf("expected OnRetry called with attempts [1 2], got %v", calls)
+	}
+	if lastErr == nil {
+		t.Fatal("expected OnRetry to receive the error that triggered the retry")
+	}
+}
+
+func TestBuilder_OnRetry_CalledForEachLegacyRetryAttempt(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	attempts := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			attempts++
+			if attempts < 3 {
+				return nil, &ProviderError{Provider: "stub", Message: "boom"}
+			}
+			return &ProviderResponse{Content: "ok", TotalTokens: 1}, nil
+		},
+	}
+
+	var calls []int
+	b := New(ModelGPT5).
+		WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Retry(2).
+		OnRetry(func(attempt int, err error) {
+			calls = append(calls, attempt)
+		}).
+		User("hi")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if !reflect.DeepEqual(calls, []int{1, 2}) {
+		t.Fatalf("expected OnRetry called with attempts [1 2], got %v", calls)
+	}
+}
+
+func TestBuilder_SendWithMeta_ReturnsCachedOnSecondIdenticalCall(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	Cache = true
+	defer ClearCache()
+
+	calls := 0
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			calls++
+			return &ProviderResponse{Content: "echo", TotalTokens: 1}, nil
+		},
+	}
+
+	newBuilder := func() *Builder {
+		return New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hi")
+	}
+
+	meta1 := newBuilder().SendWithMeta()
+	if meta1.Error != nil {
+		t.Fatalf("unexpected error: %v", meta1.Error)
+	}
+	if meta1.Cached {
+		t.Fatal("first call should not be served from cache")
+	}
+
+	meta2 := newBuilder().SendWithMeta()
+	if meta2.Error != nil {
+		t.Fatalf("unexpected error: %v", meta2.Error)
+	}
+	if !meta2.Cached {
+		t.Fatal("second identical call should be served from cache")
+	}
+	if meta2.Content != "echo" {
+		t.Fatalf("expected cached content %q, got %q", "echo", meta2.Content)
+	}
+	if calls != 1 {
+		t.Fatalf("expected provider to be called once, got %d", calls)
+	}
+}
+
+func TestBuilder_SendWithMeta_PropagatesCachedTokens(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "echo", TotalTokens: 10, CachedTokens: 7}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hi")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.CachedTokens != 7 {
+		t.Fatalf("expected CachedTokens 7, got %d", meta.CachedTokens)
+	}
+}
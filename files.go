@@ -0,0 +1,73 @@
+package ai
+
+import (
+	gocontext "context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// File Attachments
+// ═══════════════════════════════════════════════════════════════════════════
+
+// FileUploader is implemented by providers that support uploading a file
+// out of band and referencing it by ID in later requests, instead of
+// inlining its content into the prompt. Providers that don't implement it
+// get the file's content injected as text context instead (see
+// Builder.AttachFile).
+type FileUploader interface {
+	UploadFile(ctx gocontext.Context, name string, data []byte, mimeType string) (string, error)
+}
+
+// FileRef references a file previously uploaded via FileUploader, included
+// as a content part in a request.
+type FileRef struct {
+	FileID string `json:"file_id"`
+	Name   string `json:"name,omitempty"`
+}
+
+// AttachFile queues a local file to be attached to the request. If the
+// active provider implements FileUploader, the file is uploaded once via
+// the provider's files endpoint and referenced by the returned ID instead
+// of being inlined into the prompt; providers without upload support fall
+// back to injecting its content as text context, the same as Context.
+func (b *Builder) AttachFile(path string) *Builder {
+	b.attachedFiles = append(b.attachedFiles, path)
+	return b
+}
+
+// resolveAttachedFiles uploads (or, for providers without FileUploader
+// support, reads as text context) every path queued by AttachFile.
+func (b *Builder) resolveAttachedFiles(ctx gocontext.Context, client *Client) {
+	uploader, supportsUpload := client.provider.(FileUploader)
+
+	for _, path := range b.attachedFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.recordLoadError(fmt.Errorf("attach file %s: %w", path, err))
+			continue
+		}
+
+		if supportsUpload {
+			fileID, err := uploader.UploadFile(ctx, filepath.Base(path), data, detectFileMimeType(path))
+			if err == nil {
+				b.fileRefs = append(b.fileRefs, FileRef{FileID: fileID, Name: filepath.Base(path)})
+				continue
+			}
+			b.recordLoadError(fmt.Errorf("upload file %s: %w", path, err))
+		}
+
+		b.fileContext = append(b.fileContext, fmt.Sprintf("--- %s ---\n%s", path, string(data)))
+	}
+}
+
+// detectFileMimeType returns the MIME type for path based on its
+// extension, falling back to "application/octet-stream" when unknown.
+func detectFileMimeType(path string) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
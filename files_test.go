@@ -0,0 +1,147 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuilderAttachFile_UploadsViaFileUploader(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello file"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	p := &stubFileUploaderProvider{
+		stubProvider: &stubProvider{
+			name: "stub",
+			sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+				return &ProviderResponse{Content: "ok"}, nil
+			},
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		AttachFile(path).
+		User("describe this")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+
+	if uploaded := p.UploadedFiles(); len(uploaded) != 1 || uploaded[0] != "notes.txt" {
+		t.Fatalf("expected notes.txt to be uploaded once, got %v", uploaded)
+	}
+
+	reqs := p.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	parts, ok := reqs[0].Messages[0].Content.([]ContentPart)
+	if !ok {
+		t.Fatalf("expected multimodal content, got %T", reqs[0].Messages[0].Content)
+	}
+	found := false
+	for _, part := range parts {
+		if part.Type == "file" && part.File != nil && part.File.FileID == "file-stub-id" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a file content part referencing the uploaded ID, got %+v", parts)
+	}
+}
+
+func TestBuilderAttachFile_FallsBackToTextContextWithoutUploader(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello file"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT4o).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		AttachFile(path).
+		User("describe this")
+
+	meta := b.SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+
+	reqs := p.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	system, ok := reqs[0].Messages[0].Content.(string)
+	if !ok || reqs[0].Messages[0].Role != "system" {
+		t.Fatalf("expected a system message with text content, got %+v", reqs[0].Messages[0])
+	}
+	if !strings.Contains(system, "hello file") {
+		t.Errorf("expected the file's content injected as context, got %q", system)
+	}
+}
+
+func TestBuilderAttachFile_MissingFileSurfacesViaErr(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{name: "stub"}
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		AttachFile("/no/such/file.txt").
+		User("describe this")
+
+	meta := b.SendWithMeta()
+	if meta.Error == nil {
+		t.Fatal("expected a missing attached file to surface as an error")
+	}
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "attach file") {
+		t.Fatalf("expected b.Err() to report the attach failure, got %v", err)
+	}
+}
+
+func TestBuilderAttachFile_FailedUploadSurfacesViaErr(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("hello file"), 0o644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+
+	p := &stubFileUploaderProvider{
+		stubProvider: &stubProvider{name: "stub"},
+		uploadFn: func(ctx context.Context, name string, data []byte, mimeType string) (string, error) {
+			return "", errors.New("upload failed")
+		},
+	}
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		AttachFile(path).
+		User("describe this")
+
+	meta := b.SendWithMeta()
+	if meta.Error == nil {
+		t.Fatal("expected a failed upload to surface as an error")
+	}
+	if err := b.Err(); err == nil || !strings.Contains(err.Error(), "upload file") {
+		t.Fatalf("expected b.Err() to report the upload failure, got %v", err)
+	}
+}
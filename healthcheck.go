@@ -0,0 +1,43 @@
+package ai
+
+import "context"
+
+// pingModelFor returns a small, cheap model to use for HealthCheck's probe
+// request, so readiness checks don't burn meaningful latency or quota.
+func pingModelFor(providerType ProviderType) Model {
+	switch providerType {
+	case ProviderAnthropic:
+		return ModelClaudeHaiku
+	case ProviderGoogle:
+		return ModelGemini3Flash
+	default:
+		return ModelGPT4oMini
+	}
+}
+
+// HealthCheck verifies that client's provider is reachable and its
+// credentials are valid, for use in readiness probes (e.g. an HTTP
+// /healthz handler) that want to report a misconfigured upstream before
+// the first real request fails. client may be nil to check the default
+// client.
+//
+// When the provider implements ModelLister, HealthCheck uses that (no
+// completion tokens spent); otherwise it falls back to a minimal one-word
+// completion. Either way, any failure comes back as the same classified
+// *ProviderError Send would normally return.
+func HealthCheck(ctx context.Context, client *Client) error {
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	if lister, ok := client.provider.(ModelLister); ok {
+		_, err := lister.ListModels(ctx)
+		return err
+	}
+
+	_, err := client.provider.Send(ctx, &ProviderRequest{
+		Model:    string(pingModelFor(client.providerType)),
+		Messages: []Message{{Role: "user", Content: "ping"}},
+	})
+	return err
+}
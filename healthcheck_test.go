@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthCheck_UsesModelListerWhenAvailable(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models" {
+			t.Fatalf("expected /models, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(ProviderOpenAI, WithAPIKey("k"), WithBaseURL(srv.URL))
+
+	if err := HealthCheck(context.Background(), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHealthCheck_FallsBackToMinimalSendWithoutModelLister(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "pong"}, nil
+		},
+	}
+	client := &Client{provider: p, providerType: ProviderAnthropic}
+
+	if err := HealthCheck(context.Background(), client); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reqs := p.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].Model != string(ModelClaudeHaiku) {
+		t.Errorf("expected ping to use a cheap model, got %q", reqs[0].Model)
+	}
+}
+
+func TestHealthCheck_ReturnsClassifiedError(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	wantErr := &ProviderError{Provider: "stub", Code: ErrAuth, Message: "invalid key"}
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return nil, wantErr
+		},
+	}
+	client := &Client{provider: p, providerType: ProviderAnthropic}
+
+	err := HealthCheck(context.Background(), client)
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.Code != ErrAuth {
+		t.Fatalf("expected a classified ErrAuth error, got %v", err)
+	}
+}
+
+func TestHealthCheck_NilClientUsesDefault(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "pong"}, nil
+		},
+	}
+	setDefaultClientForTest(t, p, ProviderAnthropic)
+
+	if err := HealthCheck(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -16,6 +16,7 @@ type (
 	AfterResponseHook func(model Model, content string, duration time.Duration)
 	OnErrorHook       func(model Model, err error)
 	OnTokensHook      func(model Model, prompt, completion int)
+	RateLimitHook     func(provider string, info RateLimitInfo)
 )
 
 // Global hooks (can be set by users for observability).
@@ -26,6 +27,7 @@ var (
 	afterResponseHooks []AfterResponseHook
 	onErrorHooks       []OnErrorHook
 	onTokensHooks      []OnTokensHook
+	rateLimitHooks     []RateLimitHook
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -60,6 +62,15 @@ func OnTokens(hook OnTokensHook) {
 	onTokensHooks = append(onTokensHooks, hook)
 }
 
+// OnRateLimitInfo registers a hook called whenever a provider reports a
+// rate-limit budget alongside a response (see RateLimitInfo), so a caller's
+// limiter can adapt without waiting for a 429.
+func OnRateLimitInfo(hook RateLimitHook) {
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+	rateLimitHooks = append(rateLimitHooks, hook)
+}
+
 // ClearHooks removes all registered hooks.
 func ClearHooks() {
 	hooksLock.Lock()
@@ -68,6 +79,7 @@ func ClearHooks() {
 	afterResponseHooks = nil
 	onErrorHooks = nil
 	onTokensHooks = nil
+	rateLimitHooks = nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -114,6 +126,16 @@ func invokeOnTokens(model Model, prompt, completion int) {
 	}
 }
 
+func invokeRateLimitInfo(provider string, info RateLimitInfo) {
+	hooksLock.RLock()
+	hooks := rateLimitHooks
+	hooksLock.RUnlock()
+
+	for _, hook := range hooks {
+		hook(provider, info)
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Common Hook Examples (optional utilities)
 // ═══════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,325 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Image Edit/Variation Request/Response
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ImageEditRequest is a provider-agnostic request to edit an existing image,
+// via a standalone endpoint (e.g. OpenAI's /images/edits) rather than the
+// in-conversation image_generation tool.
+type ImageEditRequest struct {
+	Model  string
+	Image  []byte // image to edit
+	Mask   []byte // optional: transparent regions mark where Image should be edited
+	Prompt string
+	N      int    // number of images to generate, default 1
+	Size   string // e.g. "1024x1024", "auto"
+	Format string // output format: "png", "jpeg", "webp"
+}
+
+// ImageVariationRequest is a provider-agnostic request to generate
+// variations of an existing image, via a standalone endpoint (e.g. OpenAI's
+// /images/variations). There is no prompt: the provider produces images
+// similar to Image.
+type ImageVariationRequest struct {
+	Model  string
+	Image  []byte
+	N      int    // number of images to generate, default 1
+	Size   string // e.g. "1024x1024", "auto"
+	Format string // output format: "png", "jpeg", "webp"
+}
+
+// ImageEditResponse is a provider-agnostic response from an image edit or
+// variation request.
+type ImageEditResponse struct {
+	Images      [][]byte // raw image bytes, one per N requested
+	Format      string
+	ContentType string
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Image Edit Builder - Fluent API
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ImageEditBuilder provides a fluent API for editing an existing image.
+type ImageEditBuilder struct {
+	model  string
+	image  []byte
+	mask   []byte
+	prompt string
+	n      int
+	size   string
+	format string
+	client *Client
+	ctx    context.Context
+}
+
+// EditImage creates a new ImageEditBuilder from a local image file and a
+// prompt describing the edit.
+func EditImage(path string, prompt string) *ImageEditBuilder {
+	image, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("%s Error loading image %s: %v\n", colorRed("✗"), path, err)
+		return &ImageEditBuilder{prompt: prompt, n: 1}
+	}
+
+	return &ImageEditBuilder{image: image, prompt: prompt, n: 1}
+}
+
+// EditImageBytes creates a new ImageEditBuilder from image bytes and a
+// prompt describing the edit.
+func EditImageBytes(image []byte, prompt string) *ImageEditBuilder {
+	return &ImageEditBuilder{image: image, prompt: prompt, n: 1}
+}
+
+// Model sets the image model.
+func (e *ImageEditBuilder) Model(model string) *ImageEditBuilder {
+	e.model = model
+	return e
+}
+
+// Mask sets the mask image from a local file path. Transparent regions of
+// the mask mark where the image should be edited.
+func (e *ImageEditBuilder) Mask(path string) *ImageEditBuilder {
+	mask, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("%s Error loading mask %s: %v\n", colorRed("✗"), path, err)
+		return e
+	}
+	e.mask = mask
+	return e
+}
+
+// MaskBytes sets the mask image from raw bytes.
+func (e *ImageEditBuilder) MaskBytes(mask []byte) *ImageEditBuilder {
+	e.mask = mask
+	return e
+}
+
+// N sets the number of edited images to generate.
+func (e *ImageEditBuilder) N(n int) *ImageEditBuilder {
+	e.n = n
+	return e
+}
+
+// Size sets the output image dimensions (e.g. "1024x1024", "auto").
+func (e *ImageEditBuilder) Size(size string) *ImageEditBuilder {
+	e.size = size
+	return e
+}
+
+// Format sets the output image format ("png", "jpeg", "webp").
+func (e *ImageEditBuilder) Format(format string) *ImageEditBuilder {
+	e.format = format
+	return e
+}
+
+// WithClient sets a specific client/provider to execute the request with.
+func (e *ImageEditBuilder) WithClient(client *Client) *ImageEditBuilder {
+	e.client = client
+	return e
+}
+
+// WithContext sets a context for cancellation.
+func (e *ImageEditBuilder) WithContext(ctx context.Context) *ImageEditBuilder {
+	e.ctx = ctx
+	return e
+}
+
+// Do edits the image and returns the first result's raw bytes.
+func (e *ImageEditBuilder) Do() ([]byte, error) {
+	resp, err := e.DoWithMeta()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Images) == 0 {
+		return nil, fmt.Errorf("provider returned no images")
+	}
+	return resp.Images[0], nil
+}
+
+// DoWithMeta edits the image and returns the full response.
+func (e *ImageEditBuilder) DoWithMeta() (*ImageEditResponse, error) {
+	client := e.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	imageProvider, ok := client.provider.(ImageProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support image editing", client.provider.Name())
+	}
+
+	n := e.n
+	if n == 0 {
+		n = 1
+	}
+
+	req := &ImageEditRequest{
+		Model:  e.model,
+		Image:  e.image,
+		Mask:   e.mask,
+		Prompt: e.prompt,
+		N:      n,
+		Size:   e.size,
+		Format: e.format,
+	}
+
+	if Debug {
+		fmt.Printf("%s Editing image: %d bytes, prompt=%q\n", colorCyan("→"), len(e.image), e.prompt)
+	}
+
+	waitForRateLimit()
+	resp, err := imageProvider.EditImage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if Debug {
+		fmt.Printf("%s Generated %d edited image(s)\n", colorGreen("✓"), len(resp.Images))
+	}
+
+	return resp, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Image Variation Builder - Fluent API
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ImageVariationBuilder provides a fluent API for generating variations of
+// an existing image.
+type ImageVariationBuilder struct {
+	model  string
+	image  []byte
+	n      int
+	size   string
+	format string
+	client *Client
+	ctx    context.Context
+}
+
+// ImageVariation creates a new ImageVariationBuilder from a local image file.
+func ImageVariation(path string) *ImageVariationBuilder {
+	image, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("%s Error loading image %s: %v\n", colorRed("✗"), path, err)
+		return &ImageVariationBuilder{n: 1}
+	}
+
+	return &ImageVariationBuilder{image: image, n: 1}
+}
+
+// ImageVariationBytes creates a new ImageVariationBuilder from image bytes.
+func ImageVariationBytes(image []byte) *ImageVariationBuilder {
+	return &ImageVariationBuilder{image: image, n: 1}
+}
+
+// Model sets the image model.
+func (v *ImageVariationBuilder) Model(model string) *ImageVariationBuilder {
+	v.model = model
+	return v
+}
+
+// N sets the number of variations to generate.
+func (v *ImageVariationBuilder) N(n int) *ImageVariationBuilder {
+	v.n = n
+	return v
+}
+
+// Size sets the output image dimensions (e.g. "1024x1024", "auto").
+func (v *ImageVariationBuilder) Size(size string) *ImageVariationBuilder {
+	v.size = size
+	return v
+}
+
+// Format sets the image format ("png", "jpeg", "webp") reported on the
+// response. The OpenAI provider does not send it to the variations
+// endpoint, so it does not change the format of the returned images.
+func (v *ImageVariationBuilder) Format(format string) *ImageVariationBuilder {
+	v.format = format
+	return v
+}
+
+// WithClient sets a specific client/provider to execute the request with.
+func (v *ImageVariationBuilder) WithClient(client *Client) *ImageVariationBuilder {
+	v.client = client
+	return v
+}
+
+// WithContext sets a context for cancellation.
+func (v *ImageVariationBuilder) WithContext(ctx context.Context) *ImageVariationBuilder {
+	v.ctx = ctx
+	return v
+}
+
+// Do generates variations of the image and returns the first result's raw
+// bytes.
+func (v *ImageVariationBuilder) Do() ([]byte, error) {
+	resp, err := v.DoWithMeta()
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Images) == 0 {
+		return nil, fmt.Errorf("provider returned no images")
+	}
+	return resp.Images[0], nil
+}
+
+// DoWithMeta generates variations of the image and returns the full
+// response.
+func (v *ImageVariationBuilder) DoWithMeta() (*ImageEditResponse, error) {
+	client := v.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	ctx := v.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	imageProvider, ok := client.provider.(ImageProvider)
+	if !ok {
+		return nil, fmt.Errorf("provider %s does not support image variations", client.provider.Name())
+	}
+
+	n := v.n
+	if n == 0 {
+		n = 1
+	}
+
+	req := &ImageVariationRequest{
+		Model:  v.model,
+		Image:  v.image,
+		N:      n,
+		Size:   v.size,
+		Format: v.format,
+	}
+
+	if Debug {
+		fmt.Printf("%s Generating variations: %d bytes\n", colorCyan("→"), len(v.image))
+	}
+
+	waitForRateLimit()
+	resp, err := imageProvider.ImageVariation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if Debug {
+		fmt.Printf("%s Generated %d image variation(s)\n", colorGreen("✓"), len(resp.Images))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"errors"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Response Merging
+// ═══════════════════════════════════════════════════════════════════════════
+
+// MergeStrategy determines how MergeResponses combines multiple responses
+// gathered from a parallel fan-out (e.g. Compare, best-of-N).
+type MergeStrategy string
+
+const (
+	// MergeConcat joins the content of every response with a blank line.
+	MergeConcat MergeStrategy = "concat"
+	// MergeFirstNonError returns the first response that isn't nil.
+	MergeFirstNonError MergeStrategy = "first-non-error"
+	// MergeMajorityVote returns the response whose content occurs most often,
+	// useful for classification or best-of-N voting.
+	MergeMajorityVote MergeStrategy = "majority-vote"
+)
+
+// MergeResponses combines responses produced by a parallel fan-out into a
+// single ProviderResponse according to strategy. Failed calls should be
+// passed as a nil entry in resps; they are skipped. With MergeConcat, token
+// counts on the merged response are summed across the non-nil responses;
+// MergeFirstNonError and MergeMajorityVote return one of the inputs as is,
+// with only that response's own token counts. Returns an error if every
+// entry is nil.
+func MergeResponses(strategy MergeStrategy, resps ...*ProviderResponse) (*ProviderResponse, error) {
+	var ok []*ProviderResponse
+	for _, r := range resps {
+		if r != nil {
+			ok = append(ok, r)
+		}
+	}
+	if len(ok) == 0 {
+		return nil, errors.New("merge responses: no non-error responses to merge")
+	}
+
+	switch strategy {
+	case MergeFirstNonError:
+		return ok[0], nil
+
+	case MergeMajorityVote:
+		counts := make(map[string]int, len(ok))
+		for _, r := range ok {
+			counts[r.Content]++
+		}
+		best := ok[0]
+		bestCount := 0
+		for _, r := range ok {
+			if c := counts[r.Content]; c > bestCount {
+				bestCount = c
+				best = r
+			}
+		}
+		return best, nil
+
+	default: // MergeConcat
+		merged := &ProviderResponse{}
+		parts := make([]string, 0, len(ok))
+		for _, r := range ok {
+			parts = append(parts, r.Content)
+			merged.PromptTokens += r.PromptTokens
+			merged.CompletionTokens += r.CompletionTokens
+			merged.TotalTokens += r.TotalTokens
+		}
+		merged.Content = strings.Join(parts, "\n\n")
+		return merged, nil
+	}
+}
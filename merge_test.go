@@ -0,0 +1,55 @@
+package ai
+
+import "testing"
+
+func TestMergeResponses_Concat(t *testing.T) {
+	merged, err := MergeResponses(MergeConcat,
+		&ProviderResponse{Content: "a", TotalTokens: 10},
+		nil,
+		&ProviderResponse{Content: "b", TotalTokens: 20},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.Content != "a\n\nb" {
+		t.Errorf("expected concatenated content, got %q", merged.Content)
+	}
+	if merged.TotalTokens != 30 {
+		t.Errorf("expected summed tokens, got %d", merged.TotalTokens)
+	}
+}
+
+func TestMergeResponses_FirstNonError(t *testing.T) {
+	merged, err := MergeResponses(MergeFirstNonError,
+		nil,
+		&ProviderResponse{Content: "first"},
+		&ProviderResponse{Content: "second"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.Content != "first" {
+		t.Errorf("expected 'first', got %q", merged.Content)
+	}
+}
+
+func TestMergeResponses_MajorityVote(t *testing.T) {
+	merged, err := MergeResponses(MergeMajorityVote,
+		&ProviderResponse{Content: "cat"},
+		&ProviderResponse{Content: "dog"},
+		&ProviderResponse{Content: "cat"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.Content != "cat" {
+		t.Errorf("expected majority 'cat', got %q", merged.Content)
+	}
+}
+
+func TestMergeResponses_AllNilReturnsError(t *testing.T) {
+	_, err := MergeResponses(MergeConcat, nil, nil)
+	if err == nil {
+		t.Error("expected error when all responses are nil")
+	}
+}
@@ -0,0 +1,76 @@
+package ai
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Per-Model Capability Registry
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ModelCapabilities describes the resource limits and supported features of a
+// specific model, so a request can be validated against the chosen model
+// up front instead of discovering limits via an API error. This is distinct
+// from ProviderCapabilities (what a provider supports at large) and ModelInfo
+// (display metadata for model-list UIs).
+type ModelCapabilities struct {
+	ContextWindow   int    // max input tokens
+	MaxOutputTokens int    // max output tokens per response
+	Vision          bool   // accepts image input
+	Tools           bool   // supports function calling
+	Thinking        bool   // supports extended/reasoning thinking
+	Modality        string // "text" or "multimodal"
+
+	// DeveloperRole is true for OpenAI models that prefer the "developer"
+	// role over "system" for instructions (system is kept only for
+	// back-compat). buildMessages translates our system message to it.
+	DeveloperRole bool
+}
+
+// modelCapabilityRegistry holds known capability data for models this
+// package ships constants for. Models not listed here (custom IDs, newly
+// released models not yet added) simply aren't in the map.
+var modelCapabilityRegistry = map[Model]ModelCapabilities{
+	ModelGPT5:      {ContextWindow: 400_000, MaxOutputTokens: 128_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal", DeveloperRole: true},
+	ModelGPT5Mini:  {ContextWindow: 400_000, MaxOutputTokens: 128_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal", DeveloperRole: true},
+	ModelGPT5Nano:  {ContextWindow: 400_000, MaxOutputTokens: 128_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal", DeveloperRole: true},
+	ModelGPT5Codex: {ContextWindow: 400_000, MaxOutputTokens: 128_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal", DeveloperRole: true},
+	ModelGPT4o:     {ContextWindow: 128_000, MaxOutputTokens: 16_384, Vision: true, Tools: true, Modality: "multimodal"},
+	ModelGPT4oMini: {ContextWindow: 128_000, MaxOutputTokens: 16_384, Vision: true, Tools: true, Modality: "multimodal"},
+	ModelO1:        {ContextWindow: 200_000, MaxOutputTokens: 100_000, Thinking: true, Modality: "text", DeveloperRole: true},
+	ModelO1Mini:    {ContextWindow: 128_000, MaxOutputTokens: 65_536, Thinking: true, Modality: "text", DeveloperRole: true},
+	ModelO1Preview: {ContextWindow: 128_000, MaxOutputTokens: 32_768, Thinking: true, Modality: "text"},
+
+	ModelClaudeOpus:     {ContextWindow: 200_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeSonnet:   {ContextWindow: 200_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeHaiku:    {ContextWindow: 200_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeOpus41:   {ContextWindow: 200_000, MaxOutputTokens: 32_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeOpus4:    {ContextWindow: 200_000, MaxOutputTokens: 32_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeSonnet4:  {ContextWindow: 200_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeSonnet37: {ContextWindow: 200_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelClaudeHaiku35:  {ContextWindow: 200_000, MaxOutputTokens: 8_192, Vision: true, Tools: true, Modality: "multimodal"},
+
+	ModelGemini3Pro:        {ContextWindow: 1_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelGemini3Flash:      {ContextWindow: 1_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelGemini25Pro:       {ContextWindow: 1_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelGemini25Flash:     {ContextWindow: 1_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Thinking: true, Modality: "multimodal"},
+	ModelGemini25FlashLite: {ContextWindow: 1_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Modality: "multimodal"},
+
+	ModelGrok41Fast: {ContextWindow: 2_000_000, MaxOutputTokens: 64_000, Vision: true, Tools: true, Modality: "multimodal"},
+	ModelGrok3:      {ContextWindow: 131_072, MaxOutputTokens: 32_768, Tools: true, Modality: "text"},
+}
+
+// GetModelCapabilities looks up the known capabilities for m. ok is false if
+// m isn't in the registry (e.g. a custom or self-hosted model ID), in which
+// case callers should fall back to a conservative default rather than assume
+// support.
+func GetModelCapabilities(m Model) (ModelCapabilities, bool) {
+	caps, ok := modelCapabilityRegistry[m]
+	return caps, ok
+}
+
+// systemMessageRole returns the message role buildMessages should use for
+// the system prompt: "developer" for models that prefer it (see
+// ModelCapabilities.DeveloperRole), "system" otherwise.
+func systemMessageRole(m Model) string {
+	if caps, ok := GetModelCapabilities(m); ok && caps.DeveloperRole {
+		return "developer"
+	}
+	return "system"
+}
@@ -0,0 +1,20 @@
+package ai
+
+import "testing"
+
+func TestGetModelCapabilities_KnownModel(t *testing.T) {
+	caps, ok := GetModelCapabilities(ModelGPT4oMini)
+	if !ok {
+		t.Fatalf("expected ModelGPT4oMini to be in the registry")
+	}
+	if caps.ContextWindow != 128_000 || !caps.Vision || !caps.Tools {
+		t.Errorf("unexpected capabilities for GPT-4o mini: %+v", caps)
+	}
+}
+
+func TestGetModelCapabilities_UnknownModel(t *testing.T) {
+	_, ok := GetModelCapabilities(Model("custom/my-finetune"))
+	if ok {
+		t.Errorf("expected unknown model to not be in the registry")
+	}
+}
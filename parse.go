@@ -109,7 +109,7 @@ Important:
 	}
 
 	// Clone builder and enable JSON mode
-	builder := b.Clone().JSON()
+	builder := b.Clone().JSON().Schema(target)
 
 	// Setup context
 	ctx := b.ctx
@@ -224,7 +224,7 @@ Important:
 	}
 
 	// Clone builder and enable JSON mode
-	builder := b.Clone().JSON()
+	builder := b.Clone().JSON().Schema(target)
 
 	// Setup context
 	ctx := b.ctx
@@ -518,6 +518,37 @@ Respond with JSON containing:
 	return Extract[Classification](b, prompt)
 }
 
+// labelChoice is the target struct for ClassifyLabel. allowed is populated
+// before parsing and checked by Validate, which ParseInto calls on every
+// attempt via the StructValidator interface - so a label outside the given
+// set triggers a correction retry instead of being returned as-is.
+type labelChoice struct {
+	Label   string   `json:"label"`
+	allowed []string `json:"-"`
+}
+
+// Validate implements StructValidator.
+func (l *labelChoice) Validate() error {
+	return ValidateOneOf(l.Label, l.allowed...)
+}
+
+// ClassifyLabel constrains the model to pick exactly one of labels and
+// returns just the chosen label, retrying with correction feedback if the
+// model picks something outside the set. Use this over Classify when you
+// only need the label for routing, not a confidence score or reasoning.
+func ClassifyLabel(b *Builder, text string, labels []string) (string, error) {
+	prompt := fmt.Sprintf(`Classify the following text into exactly one of these labels: %s
+
+Text: %s
+
+Respond with JSON containing:
+- label: the chosen label (must be exactly one of the given options)`, strings.Join(labels, ", "), text)
+
+	target := &labelChoice{allowed: labels}
+	result := ParseInto(b, prompt, target, DefaultParseConfig())
+	return result.Value.Label, result.Error
+}
+
 // Entity represents an extracted entity
 type Entity struct {
 	Name  string `json:"name"`
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Pool Provider - weighted load balancing across several Providers
+// ═══════════════════════════════════════════════════════════════════════════
+
+// PoolMember is one weighted backend in a PoolProvider.
+type PoolMember struct {
+	Provider Provider
+	Weight   int // relative share of traffic; <= 0 is treated as 1
+}
+
+// PoolProvider spreads requests across several Providers in weighted
+// round-robin order, for scaling throughput beyond what a single
+// provider/API key's rate limit allows (e.g. two OpenAI keys with separate
+// quotas). It implements Provider, so it drops into Client like any single
+// provider.
+//
+// Each member tracks its own circuit breaker state; a member that's failed
+// repeatedly is skipped in favor of the next healthy one, the same
+// short-circuiting Builder.CircuitBreaker does for model fallbacks.
+type PoolProvider struct {
+	mu            sync.Mutex
+	slots         []*poolMember // weight-expanded selection order
+	cursor        int
+	breakerConfig *CircuitBreakerConfig
+}
+
+// poolMember pairs a wrapped Provider with its own breaker, so one member's
+// failures don't affect another's.
+type poolMember struct {
+	provider Provider
+	breaker  *circuitBreaker
+}
+
+// NewPoolProvider builds a PoolProvider from members, expanding each by its
+// Weight for round-robin selection (a member with Weight 3 gets 3 out of
+// every sum(weights) picks). Weight <= 0 is treated as 1.
+func NewPoolProvider(members ...PoolMember) *PoolProvider {
+	p := &PoolProvider{breakerConfig: DefaultCircuitBreakerConfig()}
+	for _, m := range members {
+		weight := m.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		pm := &poolMember{provider: m.Provider, breaker: &circuitBreaker{}}
+		for i := 0; i < weight; i++ {
+			p.slots = append(p.slots, pm)
+		}
+	}
+	return p
+}
+
+// Name returns the provider identifier ("pool"). Errors from individual
+// members still carry their own provider name.
+func (p *PoolProvider) Name() string { return "pool" }
+
+// Capabilities returns the first member's capabilities. Pool members are
+// expected to be interchangeable backends (e.g. the same provider under
+// different API keys), so this is a reasonable stand-in for the pool as a
+// whole.
+func (p *PoolProvider) Capabilities() ProviderCapabilities {
+	if len(p.slots) == 0 {
+		return ProviderCapabilities{}
+	}
+	return p.slots[0].provider.Capabilities()
+}
+
+// Send routes req to the next healthy member in weighted round-robin order.
+func (p *PoolProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	pm, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pm.provider.Send(ctx, req)
+	p.record(pm, err)
+	return resp, err
+}
+
+// SendStream routes req to the next healthy member in weighted round-robin order.
+func (p *PoolProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	pm, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pm.provider.SendStream(ctx, req, callback)
+	p.record(pm, err)
+	return resp, err
+}
+
+// next advances the round-robin cursor to the next slot whose circuit
+// breaker is closed (or half-open for a probe), skipping at most one full
+// lap around the pool. Returns an error if every member's circuit is open.
+func (p *PoolProvider) next() (*poolMember, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.slots) == 0 {
+		return nil, &ProviderError{Provider: p.Name(), Message: "no members configured"}
+	}
+
+	for i := 0; i < len(p.slots); i++ {
+		pm := p.slots[p.cursor]
+		p.cursor = (p.cursor + 1) % len(p.slots)
+		if pm.breaker.allow(p.breakerConfig) {
+			return pm, nil
+		}
+	}
+
+	return nil, &ProviderError{Provider: p.Name(), Message: "all pool members' circuits are open"}
+}
+
+// record updates pm's breaker based on the outcome of a call.
+func (p *PoolProvider) record(pm *poolMember, err error) {
+	if err != nil {
+		pm.breaker.recordFailure(p.breakerConfig)
+		return
+	}
+	pm.breaker.recordSuccess()
+}
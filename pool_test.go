@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPoolProvider_Send_DistributesByWeight(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	a := &stubProvider{name: "a", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "a"}, nil
+	}}
+	b := &stubProvider{name: "b", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "b"}, nil
+	}}
+
+	pool := NewPoolProvider(
+		PoolMember{Provider: a, Weight: 2},
+		PoolMember{Provider: b, Weight: 1},
+	)
+
+	var gotA, gotB int
+	for i := 0; i < 9; i++ {
+		resp, err := pool.Send(context.Background(), &ProviderRequest{Model: "m"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		switch resp.Content {
+		case "a":
+			gotA++
+		case "b":
+			gotB++
+		}
+	}
+
+	if gotA != 6 || gotB != 3 {
+		t.Fatalf("expected a 2:1 weighted split of 6:3 over 9 calls, got a=%d b=%d", gotA, gotB)
+	}
+}
+
+func TestPoolProvider_Send_SkipsMemberWithOpenCircuit(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	failing := &stubProvider{name: "failing", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return nil, fmt.Errorf("boom")
+	}}
+	healthy := &stubProvider{name: "healthy", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return &ProviderResponse{Content: "healthy"}, nil
+	}}
+
+	pool := NewPoolProvider(
+		PoolMember{Provider: failing, Weight: 1},
+		PoolMember{Provider: healthy, Weight: 1},
+	)
+	pool.breakerConfig = &CircuitBreakerConfig{FailureThreshold: 1, CooldownPeriod: time.Hour}
+
+	// First call hits "failing" and opens its breaker immediately
+	// (threshold 1). Every call from here on should land on "healthy".
+	_, _ = pool.Send(context.Background(), &ProviderRequest{Model: "m"})
+
+	for i := 0; i < 4; i++ {
+		resp, err := pool.Send(context.Background(), &ProviderRequest{Model: "m"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Content != "healthy" {
+			t.Fatalf("expected the open-circuit member to be skipped, got %q", resp.Content)
+		}
+	}
+}
+
+func TestPoolProvider_Send_ErrorsWhenAllCircuitsOpen(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	failing := &stubProvider{name: "failing", sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+		return nil, fmt.Errorf("boom")
+	}}
+
+	pool := NewPoolProvider(PoolMember{Provider: failing})
+	pool.breakerConfig = &CircuitBreakerConfig{FailureThreshold: 1, CooldownPeriod: time.Hour}
+
+	_, _ = pool.Send(context.Background(), &ProviderRequest{Model: "m"})
+
+	_, err := pool.Send(context.Background(), &ProviderRequest{Model: "m"})
+	if err == nil {
+		t.Fatal("expected an error once every member's circuit is open")
+	}
+}
@@ -2,6 +2,8 @@ package ai
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strings"
 )
 
@@ -17,20 +19,89 @@ const (
 	cyan    = "\033[36m"
 )
 
-func colorRed(s string) string     { return red + s + reset }
-func colorGreen(s string) string   { return green + s + reset }
-func colorYellow(s string) string  { return yellow + s + reset }
-func colorBlue(s string) string    { return blue + s + reset }
-func colorMagenta(s string) string { return magenta + s + reset }
-func colorCyan(s string) string    { return cyan + s + reset }
-func colorDim(s string) string     { return dim + s + reset }
+// colorEnabled controls whether the color* helpers below emit ANSI escape
+// codes, set via SetColorOutput. Defaults to auto-detecting whether
+// os.Stdout is a terminal, so piped/redirected output (CI logs, files
+// captured from stdout) isn't full of garbage escape codes.
+var colorEnabled = isTerminal(os.Stdout)
+
+// prettyOutput is where printDebugRequest, printDebugResponse, and the
+// Pretty printers write, set via SetOutput. Defaults to os.Stdout.
+var prettyOutput io.Writer = os.Stdout
+
+// SetColorOutput enables or disables ANSI color codes in debug and Pretty
+// output, overriding the default terminal auto-detection. Pass false to
+// keep CI logs or redirected output free of escape codes.
+func SetColorOutput(enabled bool) {
+	colorEnabled = enabled
+}
+
+// SetOutput redirects the debug request/response dumps and Pretty output,
+// normally written to os.Stdout, to w. Other Debug log lines (e.g. the
+// providers' per-request "POST" lines) are still printed to os.Stdout.
+func SetOutput(w io.Writer) {
+	prettyOutput = w
+}
+
+// isTerminal reports whether f is attached to a terminal rather than a
+// pipe, file, or other non-interactive destination.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func colorRed(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return red + s + reset
+}
+func colorGreen(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return green + s + reset
+}
+func colorYellow(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return yellow + s + reset
+}
+func colorBlue(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return blue + s + reset
+}
+func colorMagenta(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return magenta + s + reset
+}
+func colorCyan(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return cyan + s + reset
+}
+func colorDim(s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return dim + s + reset
+}
 
 // printDebugRequest prints the outgoing request
 func printDebugRequest(model Model, messages []Message) {
-	fmt.Println()
-	fmt.Println(colorYellow("┌─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s DEBUG REQUEST → %s\n", colorYellow("│"), colorCyan(string(model)))
-	fmt.Println(colorYellow("├─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(prettyOutput)
+	fmt.Fprintln(prettyOutput, colorYellow("┌─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(prettyOutput, "%s DEBUG REQUEST → %s\n", colorYellow("│"), colorCyan(string(model)))
+	fmt.Fprintln(prettyOutput, colorYellow("├─────────────────────────────────────────────────────────────"))
 
 	for _, m := range messages {
 		var role string
@@ -43,7 +114,7 @@ func printDebugRequest(model Model, messages []Message) {
 			role = colorDim(m.Role)
 		}
 
-		fmt.Printf("%s [%s]\n", colorYellow("│"), role)
+		fmt.Fprintf(prettyOutput, "%s [%s]\n", colorYellow("│"), role)
 
 		// Indent content
 		var contentStr string
@@ -53,53 +124,53 @@ func printDebugRequest(model Model, messages []Message) {
 		lines := strings.Split(contentStr, "\n")
 		for _, line := range lines {
 			if len(line) > 80 {
-				fmt.Printf("%s   %s...\n", colorYellow("│"), line[:77])
+				fmt.Fprintf(prettyOutput, "%s   %s...\n", colorYellow("│"), line[:77])
 			} else {
-				fmt.Printf("%s   %s\n", colorYellow("│"), line)
+				fmt.Fprintf(prettyOutput, "%s   %s\n", colorYellow("│"), line)
 			}
 		}
 	}
 
-	fmt.Println(colorYellow("└─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(prettyOutput, colorYellow("└─────────────────────────────────────────────────────────────"))
 }
 
 // printDebugResponse prints the incoming response
 func printDebugResponse(content string, resp *Response) {
-	fmt.Println()
-	fmt.Println(colorGreen("┌─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s DEBUG RESPONSE\n", colorGreen("│"))
-	fmt.Println(colorGreen("├─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(prettyOutput)
+	fmt.Fprintln(prettyOutput, colorGreen("┌─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(prettyOutput, "%s DEBUG RESPONSE\n", colorGreen("│"))
+	fmt.Fprintln(prettyOutput, colorGreen("├─────────────────────────────────────────────────────────────"))
 
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
-		fmt.Printf("%s %s\n", colorGreen("│"), line)
+		fmt.Fprintf(prettyOutput, "%s %s\n", colorGreen("│"), line)
 	}
 
-	fmt.Println(colorGreen("├─────────────────────────────────────────────────────────────"))
-	fmt.Printf("%s Tokens: prompt=%d, completion=%d, total=%d\n",
+	fmt.Fprintln(prettyOutput, colorGreen("├─────────────────────────────────────────────────────────────"))
+	fmt.Fprintf(prettyOutput, "%s Tokens: prompt=%d, completion=%d, total=%d\n",
 		colorGreen("│"),
 		resp.Usage.PromptTokens,
 		resp.Usage.CompletionTokens,
 		resp.Usage.TotalTokens,
 	)
-	fmt.Println(colorGreen("└─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(prettyOutput, colorGreen("└─────────────────────────────────────────────────────────────"))
 }
 
 // printPrettyResponse prints a formatted response
 func printPrettyResponse(model Model, content string) {
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorCyan("▸"), colorDim(string(model)))
-	fmt.Println(colorDim("─────────────────────────────────────────────────────────────"))
-	fmt.Println(content)
-	fmt.Println()
+	fmt.Fprintln(prettyOutput)
+	fmt.Fprintf(prettyOutput, "%s %s\n", colorCyan("▸"), colorDim(string(model)))
+	fmt.Fprintln(prettyOutput, colorDim("─────────────────────────────────────────────────────────────"))
+	fmt.Fprintln(prettyOutput, content)
+	fmt.Fprintln(prettyOutput)
 }
 
 // printPrettyConversation prints a conversation exchange
 func printPrettyConversation(model Model, userMsg, assistantMsg string) {
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorGreen("You:"), userMsg)
-	fmt.Println()
-	fmt.Printf("%s %s\n", colorBlue(string(model)+":"), "")
-	fmt.Println(assistantMsg)
-	fmt.Println()
+	fmt.Fprintln(prettyOutput)
+	fmt.Fprintf(prettyOutput, "%s %s\n", colorGreen("You:"), userMsg)
+	fmt.Fprintln(prettyOutput)
+	fmt.Fprintf(prettyOutput, "%s %s\n", colorBlue(string(model)+":"), "")
+	fmt.Fprintln(prettyOutput, assistantMsg)
+	fmt.Fprintln(prettyOutput)
 }
@@ -0,0 +1,41 @@
+package ai
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetColorOutput_TogglesColorHelpers(t *testing.T) {
+	old := colorEnabled
+	defer func() { colorEnabled = old }()
+
+	SetColorOutput(false)
+	if got := colorRed("x"); got != "x" {
+		t.Errorf("expected plain string with colors disabled, got %q", got)
+	}
+
+	SetColorOutput(true)
+	if got := colorRed("x"); !strings.Contains(got, "\033[31m") {
+		t.Errorf("expected ANSI escape code with colors enabled, got %q", got)
+	}
+}
+
+func TestSetOutput_RedirectsPrettyPrinting(t *testing.T) {
+	oldOutput := prettyOutput
+	oldColor := colorEnabled
+	defer func() {
+		prettyOutput = oldOutput
+		colorEnabled = oldColor
+	}()
+
+	SetColorOutput(false)
+	var buf bytes.Buffer
+	SetOutput(&buf)
+
+	printPrettyResponse(ModelGPT4o, "hello")
+
+	if !strings.Contains(buf.String(), "hello") {
+		t.Errorf("expected redirected output to contain response content, got %q", buf.String())
+	}
+}
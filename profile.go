@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Named Builder Profiles
+// ═══════════════════════════════════════════════════════════════════════════
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = map[string]func(*Builder){}
+)
+
+// RegisterProfile stores configure under name, for Profile to apply to a
+// fresh Builder. configure should set whatever model, system prompt,
+// temperature, validators, etc. the profile represents - e.g.:
+//
+//	ai.RegisterProfile("summarizer", func(b *Builder) {
+//		b.Model(ModelGPT5Mini).System("Summarize the input in 3 bullet points.")
+//	})
+//
+// Registering under a name that's already in use replaces it.
+func RegisterProfile(name string, configure func(*Builder)) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles[name] = configure
+}
+
+// Profile returns a fresh Builder configured by the profile registered under
+// name, so concurrent callers each get their own independent Builder instead
+// of sharing state. Returns an error if name was never registered with
+// RegisterProfile.
+func Profile(name string) (*Builder, error) {
+	profilesMu.RLock()
+	configure, ok := profiles[name]
+	profilesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("ai: unregistered profile %q", name)
+	}
+
+	b := New("")
+	configure(b)
+	return b, nil
+}
+
+// MustProfile is like Profile but panics if name was never registered with
+// RegisterProfile, since a typo'd profile name is a programming error, not a
+// runtime condition callers should have to check for.
+func MustProfile(name string) *Builder {
+	b, err := Profile(name)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,46 @@
+package ai
+
+import "testing"
+
+func TestProfile_ReturnsIndependentBuildersPerCall(t *testing.T) {
+	RegisterProfile("test-summarizer", func(b *Builder) {
+		b.Model(ModelGPT5Mini).System("Summarize in 3 bullet points.").Temperature(0.2)
+	})
+
+	a, err := Profile("test-summarizer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := Profile("test-summarizer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if a == b {
+		t.Fatal("expected Profile to return a fresh Builder each call")
+	}
+	if a.model != ModelGPT5Mini || a.system != "Summarize in 3 bullet points." {
+		t.Fatalf("unexpected profile config: model=%v system=%q", a.model, a.system)
+	}
+
+	// Mutating one shouldn't affect the other.
+	a.User("hello")
+	if len(b.messages) != 0 {
+		t.Fatalf("expected profiles to be independent, but b saw a's mutation: %+v", b.messages)
+	}
+}
+
+func TestProfile_ReturnsErrorOnUnregisteredName(t *testing.T) {
+	if _, err := Profile("does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unregistered profile name")
+	}
+}
+
+func TestMustProfile_PanicsOnUnregisteredName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an unregistered profile name")
+		}
+	}()
+	MustProfile("does-not-exist")
+}
@@ -1,8 +1,14 @@
 package ai
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -21,7 +27,10 @@ type Provider interface {
 	Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error)
 
 	// SendStream sends a streaming request to the provider.
-	// The callback is invoked for each chunk of text received.
+	// The callback is invoked for each chunk of text received. If the stream
+	// fails partway through, the response returned alongside the error still
+	// holds whatever content was accumulated before the failure, since the
+	// callback has already delivered those chunks to the caller.
 	SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error)
 
 	// Capabilities returns a struct describing what features the provider supports.
@@ -61,6 +70,13 @@ type Embedder interface {
 	Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error)
 }
 
+// RequestPreviewer is implemented by providers that can build their
+// wire-format request struct from a ProviderRequest without sending it, for
+// Builder.DryRun.
+type RequestPreviewer interface {
+	BuildRawRequest(req *ProviderRequest) any
+}
+
 // AudioProvider is an interface for providers that support audio operations (TTS/STT).
 type AudioProvider interface {
 	// TextToSpeech converts text to audio.
@@ -69,6 +85,75 @@ type AudioProvider interface {
 	SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error)
 }
 
+// ModelLister is implemented by providers that can report which models a
+// given API key actually has access to, instead of relying solely on the
+// static modelMappings table.
+type ModelLister interface {
+	ListModels(ctx context.Context) ([]ModelDescriptor, error)
+}
+
+// ModelDescriptor describes a model as reported by a provider's models
+// listing endpoint.
+type ModelDescriptor struct {
+	ID      string
+	Owner   string
+	Created int64 // Unix timestamp, 0 if not reported
+}
+
+// BackgroundResponder is implemented by providers whose API supports
+// submitting a request as an async background job and polling it for
+// completion, instead of blocking on Send (e.g. OpenAI's Responses API, for
+// long-running deep-research models). Used by Builder.Background.
+type BackgroundResponder interface {
+	// StartBackground submits req as a background job and returns its
+	// provider-assigned ID. req.Background is set to true by the caller.
+	StartBackground(ctx context.Context, req *ProviderRequest) (id string, err error)
+
+	// PollResponse checks the status of a background job. status is the
+	// provider's raw status string (e.g. "queued", "in_progress",
+	// "completed"); resp is only populated once status is "completed".
+	PollResponse(ctx context.Context, id string) (resp *ProviderResponse, status string, err error)
+}
+
+// ImageProvider is implemented by providers whose API supports editing an
+// existing image and generating variations of one, via their standalone
+// image endpoints - distinct from the in-conversation image_generation tool
+// exposed through Builder.ImageGeneration.
+type ImageProvider interface {
+	// EditImage edits req.Image (optionally masked by req.Mask) according to
+	// req.Prompt.
+	EditImage(ctx context.Context, req *ImageEditRequest) (*ImageEditResponse, error)
+
+	// ImageVariation generates variations of req.Image without a prompt.
+	ImageVariation(ctx context.Context, req *ImageVariationRequest) (*ImageEditResponse, error)
+}
+
+// ToolCallStreamer is implemented by providers that can report tool-call
+// arguments incrementally as they stream in, instead of only delivering the
+// completed call once the stream ends (e.g. OpenAI's chat completions API).
+// Used by Builder.StreamWithToolDeltas.
+type ToolCallStreamer interface {
+	SendStreamWithToolDeltas(ctx context.Context, req *ProviderRequest, callback StreamCallback, onToolDelta ToolCallDeltaCallback) (*ProviderResponse, error)
+}
+
+// BatchProcessor is implemented by providers whose API supports submitting
+// many requests at once as an offline batch job, processed asynchronously
+// (typically within 24h) at a discount over synchronous Send (e.g. OpenAI's
+// Batch API). Used by SubmitBatch, GetBatch, and DownloadBatchResults.
+type BatchProcessor interface {
+	// SubmitBatch serializes reqs as a single batch job and returns its
+	// provider-assigned ID.
+	SubmitBatch(ctx context.Context, reqs []*ProviderRequest) (batchID string, err error)
+
+	// BatchStatus reports the provider's raw status string for a submitted
+	// batch job (e.g. "validating", "in_progress", "completed", "failed").
+	BatchStatus(ctx context.Context, batchID string) (status string, err error)
+
+	// BatchResults downloads and parses the results of a completed batch
+	// job, in the same order reqs were passed to SubmitBatch.
+	BatchResults(ctx context.Context, batchID string) ([]*ProviderResponse, error)
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Provider Types
 // ═══════════════════════════════════════════════════════════════════════════
@@ -95,6 +180,23 @@ type ProviderConfig struct {
 	BaseURL string            // Custom API endpoint (optional)
 	Headers map[string]string // Custom headers to include in requests
 	Timeout time.Duration     // Request timeout
+
+	// Transport, when set, is used as the RoundTripper for the provider's
+	// http.Client — e.g. to route through a corporate proxy or configure
+	// custom TLS. When nil, a transport honoring HTTPS_PROXY/HTTP_PROXY
+	// (via http.ProxyFromEnvironment) is used instead.
+	Transport http.RoundTripper
+
+	// CompressRequests gzips request bodies at or above
+	// compressRequestThreshold and sends them with a gzip Content-Encoding
+	// header, to cut upload time for large prompts/contexts.
+	CompressRequests bool
+
+	// Organization and Project scope requests to a specific OpenAI
+	// organization/project, sent as the OpenAI-Organization/OpenAI-Project
+	// headers. Ignored by providers other than OpenAIProvider.
+	Organization string
+	Project      string
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -104,14 +206,82 @@ type ProviderConfig struct {
 // ProviderRequest is a unified request structure used by all providers.
 // It normalizes inputs like model name, messages, and tools.
 type ProviderRequest struct {
-	Model        string
-	Messages     []Message
-	Temperature  *float64
-	Thinking     ThinkingLevel
+	Model       string
+	Messages    []Message
+	Temperature *float64
+	Thinking    ThinkingLevel
+
+	// ThinkingBudgetTokens is an exact reasoning token budget, set via
+	// Builder.ThinkingBudget. Providers that take a token budget directly
+	// (Anthropic, Gemini) use this in place of Thinking's bucketed default.
+	// OpenAI ignores it and relies on Thinking, which ThinkingBudget also
+	// sets to the nearest effort level.
+	ThinkingBudgetTokens int
+
 	Tools        []Tool        // Function calling tools
 	BuiltinTools []BuiltinTool // Responses API built-in tools (web_search, file_search, etc.)
 	JSONMode     bool
+	Schema       any // Structured output schema (struct value or JSON Schema map), set via Builder.Schema
 	Stream       bool
+
+	// CaptureRaw requests that the provider populate ProviderResponse.Raw
+	// with the unparsed response body, set via Builder.CaptureRaw.
+	CaptureRaw bool
+
+	// Headers carries request-scoped HTTP headers, set via Builder.Header.
+	// Providers merge these on top of ProviderConfig.Headers, with these
+	// winning on key collisions.
+	Headers map[string]string
+
+	// EndUser is a stable, hashed end-user identifier set via Builder.EndUser,
+	// sent as OpenAI's "safety_identifier" and Anthropic's "metadata.user_id"
+	// to aid abuse detection and per-user rate limiting. Empty if not set.
+	EndUser string
+
+	// ReasoningSummary requests a reasoning summary ("auto", "concise", or
+	// "detailed") from OpenAI's Responses API, set via
+	// Builder.ReasoningSummary. Ignored by providers that don't support it.
+	ReasoningSummary string
+
+	// Verbosity requests a gpt-5.1+ answer length ("low", "medium", "high"),
+	// independent of max tokens, set via Builder.Verbosity. Ignored by
+	// providers/models that don't support it.
+	Verbosity string
+
+	// ToolChoice forces the model to call a specific tool instead of
+	// deciding on its own, set via Builder.ForceTool. It holds the forced
+	// tool's name; nil means the provider's default ("auto").
+	ToolChoice any
+
+	// Background requests that the provider run this as an async background
+	// job instead of blocking for the result, set via Builder.Background.
+	// Only meaningful to providers implementing BackgroundResponder (e.g.
+	// OpenAI's Responses API, for long-running deep-research models);
+	// ignored otherwise.
+	Background bool
+
+	// ServiceTier requests OpenAI's "auto", "default", "flex", or
+	// "priority" service tier, set via Builder.ServiceTier. Ignored by
+	// providers other than OpenAI.
+	ServiceTier string
+
+	// SafetySettings overrides Gemini's default content-safety thresholds,
+	// set via Builder.SafetySettings. Ignored by providers other than
+	// Google.
+	SafetySettings []SafetySetting
+
+	// Metadata carries arbitrary key/value tags echoed back by OpenAI and
+	// Anthropic in their dashboards and webhooks, set via Builder.Metadata.
+	// Ignored by providers that don't support request metadata.
+	Metadata map[string]string
+
+	// InputItems are pre-built Responses API input items appended after
+	// Messages, set via Builder.InputItems. Used to post back the result of
+	// a tool call - e.g. FunctionCallOutput, ComputerCallOutput,
+	// ShellCallOutput, or MCPApprovalResponse's InputItem() - so a multi-turn
+	// tool conversation can continue. Ignored by providers other than
+	// OpenAI's Responses API.
+	InputItems []any
 }
 
 // ProviderResponse is a unified response structure returned by all providers.
@@ -124,8 +294,140 @@ type ProviderResponse struct {
 	TotalTokens      int
 	FinishReason     string
 
+	// ServedModel is the model ID the provider actually used to fulfill the
+	// request, when it differs from the one requested (e.g. OpenRouter's
+	// automatic routing substituting a different upstream model).
+	ServedModel string
+
 	// Responses API output (populated when using built-in tools)
 	ResponsesOutput *ResponsesOutput
+
+	// Reasoning holds the provider's reasoning/thinking summary, when the
+	// model and API surfaced one (e.g. OpenAI reasoning models' Responses
+	// API "reasoning" output items, Anthropic extended thinking blocks).
+	// It's a summary, not necessarily the full chain-of-thought, and is
+	// meant for auditing rather than display to end users.
+	Reasoning string
+
+	// Raw holds the unparsed response body, populated only when the request
+	// set CaptureRaw (via Builder.CaptureRaw). Left nil otherwise, so large
+	// bodies aren't retained by default.
+	Raw json.RawMessage
+
+	// CachedTokens is the number of prompt tokens the provider served from
+	// its own prompt cache (e.g. OpenAI's
+	// usage.prompt_tokens_details.cached_tokens, Anthropic's
+	// cache_read_input_tokens). 0 if the provider doesn't report one.
+	CachedTokens int
+
+	// ReasoningTokens is the number of CompletionTokens spent on internal
+	// reasoning rather than visible output (e.g. OpenAI's
+	// usage.completion_tokens_details.reasoning_tokens), billed the same as
+	// other completion tokens but worth separating out for cost estimation.
+	// 0 if the provider doesn't report one.
+	ReasoningTokens int
+
+	// AudioOutput holds audio the model returned alongside (or instead of)
+	// text, for audio-capable chat models. Nil unless the provider's
+	// response included one.
+	AudioOutput *AudioOutput
+
+	// RateLimit holds the rate-limit budget the provider reported alongside
+	// this response (e.g. OpenAI's x-ratelimit-* headers), for callers that
+	// want to throttle proactively instead of waiting for a 429. Nil if the
+	// provider didn't report one.
+	RateLimit *RateLimitInfo
+
+	// ServiceTier is the service tier OpenAI actually used to fulfill the
+	// request, which can differ from the one requested via
+	// Builder.ServiceTier if OpenAI downgraded it (e.g. "flex" unavailable).
+	// Empty if the provider doesn't report one.
+	ServiceTier string
+}
+
+// RateLimitInfo is the rate-limit budget a provider reported for the
+// request, parsed from its response headers.
+type RateLimitInfo struct {
+	// LimitRequests and LimitTokens are the requests/tokens allowed per
+	// rate-limit window. 0 if the provider didn't report one.
+	LimitRequests int
+	LimitTokens   int
+
+	// RemainingRequests and RemainingTokens are what's left in the current
+	// window.
+	RemainingRequests int
+	RemainingTokens   int
+
+	// ResetRequests and ResetTokens are how long until each budget resets
+	// to its limit.
+	ResetRequests time.Duration
+	ResetTokens   time.Duration
+}
+
+// captureRawIfRequested returns body as a json.RawMessage when captureRaw is
+// true, or nil otherwise. Shared by every provider's parseResponse so Raw is
+// only retained when the caller opted in via Builder.CaptureRaw.
+func captureRawIfRequested(body []byte, captureRaw bool) json.RawMessage {
+	if !captureRaw {
+		return nil
+	}
+	return json.RawMessage(body)
+}
+
+// buildHTTPClient builds the *http.Client a provider uses for requests from
+// its ProviderConfig. config.Transport takes priority when set (e.g. to
+// route through a corporate proxy or configure custom TLS); otherwise a
+// transport honoring HTTPS_PROXY/HTTP_PROXY via http.ProxyFromEnvironment is
+// used. config.Timeout applies either way.
+func buildHTTPClient(config ProviderConfig) *http.Client {
+	transport := config.Transport
+	if transport == nil {
+		transport = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	return &http.Client{Transport: transport, Timeout: config.Timeout}
+}
+
+// setExtraHeaders applies request-scoped headers (ProviderRequest.Headers,
+// set via Builder.Header) on top of a request's existing headers, winning
+// on key collisions with whatever the provider already set from
+// ProviderConfig.Headers.
+func setExtraHeaders(req *http.Request, extra map[string]string) {
+	for k, v := range extra {
+		req.Header.Set(k, v)
+	}
+}
+
+// hashEndUserID hashes a raw end-user identifier with SHA-256 before it's
+// sent to a provider (as OpenAI's safety_identifier or Anthropic's
+// metadata.user_id), so real user identifiers are never sent upstream.
+func hashEndUserID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
+
+// compressRequestThreshold is the minimum body size, in bytes, below which
+// compressRequestBody leaves the body alone — gzip's own framing overhead
+// can outweigh the savings for small requests.
+const compressRequestThreshold = 4096
+
+// compressRequestBody gzips body when config.CompressRequests is set and
+// body is at least compressRequestThreshold bytes. It returns the (possibly
+// unchanged) body and whether compression was applied, so the caller can set
+// the Content-Encoding header accordingly.
+func compressRequestBody(config ProviderConfig, body []byte) ([]byte, bool) {
+	if !config.CompressRequests || len(body) < compressRequestThreshold {
+		return body, false
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(body); err != nil {
+		return body, false
+	}
+	if err := gz.Close(); err != nil {
+		return body, false
+	}
+	return buf.Bytes(), true
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -486,3 +788,54 @@ func checkCapability(provider Provider, feature string, supported bool) {
 			colorYellow("⚠"), provider.Name(), feature)
 	}
 }
+
+// capabilityMismatch reports the name of the first built-in tool or tool-call
+// feature that model's capabilities (per Capabilities) don't support, or ""
+// if model can satisfy everything b requested. Used by the fallback loop to
+// skip models that would otherwise fail confusingly.
+func capabilityMismatch(model Model, b *Builder) string {
+	caps := Capabilities(model)
+
+	if len(b.tools) > 0 && !caps.Tools {
+		return "tools"
+	}
+
+	for _, bt := range b.builtinTools {
+		switch bt.Type {
+		case "web_search":
+			if !caps.WebSearch {
+				return "web_search"
+			}
+		case "file_search":
+			if !caps.FileSearch {
+				return "file_search"
+			}
+		case "code_interpreter":
+			if !caps.CodeInterpreter {
+				return "code_interpreter"
+			}
+		case "mcp":
+			if !caps.MCP {
+				return "mcp"
+			}
+		case "image_generation":
+			if !caps.ImageGeneration {
+				return "image_generation"
+			}
+		case "computer_use_preview":
+			if !caps.ComputerUse {
+				return "computer_use"
+			}
+		case "shell":
+			if !caps.Shell {
+				return "shell"
+			}
+		case "apply_patch":
+			if !caps.ApplyPatch {
+				return "apply_patch"
+			}
+		}
+	}
+
+	return ""
+}
@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -33,10 +32,7 @@ func NewAnthropicProvider(config ProviderConfig) *AnthropicProvider {
 	if config.APIKey == "" {
 		config.APIKey = os.Getenv("ANTHROPIC_API_KEY")
 	}
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
-	}
+	client := buildHTTPClient(config)
 	return &AnthropicProvider{config: config, httpClient: client}
 }
 
@@ -57,6 +53,13 @@ func (p *AnthropicProvider) Capabilities() ProviderCapabilities {
 	}
 }
 
+// CloseIdleConnections closes any idle connections on the underlying HTTP
+// transport, releasing them back to the OS instead of leaving them open
+// until they time out on their own. Client.Close calls this.
+func (p *AnthropicProvider) CloseIdleConnections() {
+	p.httpClient.CloseIdleConnections()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Send
 // ═══════════════════════════════════════════════════════════════════════════
@@ -77,12 +80,17 @@ func (p *AnthropicProvider) Send(ctx context.Context, req *ProviderRequest) (*Pr
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/messages")
@@ -99,7 +107,7 @@ func (p *AnthropicProvider) Send(ctx context.Context, req *ProviderRequest) (*Pr
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponse(respBody)
+	return p.parseResponse(respBody, req.CaptureRaw)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -123,12 +131,17 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/messages", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/messages")
@@ -150,24 +163,20 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 	}
 
 	var fullContent strings.Builder
-	reader := bufio.NewReader(resp.Body)
+	sse := newSSEReader(resp.Body)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		data, err := readWithContext(ctx, resp.Body, func() (string, error) { return sse.Next(p.Name()) })
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
-		}
-
-		line = bytes.TrimSpace(line)
-		if !bytes.HasPrefix(line, []byte("data: ")) {
-			continue
+			if ctx.Err() != nil {
+				return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
+			}
+			return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
 		}
 
-		data := bytes.TrimPrefix(line, []byte("data: "))
-
 		// Anthropic stream events
 		var event struct {
 			Type  string `json:"type"`
@@ -177,7 +186,10 @@ func (p *AnthropicProvider) SendStream(ctx context.Context, req *ProviderRequest
 			} `json:"delta"`
 		}
 
-		if err := json.Unmarshal(data, &event); err != nil {
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			if Debug {
+				fmt.Printf("%s [%s] skipping unparseable stream event: %s\n", colorYellow("⚠"), p.Name(), data)
+			}
 			continue
 		}
 
@@ -215,8 +227,31 @@ type anthropicRequest struct {
 	Stream      bool               `json:"stream,omitempty"`
 	Temperature *float64           `json:"temperature,omitempty"`
 	Tools       []anthropicTool    `json:"tools,omitempty"`
+	ToolChoice  any                `json:"tool_choice,omitempty"`
 	// Extended thinking (Claude)
 	Thinking *anthropicThinking `json:"thinking,omitempty"`
+	Metadata *anthropicMetadata `json:"metadata,omitempty"`
+}
+
+// anthropicMetadata carries request metadata Anthropic uses for abuse
+// detection, such as a stable per-end-user identifier, plus arbitrary
+// caller-supplied tags set via Builder.Metadata.
+type anthropicMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+	Extra  map[string]string
+}
+
+// MarshalJSON flattens Extra alongside UserID, so callers see a single
+// "metadata" object rather than a nested one.
+func (m anthropicMetadata) MarshalJSON() ([]byte, error) {
+	out := make(map[string]string, len(m.Extra)+1)
+	for k, v := range m.Extra {
+		out[k] = v
+	}
+	if m.UserID != "" {
+		out["user_id"] = m.UserID
+	}
+	return json.Marshal(out)
 }
 
 type anthropicMessage struct {
@@ -361,12 +396,19 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 		case ThinkingHigh:
 			budgetTokens = 16384
 		}
+		if req.ThinkingBudgetTokens > 0 {
+			budgetTokens = req.ThinkingBudgetTokens
+		}
 		anthropicReq.Thinking = &anthropicThinking{
 			Type:         "enabled",
 			BudgetTokens: budgetTokens,
 		}
 	}
 
+	if req.EndUser != "" || len(req.Metadata) > 0 {
+		anthropicReq.Metadata = &anthropicMetadata{UserID: req.EndUser, Extra: req.Metadata}
+	}
+
 	// Convert tools to Anthropic format
 	if len(req.Tools) > 0 {
 		for _, tool := range req.Tools {
@@ -376,12 +418,23 @@ func (p *AnthropicProvider) buildRequest(req *ProviderRequest) *anthropicRequest
 				InputSchema: tool.Function.Parameters,
 			})
 		}
+		if name, ok := req.ToolChoice.(string); ok && name != "" {
+			anthropicReq.ToolChoice = map[string]string{
+				"type": "tool",
+				"name": name,
+			}
+		}
 	}
 
 	return anthropicReq
 }
 
-func (p *AnthropicProvider) setHeaders(req *http.Request) {
+// BuildRawRequest implements RequestPreviewer.
+func (p *AnthropicProvider) BuildRawRequest(req *ProviderRequest) any {
+	return p.buildRequest(req)
+}
+
+func (p *AnthropicProvider) setHeaders(req *http.Request, extra map[string]string) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("x-api-key", p.config.APIKey)
 	req.Header.Set("anthropic-version", anthropicAPIVersion)
@@ -389,24 +442,27 @@ func (p *AnthropicProvider) setHeaders(req *http.Request) {
 	for k, v := range p.config.Headers {
 		req.Header.Set(k, v)
 	}
+	setExtraHeaders(req, extra)
 }
 
-func (p *AnthropicProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+func (p *AnthropicProvider) parseResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
 	var result struct {
 		ID      string `json:"id"`
 		Type    string `json:"type"`
 		Role    string `json:"role"`
 		Content []struct {
-			Type  string         `json:"type"`
-			Text  string         `json:"text,omitempty"`
-			ID    string         `json:"id,omitempty"`
-			Name  string         `json:"name,omitempty"`
-			Input map[string]any `json:"input,omitempty"`
+			Type     string         `json:"type"`
+			Text     string         `json:"text,omitempty"`
+			Thinking string         `json:"thinking,omitempty"`
+			ID       string         `json:"id,omitempty"`
+			Name     string         `json:"name,omitempty"`
+			Input    map[string]any `json:"input,omitempty"`
 		} `json:"content"`
 		StopReason string `json:"stop_reason"`
 		Usage      struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
+			InputTokens          int `json:"input_tokens"`
+			OutputTokens         int `json:"output_tokens"`
+			CacheReadInputTokens int `json:"cache_read_input_tokens"`
 		} `json:"usage"`
 		Error *struct {
 			Type    string `json:"type"`
@@ -424,19 +480,25 @@ func (p *AnthropicProvider) parseResponse(body []byte) (*ProviderResponse, error
 	if result.Error != nil {
 		return nil, &ProviderError{
 			Provider: p.Name(),
-			Code:     result.Error.Type,
+			Code:     classifyErrorCode(result.Error.Type, result.Error.Message),
 			Message:  result.Error.Message,
 		}
 	}
 
 	// Extract text content and tool calls
 	var content strings.Builder
+	var reasoning strings.Builder
 	var toolCalls []ToolCall
 
 	for _, block := range result.Content {
 		switch block.Type {
 		case "text":
 			content.WriteString(block.Text)
+		case "thinking":
+			if reasoning.Len() > 0 {
+				reasoning.WriteString("\n")
+			}
+			reasoning.WriteString(block.Thinking)
 		case "tool_use":
 			// Convert to our ToolCall format
 			argsJSON, _ := json.Marshal(block.Input)
@@ -460,6 +522,9 @@ func (p *AnthropicProvider) parseResponse(body []byte) (*ProviderResponse, error
 		PromptTokens:     result.Usage.InputTokens,
 		CompletionTokens: result.Usage.OutputTokens,
 		TotalTokens:      result.Usage.InputTokens + result.Usage.OutputTokens,
+		CachedTokens:     result.Usage.CacheReadInputTokens,
 		FinishReason:     result.StopReason,
+		Reasoning:        reasoning.String(),
+		Raw:              captureRawIfRequested(body, captureRaw),
 	}, nil
 }
@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -31,10 +30,7 @@ func NewGoogleProvider(config ProviderConfig) *GoogleProvider {
 	if config.APIKey == "" {
 		config.APIKey = getEnvWithFallback("GOOGLE_API_KEY", "GEMINI_API_KEY")
 	}
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
-	}
+	client := buildHTTPClient(config)
 	return &GoogleProvider{config: config, httpClient: client}
 }
 
@@ -55,6 +51,13 @@ func (p *GoogleProvider) Capabilities() ProviderCapabilities {
 	}
 }
 
+// CloseIdleConnections closes any idle connections on the underlying HTTP
+// transport, releasing them back to the OS instead of leaving them open
+// until they time out on their own. Client.Close calls this.
+func (p *GoogleProvider) CloseIdleConnections() {
+	p.httpClient.CloseIdleConnections()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Send
 // ═══════════════════════════════════════════════════════════════════════════
@@ -76,13 +79,18 @@ func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
+	reqBody, compressed := compressRequestBody(p.config, body)
+
 	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", p.config.BaseURL, model, p.config.APIKey)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST /models/%s:generateContent\n", colorDim("→"), p.Name(), model)
@@ -99,7 +107,7 @@ func (p *GoogleProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponse(respBody)
+	return p.parseResponse(respBody, req.CaptureRaw)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -123,13 +131,18 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
+	reqBody, compressed := compressRequestBody(p.config, body)
+
 	url := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse&key=%s", p.config.BaseURL, model, p.config.APIKey)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST /models/%s:streamGenerateContent (stream)\n", colorDim("→"), p.Name(), model)
@@ -151,24 +164,20 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 	}
 
 	var fullContent strings.Builder
-	reader := bufio.NewReader(resp.Body)
+	sse := newSSEReader(resp.Body)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		data, err := readWithContext(ctx, resp.Body, func() (string, error) { return sse.Next(p.Name()) })
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
-		}
-
-		line = bytes.TrimSpace(line)
-		if !bytes.HasPrefix(line, []byte("data: ")) {
-			continue
+			if ctx.Err() != nil {
+				return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
+			}
+			return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
 		}
 
-		data := bytes.TrimPrefix(line, []byte("data: "))
-
 		var chunk struct {
 			Candidates []struct {
 				Content struct {
@@ -179,7 +188,10 @@ func (p *GoogleProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 			} `json:"candidates"`
 		}
 
-		if err := json.Unmarshal(data, &chunk); err != nil {
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			if Debug {
+				fmt.Printf("%s [%s] skipping unparseable stream event: %s\n", colorYellow("⚠"), p.Name(), data)
+			}
 			continue
 		}
 
@@ -209,6 +221,13 @@ type geminiRequest struct {
 	SystemInstruct   *geminiContent        `json:"systemInstruction,omitempty"`
 	GenerationConfig *geminiGenerateConfig `json:"generationConfig,omitempty"`
 	Tools            []geminiTool          `json:"tools,omitempty"`
+	SafetySettings   []geminiSafetySetting `json:"safetySettings,omitempty"`
+}
+
+// geminiSafetySetting is the wire format for one entry of SafetySettings.
+type geminiSafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
 }
 
 type geminiContent struct {
@@ -235,6 +254,7 @@ type geminiFileData struct {
 type geminiGenerateConfig struct {
 	Temperature      *float64              `json:"temperature,omitempty"`
 	ResponseMimeType string                `json:"responseMimeType,omitempty"`
+	ResponseSchema   map[string]any        `json:"responseSchema,omitempty"`
 	ThinkingConfig   *geminiThinkingConfig `json:"thinkingConfig,omitempty"`
 }
 
@@ -349,8 +369,21 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 		geminiReq.GenerationConfig.ResponseMimeType = "application/json"
 	}
 
-	// Thinking/reasoning config
-	if req.Thinking != "" {
+	// Gemini supports schema-guaranteed JSON output via responseSchema, instead
+	// of just prompting the model to produce JSON.
+	if req.Schema != nil {
+		geminiReq.GenerationConfig.ResponseMimeType = "application/json"
+		geminiReq.GenerationConfig.ResponseSchema = resolveSchema(req.Schema)
+	}
+
+	// Thinking/reasoning config. Gemini 3 rejects a request that sets both
+	// thinkingBudget and thinkingLevel, so an explicit token budget takes
+	// the legacy field instead of the bucketed level.
+	if req.ThinkingBudgetTokens > 0 {
+		geminiReq.GenerationConfig.ThinkingConfig = &geminiThinkingConfig{
+			ThinkingBudget: req.ThinkingBudgetTokens,
+		}
+	} else if req.Thinking != "" {
 		geminiReq.GenerationConfig.ThinkingConfig = &geminiThinkingConfig{
 			ThinkingLevel: string(req.Thinking),
 		}
@@ -369,18 +402,32 @@ func (p *GoogleProvider) buildRequest(req *ProviderRequest) *geminiRequest {
 		geminiReq.Tools = []geminiTool{{FunctionDeclarations: funcs}}
 	}
 
+	// Safety settings
+	for _, setting := range req.SafetySettings {
+		geminiReq.SafetySettings = append(geminiReq.SafetySettings, geminiSafetySetting{
+			Category:  string(setting.Category),
+			Threshold: string(setting.Threshold),
+		})
+	}
+
 	return geminiReq
 }
 
-func (p *GoogleProvider) setHeaders(req *http.Request) {
+// BuildRawRequest implements RequestPreviewer.
+func (p *GoogleProvider) BuildRawRequest(req *ProviderRequest) any {
+	return p.buildRequest(req)
+}
+
+func (p *GoogleProvider) setHeaders(req *http.Request, extra map[string]string) {
 	req.Header.Set("Content-Type", "application/json")
 
 	for k, v := range p.config.Headers {
 		req.Header.Set(k, v)
 	}
+	setExtraHeaders(req, extra)
 }
 
-func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+func (p *GoogleProvider) parseResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
 	var result struct {
 		Candidates []struct {
 			Content struct {
@@ -400,6 +447,9 @@ func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 			CandidatesTokenCount int `json:"candidatesTokenCount"`
 			TotalTokenCount      int `json:"totalTokenCount"`
 		} `json:"usageMetadata"`
+		PromptFeedback *struct {
+			BlockReason string `json:"blockReason,omitempty"`
+		} `json:"promptFeedback,omitempty"`
 		Error *struct {
 			Code    int    `json:"code"`
 			Message string `json:"message"`
@@ -417,11 +467,22 @@ func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	if result.Error != nil {
 		return nil, &ProviderError{
 			Provider: p.Name(),
-			Code:     result.Error.Status,
+			Code:     classifyErrorCode(result.Error.Status, result.Error.Message),
 			Message:  result.Error.Message,
 		}
 	}
 
+	// The prompt itself can be blocked before any candidate is generated, in
+	// which case Candidates is empty and the reason lives on PromptFeedback
+	// instead of a per-candidate FinishReason.
+	if result.PromptFeedback != nil && result.PromptFeedback.BlockReason != "" {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     ErrBlockedBySafety,
+			Message:  fmt.Sprintf("prompt blocked by safety filters: %s", result.PromptFeedback.BlockReason),
+		}
+	}
+
 	if len(result.Candidates) == 0 {
 		return nil, &ProviderError{
 			Provider: p.Name(),
@@ -429,6 +490,16 @@ func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 		}
 	}
 
+	// A candidate can also be cut short by safety filters mid-generation,
+	// which otherwise looks like an ordinary empty-content response.
+	if result.Candidates[0].FinishReason == "SAFETY" {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     ErrBlockedBySafety,
+			Message:  "response blocked by safety filters",
+		}
+	}
+
 	// Extract text content and tool calls
 	var content strings.Builder
 	var toolCalls []ToolCall
@@ -461,5 +532,6 @@ func (p *GoogleProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 		CompletionTokens: result.UsageMetadata.CandidatesTokenCount,
 		TotalTokens:      result.UsageMetadata.TotalTokenCount,
 		FinishReason:     candidate.FinishReason,
+		Raw:              captureRawIfRequested(body, captureRaw),
 	}, nil
 }
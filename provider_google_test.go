@@ -0,0 +1,139 @@
+package ai
+
+import "testing"
+
+func TestGoogleProvider_BuildRequest_SchemaSetsResponseSchema(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	type Answer struct {
+		Text string `json:"text"`
+	}
+
+	req := &ProviderRequest{
+		Model:    string(ModelGemini3Flash),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Schema:   &Answer{},
+	}
+
+	geminiReq := p.buildRequest(req)
+
+	if geminiReq.GenerationConfig.ResponseMimeType != "application/json" {
+		t.Fatalf("expected responseMimeType to be set, got %q", geminiReq.GenerationConfig.ResponseMimeType)
+	}
+
+	schema := geminiReq.GenerationConfig.ResponseSchema
+	if schema == nil {
+		t.Fatalf("expected responseSchema to be set")
+	}
+	if schema["type"] != "object" {
+		t.Fatalf("expected object schema, got %+v", schema)
+	}
+	props, ok := schema["properties"].(map[string]any)
+	if !ok || props["text"] == nil {
+		t.Fatalf("expected text property in schema, got %+v", schema)
+	}
+}
+
+func TestGoogleProvider_BuildRequest_ExplicitThinkingBudgetUsesLegacyField(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	req := &ProviderRequest{
+		Model:                string(ModelGemini3Flash),
+		Messages:             []Message{{Role: "user", Content: "hi"}},
+		Thinking:             ThinkingHigh,
+		ThinkingBudgetTokens: 2048,
+	}
+
+	geminiReq := p.buildRequest(req)
+
+	cfg := geminiReq.GenerationConfig.ThinkingConfig
+	if cfg == nil {
+		t.Fatalf("expected thinkingConfig to be set")
+	}
+	if cfg.ThinkingBudget != 2048 {
+		t.Fatalf("expected thinkingBudget=2048, got %d", cfg.ThinkingBudget)
+	}
+	if cfg.ThinkingLevel != "" {
+		t.Fatalf("expected thinkingLevel to be unset when an explicit budget is given, got %q", cfg.ThinkingLevel)
+	}
+}
+
+func TestGoogleProvider_BuildRequest_JSONModeWithoutSchema(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	req := &ProviderRequest{
+		Model:    string(ModelGemini3Flash),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		JSONMode: true,
+	}
+
+	geminiReq := p.buildRequest(req)
+
+	if geminiReq.GenerationConfig.ResponseMimeType != "application/json" {
+		t.Fatalf("expected responseMimeType to be set, got %q", geminiReq.GenerationConfig.ResponseMimeType)
+	}
+	if geminiReq.GenerationConfig.ResponseSchema != nil {
+		t.Fatalf("expected no responseSchema without Schema set, got %+v", geminiReq.GenerationConfig.ResponseSchema)
+	}
+}
+
+func TestGoogleProvider_BuildRequest_SafetySettings(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	req := &ProviderRequest{
+		Model:    string(ModelGemini3Flash),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		SafetySettings: []SafetySetting{
+			{Category: SafetyHarassment, Threshold: SafetyBlockNone},
+			{Category: SafetyDangerous, Threshold: SafetyBlockHigh},
+		},
+	}
+
+	geminiReq := p.buildRequest(req)
+
+	if len(geminiReq.SafetySettings) != 2 {
+		t.Fatalf("expected 2 safety settings, got %d", len(geminiReq.SafetySettings))
+	}
+	if geminiReq.SafetySettings[0].Category != "HARM_CATEGORY_HARASSMENT" || geminiReq.SafetySettings[0].Threshold != "BLOCK_NONE" {
+		t.Fatalf("unexpected first safety setting: %+v", geminiReq.SafetySettings[0])
+	}
+	if geminiReq.SafetySettings[1].Category != "HARM_CATEGORY_DANGEROUS_CONTENT" || geminiReq.SafetySettings[1].Threshold != "BLOCK_ONLY_HIGH" {
+		t.Fatalf("unexpected second safety setting: %+v", geminiReq.SafetySettings[1])
+	}
+}
+
+func TestGoogleProvider_ParseResponse_BlockedBySafetyDuringGeneration(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	body := []byte(`{"candidates":[{"content":{"parts":[],"role":"model"},"finishReason":"SAFETY"}]}`)
+
+	_, err := p.parseResponse(body, false)
+	if err == nil {
+		t.Fatal("expected an error for a SAFETY finish reason")
+	}
+	pErr, ok := err.(*ProviderError)
+	if !ok {
+		t.Fatalf("expected a *ProviderError, got %T", err)
+	}
+	if pErr.Code != ErrBlockedBySafety {
+		t.Fatalf("expected Code %q, got %q", ErrBlockedBySafety, pErr.Code)
+	}
+}
+
+func TestGoogleProvider_ParseResponse_BlockedBySafetyBeforeGeneration(t *testing.T) {
+	p := NewGoogleProvider(ProviderConfig{APIKey: "k"})
+
+	body := []byte(`{"promptFeedback":{"blockReason":"SAFETY"}}`)
+
+	_, err := p.parseResponse(body, false)
+	if err == nil {
+		t.Fatal("expected an error when the prompt itself is blocked")
+	}
+	pErr, ok := err.(*ProviderError)
+	if !ok {
+		t.Fatalf("expected a *ProviderError, got %T", err)
+	}
+	if pErr.Code != ErrBlockedBySafety {
+		t.Fatalf("expected Code %q, got %q", ErrBlockedBySafety, pErr.Code)
+	}
+}
@@ -1,12 +1,20 @@
 package ai
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -86,6 +94,240 @@ func TestOpenAIProvider_Send_BuildsExpectedRequest(t *testing.T) {
 	}
 }
 
+func TestOpenAIProvider_Send_DropsTemperatureForReasoningModel(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	temp := 0.7
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:       string(ModelO1),
+		Messages:    []Message{{Role: "user", Content: "hi"}},
+		Temperature: &temp,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := gotBody["temperature"]; ok {
+		t.Fatalf("expected temperature to be dropped for reasoning model, got %#v", gotBody["temperature"])
+	}
+}
+
+func TestOpenAIProvider_Send_KeepsTemperatureForNonReasoningModel(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	temp := 0.7
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:       string(ModelGPT4o),
+		Messages:    []Message{{Role: "user", Content: "hi"}},
+		Temperature: &temp,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody["temperature"] != 0.7 {
+		t.Fatalf("expected temperature to be preserved for non-reasoning model, got %#v", gotBody["temperature"])
+	}
+}
+
+func TestOpenAIProvider_Send_SendsAndParsesServiceTier(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],
+			"service_tier":"flex"
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:       string(ModelGPT5),
+		Messages:    []Message{{Role: "user", Content: "hi"}},
+		ServiceTier: "flex",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody["service_tier"] != "flex" {
+		t.Fatalf("expected service_tier=flex in request body, got %#v", gotBody["service_tier"])
+	}
+	if resp.ServiceTier != "flex" {
+		t.Fatalf("expected ServiceTier parsed from response, got %q", resp.ServiceTier)
+	}
+}
+
+func TestOpenAIProvider_Send_ParsesReasoningTokens(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":10,"completion_tokens":50,"total_tokens":60,"completion_tokens_details":{"reasoning_tokens":30}}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelO1),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Thinking: ThinkingHigh,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ReasoningTokens != 30 {
+		t.Fatalf("expected ReasoningTokens of 30, got %d", resp.ReasoningTokens)
+	}
+	if resp.CompletionTokens != 50 {
+		t.Fatalf("expected CompletionTokens of 50, got %d", resp.CompletionTokens)
+	}
+}
+
+func TestOpenAIProvider_Send_ParsesRateLimitHeaders(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+	defer ClearHooks()
+
+	var gotProvider string
+	var gotInfo RateLimitInfo
+	OnRateLimitInfo(func(provider string, info RateLimitInfo) {
+		gotProvider = provider
+		gotInfo = info
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-limit-requests", "10000")
+		w.Header().Set("x-ratelimit-limit-tokens", "2000000")
+		w.Header().Set("x-ratelimit-remaining-requests", "9999")
+		w.Header().Set("x-ratelimit-remaining-tokens", "1999950")
+		w.Header().Set("x-ratelimit-reset-requests", "6m0s")
+		w.Header().Set("x-ratelimit-reset-tokens", "1.5s")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.RateLimit == nil {
+		t.Fatal("expected RateLimit to be populated on the response")
+	}
+	if resp.RateLimit.RemainingRequests != 9999 || resp.RateLimit.RemainingTokens != 1999950 {
+		t.Fatalf("unexpected RateLimit: %+v", resp.RateLimit)
+	}
+	if resp.RateLimit.ResetRequests != 6*time.Minute {
+		t.Fatalf("expected ResetRequests of 6m, got %v", resp.RateLimit.ResetRequests)
+	}
+
+	if gotProvider != "openai" {
+		t.Fatalf("expected OnRateLimitInfo hook to fire with provider openai, got %q", gotProvider)
+	}
+	if gotInfo.LimitRequests != 10000 || gotInfo.LimitTokens != 2000000 {
+		t.Fatalf("unexpected hook info: %+v", gotInfo)
+	}
+}
+
+func TestOpenAIProvider_Send_SendsAndParsesAudio(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+
+	audioBytes := []byte("fake-wav-bytes")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"","audio":{"data":"` +
+			base64.StdEncoding.EncodeToString(audioBytes) + `","transcript":"hello there"}},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model: string(ModelGPTAudio),
+		Messages: []Message{{Role: "user", Content: []ContentPart{
+			{Type: "text", Text: "what's this?"},
+			{Type: "input_audio", InputAudio: &InputAudio{Data: base64.StdEncoding.EncodeToString([]byte("q")), Format: "wav"}},
+		}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs, _ := gotBody["messages"].([]any)
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %#v", gotBody["messages"])
+	}
+	parts, _ := msgs[0].(map[string]any)["content"].([]any)
+	var sawInputAudio bool
+	for _, part := range parts {
+		if m, ok := part.(map[string]any); ok && m["type"] == "input_audio" {
+			sawInputAudio = true
+		}
+	}
+	if !sawInputAudio {
+		t.Fatalf("expected an input_audio content part in the request, got %#v", parts)
+	}
+
+	if resp.AudioOutput == nil {
+		t.Fatal("expected AudioOutput to be populated")
+	}
+	if resp.AudioOutput.Transcript != "hello there" {
+		t.Errorf("expected transcript %q, got %q", "hello there", resp.AudioOutput.Transcript)
+	}
+	if string(resp.AudioOutput.Data) != string(audioBytes) {
+		t.Errorf("expected decoded audio bytes %q, got %q", audioBytes, resp.AudioOutput.Data)
+	}
+}
+
 func TestOpenRouterProvider_Send_BuildsExpectedRequest(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -106,6 +348,7 @@ func TestOpenRouterProvider_Send_BuildsExpectedRequest(t *testing.T) {
 
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte(`{
+			"model":"anthropic/claude-3.5-sonnet",
 			"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],
 			"usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}
 		}`))
@@ -148,35 +391,1139 @@ func TestOpenRouterProvider_Send_BuildsExpectedRequest(t *testing.T) {
 	if resp.Content != "ok" || resp.TotalTokens != 30 {
 		t.Fatalf("unexpected response: %#v", resp)
 	}
+	if resp.ServedModel != "anthropic/claude-3.5-sonnet" {
+		t.Fatalf("expected served model to reflect OpenRouter's routing, got %q", resp.ServedModel)
+	}
 }
 
-func TestOpenAIProvider_WithTimeout_IsRespected(t *testing.T) {
+func TestOpenRouterProvider_Send_HeadersOverridableFromConfig(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
 
+	var gotReferer, gotTitle string
+
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(200 * time.Millisecond)
+		gotReferer = r.Header.Get("HTTP-Referer")
+		gotTitle = r.Header.Get("X-Title")
+
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"late"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+		_, _ = w.Write([]byte(`{
+			"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],
+			"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenRouterProvider(ProviderConfig{
+		APIKey:  "k",
+		BaseURL: srv.URL,
+		Headers: map[string]string{
+			"HTTP-Referer": "https://example.com",
+			"X-Title":      "My App",
+		},
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelClaudeOpus),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotReferer != "https://example.com" {
+		t.Fatalf("expected HTTP-Referer from config, got %q", gotReferer)
+	}
+	if gotTitle != "My App" {
+		t.Fatalf("expected X-Title from config, got %q", gotTitle)
+	}
+}
+
+func TestOpenAIProvider_Send_RequestHeadersWinOverConfig(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotRequestID, gotCustom string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequestID = r.Header.Get("X-Request-Id")
+		gotCustom = r.Header.Get("X-Custom")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
 	}))
 	defer srv.Close()
 
 	p := NewOpenAIProvider(ProviderConfig{
 		APIKey:  "k",
 		BaseURL: srv.URL,
-		Timeout: 50 * time.Millisecond,
-		Headers: map[string]string{"X-Test": "1"},
+		Headers: map[string]string{"X-Custom": "from-config"},
 	})
 
 	_, err := p.Send(context.Background(), &ProviderRequest{
 		Model:    string(ModelGPT4o),
 		Messages: []Message{{Role: "user", Content: "hi"}},
+		Headers: map[string]string{
+			"X-Request-Id": "req-123",
+			"X-Custom":     "from-request",
+		},
 	})
-	if err == nil {
-		t.Fatalf("expected timeout error")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	// Provider wraps the underlying timeout error; assert via Unwrap().
-	if !errors.Is(err, context.DeadlineExceeded) {
-		t.Fatalf("expected context deadline exceeded via unwrap, got: %v", err)
+
+	if gotRequestID != "req-123" {
+		t.Fatalf("expected X-Request-Id header, got %q", gotRequestID)
+	}
+	if gotCustom != "from-request" {
+		t.Fatalf("expected request-scoped header to win over config, got %q", gotCustom)
+	}
+}
+
+func TestOpenAIProvider_Send_SendsHashedSafetyIdentifier(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	sum := sha256.Sum256([]byte("user-42"))
+	wantHash := hex.EncodeToString(sum[:])
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		EndUser:  wantHash,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotBody["safety_identifier"] != wantHash {
+		t.Fatalf("expected safety_identifier %q, got %v", wantHash, gotBody["safety_identifier"])
+	}
+}
+
+func TestOpenAIProvider_Send_SendsReasoningSummary(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp_1",
+			"output": [{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "ok"}]}],
+			"usage": {"input_tokens": 1, "output_tokens": 1, "total_tokens": 2}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:            string(ModelGPT5),
+		Messages:         []Message{{Role: "user", Content: "hi"}},
+		BuiltinTools:     []BuiltinTool{{Type: "web_search"}},
+		Thinking:         ThinkingHigh,
+		ReasoningSummary: "detailed",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reasoning, ok := gotBody["reasoning"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected reasoning object in request body, got %#v", gotBody["reasoning"])
+	}
+	if reasoning["effort"] != "high" {
+		t.Fatalf("expected reasoning.effort=high, got %#v", reasoning["effort"])
+	}
+	if reasoning["summary"] != "detailed" {
+		t.Fatalf("expected reasoning.summary=detailed, got %#v", reasoning["summary"])
+	}
+}
+
+func TestOpenAIProvider_Send_SendsVerbosity(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:     string(ModelGPT4o),
+		Messages:  []Message{{Role: "user", Content: "hi"}},
+		Verbosity: "low",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, ok := gotBody["text"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected text object in request body, got %#v", gotBody["text"])
+	}
+	if text["verbosity"] != "low" {
+		t.Fatalf("expected text.verbosity=low, got %#v", text["verbosity"])
+	}
+}
+
+func TestOpenAIProvider_Send_ForcesToolChoice(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:      string(ModelGPT4o),
+		Messages:   []Message{{Role: "user", Content: "hi"}},
+		Tools:      []Tool{{Type: "function", Function: ToolFunction{Name: "extract"}}},
+		ToolChoice: "extract",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	toolChoice, ok := gotBody["tool_choice"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected tool_choice object in request body, got %#v", gotBody["tool_choice"])
+	}
+	if toolChoice["type"] != "function" {
+		t.Fatalf("expected tool_choice.type=function, got %#v", toolChoice["type"])
+	}
+	fn, ok := toolChoice["function"].(map[string]any)
+	if !ok || fn["name"] != "extract" {
+		t.Fatalf("expected tool_choice.function.name=extract, got %#v", toolChoice["function"])
+	}
+}
+
+func TestOpenAIProvider_Send_SendsJSONSchemaResponseFormatForSchema(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	type answer struct {
+		Label string `json:"label"`
+	}
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Schema:   answer{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	format, ok := gotBody["response_format"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected response_format object, got %#v", gotBody["response_format"])
+	}
+	if format["type"] != "json_schema" {
+		t.Fatalf("expected response_format.type=json_schema, got %#v", format["type"])
+	}
+	jsonSchema, ok := format["json_schema"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected json_schema object, got %#v", format["json_schema"])
+	}
+	if jsonSchema["strict"] != true {
+		t.Fatalf("expected json_schema.strict=true, got %#v", jsonSchema["strict"])
+	}
+	schema, ok := jsonSchema["schema"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected json_schema.schema object, got %#v", jsonSchema["schema"])
+	}
+	props, ok := schema["properties"].(map[string]any)
+	if !ok || props["label"] == nil {
+		t.Fatalf("expected schema.properties.label, got %#v", schema["properties"])
+	}
+}
+
+func TestOpenAIProvider_Send_SendsMessageNameForMultiPersonaTurns(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model: string(ModelGPT4o),
+		Messages: []Message{
+			{Role: "user", Name: "Alice", Content: "hi Bob"},
+			{Role: "user", Name: "Bob", Content: "hi Alice"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	msgs, ok := gotBody["messages"].([]any)
+	if !ok || len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %#v", gotBody["messages"])
+	}
+	if m0 := msgs[0].(map[string]any); m0["name"] != "Alice" {
+		t.Errorf("expected first message name=Alice, got %#v", m0["name"])
+	}
+	if m1 := msgs[1].(map[string]any); m1["name"] != "Bob" {
+		t.Errorf("expected second message name=Bob, got %#v", m1["name"])
+	}
+}
+
+func TestAnthropicProvider_Send_ForcesToolChoice(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:      string(ModelClaudeOpus),
+		Messages:   []Message{{Role: "user", Content: "hi"}},
+		Tools:      []Tool{{Type: "function", Function: ToolFunction{Name: "extract"}}},
+		ToolChoice: "extract",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	toolChoice, ok := gotBody["tool_choice"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected tool_choice object in request body, got %#v", gotBody["tool_choice"])
+	}
+	if toolChoice["type"] != "tool" || toolChoice["name"] != "extract" {
+		t.Fatalf("expected tool_choice={type:tool,name:extract}, got %#v", toolChoice)
+	}
+}
+
+func TestAnthropicProvider_Send_ExplicitThinkingBudgetOverridesBucketedDefault(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}],"stop_reason":"end_turn","usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:                string(ModelClaudeOpus),
+		Messages:             []Message{{Role: "user", Content: "hi"}},
+		Thinking:             ThinkingHigh,
+		ThinkingBudgetTokens: 777,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	thinking, ok := gotBody["thinking"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected thinking object in request body, got %#v", gotBody["thinking"])
+	}
+	if thinking["budget_tokens"].(float64) != 777 {
+		t.Fatalf("expected the explicit budget to override ThinkingHigh's default, got %#v", thinking["budget_tokens"])
+	}
+}
+
+func TestAnthropicProvider_Send_SendsHashedUserIDInMetadata(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	sum := sha256.Sum256([]byte("user-42"))
+	wantHash := hex.EncodeToString(sum[:])
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelClaudeSonnet),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		EndUser:  wantHash,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, ok := gotBody["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected metadata object in request body, got %v", gotBody["metadata"])
+	}
+	if metadata["user_id"] != wantHash {
+		t.Fatalf("expected metadata.user_id %q, got %v", wantHash, metadata["user_id"])
+	}
+}
+
+func TestAnthropicProvider_Send_SendsArbitraryMetadata(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":1,"output_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelClaudeSonnet),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Metadata: map[string]string{"tenant_id": "acme", "trace_id": "t-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, ok := gotBody["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected metadata object in request body, got %v", gotBody["metadata"])
+	}
+	if metadata["tenant_id"] != "acme" || metadata["trace_id"] != "t-1" {
+		t.Fatalf("expected custom metadata tags, got %v", metadata)
+	}
+}
+
+func TestOpenAIProvider_Send_SendsMetadata(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1,"completion_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Metadata: map[string]string{"tenant_id": "acme", "trace_id": "t-1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadata, ok := gotBody["metadata"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected metadata object in request body, got %v", gotBody["metadata"])
+	}
+	if metadata["tenant_id"] != "acme" || metadata["trace_id"] != "t-1" {
+		t.Fatalf("expected custom metadata tags, got %v", metadata)
+	}
+}
+
+func TestBuilderMetadata_SetsMetadataField(t *testing.T) {
+	b := New(ModelGPT5).Metadata(map[string]string{"tenant_id": "acme"})
+
+	if b.metadata["tenant_id"] != "acme" {
+		t.Fatalf("expected metadata tenant_id %q, got %v", "acme", b.metadata)
+	}
+}
+
+func TestBuilderInputItems_AppendsAcrossCalls(t *testing.T) {
+	b := New(ModelGPT5).
+		InputItems(FunctionCallOutput{CallID: "call_1", Output: "sunny"}.InputItem()).
+		InputItems(MCPApprovalResponse{ApprovalRequestID: "mcpr_1", Approve: true}.InputItem())
+
+	if len(b.inputItems) != 2 {
+		t.Fatalf("expected 2 input items, got %d", len(b.inputItems))
+	}
+}
+
+func TestMCPApprovalResponse_InputItem_MarshalsApproveFlag(t *testing.T) {
+	item := MCPApprovalResponse{ApprovalRequestID: "mcpr_1", Approve: true}.InputItem()
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded["type"] != "mcp_approval_response" || decoded["approval_request_id"] != "mcpr_1" || decoded["approve"] != true {
+		t.Errorf("unexpected marshaled item: %v", decoded)
+	}
+}
+
+func TestBuilderEndUser_HashesRawID(t *testing.T) {
+	b := New(ModelGPT5).EndUser("user-42")
+
+	sum := sha256.Sum256([]byte("user-42"))
+	wantHash := hex.EncodeToString(sum[:])
+
+	if b.endUser != wantHash {
+		t.Fatalf("expected hashed end user %q, got %q", wantHash, b.endUser)
+	}
+}
+
+func TestOpenAIProvider_WithTimeout_IsRespected(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"late"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:  "k",
+		BaseURL: srv.URL,
+		Timeout: 50 * time.Millisecond,
+		Headers: map[string]string{"X-Test": "1"},
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err == nil {
+		t.Fatalf("expected timeout error")
+	}
+	// Provider wraps the underlying timeout error; assert via Unwrap().
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context deadline exceeded via unwrap, got: %v", err)
+	}
+}
+
+// recordingTransport wraps http.DefaultTransport and records whether it was
+// invoked, to verify a custom ProviderConfig.Transport is actually used.
+type recordingTransport struct {
+	called bool
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.called = true
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOpenAIProvider_CustomTransport_IsUsed(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	rt := &recordingTransport{}
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:    "k",
+		BaseURL:   srv.URL,
+		Transport: rt,
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rt.called {
+		t.Error("expected the custom Transport to be used for the request")
+	}
+}
+
+func TestOpenAIProvider_CompressRequests_GzipsLargeBodies(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotEncoding string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:           "k",
+		BaseURL:          srv.URL,
+		CompressRequests: true,
+	})
+
+	longMessage := strings.Repeat("a very long prompt that pushes us past the compression threshold. ", 100)
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: longMessage}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotEncoding != "gzip" {
+		t.Fatalf("expected Content-Encoding: gzip, got %q", gotEncoding)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(gotBody))
+	if err != nil {
+		t.Fatalf("failed to read gzip body: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to decompress body: %v", err)
+	}
+	if !strings.Contains(string(decoded), longMessage) {
+		t.Errorf("expected decompressed body to contain the prompt, got %q", decoded)
+	}
+}
+
+func TestOpenAIProvider_CompressRequests_LeavesSmallBodiesUncompressed(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotEncoding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:           "k",
+		BaseURL:          srv.URL,
+		CompressRequests: true,
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotEncoding != "" {
+		t.Errorf("expected no Content-Encoding for a small body, got %q", gotEncoding)
+	}
+}
+
+func TestOpenAIProvider_OrganizationAndProject_AreSentAsHeaders(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotOrg, gotProject string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrg = r.Header.Get("OpenAI-Organization")
+		gotProject = r.Header.Get("OpenAI-Project")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:       "k",
+		BaseURL:      srv.URL,
+		Organization: "org-123",
+		Project:      "proj-456",
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotOrg != "org-123" {
+		t.Fatalf("expected OpenAI-Organization header, got %q", gotOrg)
+	}
+	if gotProject != "proj-456" {
+		t.Fatalf("expected OpenAI-Project header, got %q", gotProject)
+	}
+}
+
+func TestOpenAIProvider_OrganizationAndProject_OmittedWhenUnset(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotOrg, gotProject string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOrg = r.Header.Get("OpenAI-Organization")
+		gotProject = r.Header.Get("OpenAI-Project")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{
+		APIKey:  "k",
+		BaseURL: srv.URL,
+	})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT4o),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotOrg != "" {
+		t.Errorf("expected no OpenAI-Organization header, got %q", gotOrg)
+	}
+	if gotProject != "" {
+		t.Errorf("expected no OpenAI-Project header, got %q", gotProject)
+	}
+}
+
+func TestOpenAIProvider_ListModels_ParsesResponse(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/models" {
+			t.Fatalf("expected /models, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"data": [
+				{"id": "gpt-4o", "owned_by": "openai", "created": 1715367049},
+				{"id": "gpt-4o-mini", "owned_by": "openai", "created": 1721172741}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	models, err := p.ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+	if models[0].ID != "gpt-4o" || models[0].Owner != "openai" || models[0].Created != 1715367049 {
+		t.Errorf("unexpected first model: %#v", models[0])
+	}
+}
+
+func TestOpenAIProvider_ImplementsModelLister(t *testing.T) {
+	var p Provider = NewOpenAIProvider(ProviderConfig{APIKey: "k"})
+	if _, ok := p.(ModelLister); !ok {
+		t.Fatalf("expected OpenAIProvider to implement ModelLister")
+	}
+}
+
+func TestOpenAIProvider_ResponsesAPI_CapturesReasoningSummary(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp_1",
+			"output": [
+				{"type": "reasoning", "summary": [{"type": "summary_text", "text": "Considered two approaches."}]},
+				{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "ok"}]}
+			],
+			"usage": {"input_tokens": 1, "output_tokens": 2, "total_tokens": 3}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:        string(ModelGPT5),
+		Messages:     []Message{{Role: "user", Content: "hi"}},
+		BuiltinTools: []BuiltinTool{{Type: "web_search"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Reasoning != "Considered two approaches." {
+		t.Fatalf("expected reasoning summary, got %q", resp.Reasoning)
+	}
+}
+
+func TestOpenAIProvider_ResponsesAPI_SendsInputItems(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp_1",
+			"output": [{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "ok"}]}],
+			"usage": {"input_tokens": 1, "output_tokens": 2, "total_tokens": 3}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:        string(ModelGPT5),
+		Messages:     []Message{{Role: "user", Content: "what's the weather in Paris?"}},
+		BuiltinTools: []BuiltinTool{{Type: "web_search"}},
+		InputItems: []any{
+			FunctionCallOutput{CallID: "call_1", Output: "sunny"}.InputItem(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	input, ok := gotBody["input"].([]any)
+	if !ok {
+		t.Fatalf("expected input to be an array, got %T", gotBody["input"])
+	}
+	if len(input) != 2 {
+		t.Fatalf("expected 2 input items (message + function_call_output), got %d", len(input))
+	}
+	last, ok := input[1].(map[string]any)
+	if !ok || last["type"] != "function_call_output" || last["call_id"] != "call_1" || last["output"] != "sunny" {
+		t.Errorf("expected function_call_output item, got %#v", last)
+	}
+}
+
+func TestOpenAIProvider_ResponsesAPI_ParsesMCPApprovalRequest(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "resp_1",
+			"output": [
+				{
+					"type": "mcp_approval_request",
+					"id": "mcpr_1",
+					"server_label": "dice",
+					"name": "roll",
+					"arguments": "{\"sides\":6}"
+				}
+			],
+			"usage": {"input_tokens": 1, "output_tokens": 2, "total_tokens": 3}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:        string(ModelGPT5),
+		Messages:     []Message{{Role: "user", Content: "roll a die"}},
+		BuiltinTools: []BuiltinTool{{Type: "mcp"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ResponsesOutput == nil || len(resp.ResponsesOutput.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %+v", resp.ResponsesOutput)
+	}
+	tc := resp.ResponsesOutput.ToolCalls[0]
+	if tc.Type != "mcp_approval_request" || tc.CallID != "mcpr_1" || tc.ServerLabel != "dice" || tc.Name != "roll" {
+		t.Errorf("unexpected mcp_approval_request tool call: %+v", tc)
+	}
+}
+
+func TestAnthropicProvider_Send_CapturesThinkingBlock(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "thinking", "thinking": "Let me work through this step by step."},
+				{"type": "text", "text": "ok"}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 1, "output_tokens": 2}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelClaudeOpus),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Thinking: ThinkingHigh,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Reasoning != "Let me work through this step by step." {
+		t.Fatalf("expected thinking block captured as reasoning, got %q", resp.Reasoning)
+	}
+	if resp.Content != "ok" {
+		t.Fatalf("expected content %q, got %q", "ok", resp.Content)
+	}
+}
+
+func TestOpenAIProvider_Send_ThinkingMinimal_MapsToReasoningEffort(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &gotBody)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.Send(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT51),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Thinking: ThinkingMinimal,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotBody["reasoning_effort"] != "minimal" {
+		t.Fatalf("expected reasoning_effort=minimal, got %#v", gotBody["reasoning_effort"])
+	}
+}
+
+func TestOpenAIProvider_Embed_DecodesBase64Encoding(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	want := []float32{0.25, -0.5, 1.5}
+	buf := make([]byte, 4*len(want))
+	for i, f := range want {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf)
+
+	var gotFormat string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &body)
+		gotFormat, _ = body["encoding_format"].(string)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"embedding":"` + encoded + `","index":0}],"model":"text-embedding-3-small","usage":{"prompt_tokens":1,"total_tokens":1}}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.Embed(context.Background(), &EmbeddingRequest{
+		Model:          "text-embedding-3-small",
+		Input:          []string{"hello"},
+		EncodingFormat: "base64",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotFormat != "base64" {
+		t.Fatalf("expected encoding_format=base64 in request, got %q", gotFormat)
+	}
+
+	if len(resp.Embeddings) != 1 || len(resp.Embeddings[0]) != len(want) {
+		t.Fatalf("unexpected embeddings: %#v", resp.Embeddings)
+	}
+	for i, f := range want {
+		if math.Abs(resp.Embeddings[0][i]-float64(f)) > 1e-6 {
+			t.Errorf("index %d: expected %v, got %v", i, f, resp.Embeddings[0][i])
+		}
+	}
+}
+
+func TestOpenAIProvider_TextToSpeech_RejectsInvalidFormat(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: "http://unused.invalid"})
+
+	_, err := p.TextToSpeech(context.Background(), &TTSRequest{
+		Model:  string(TTSTTS1),
+		Input:  "hello",
+		Voice:  string(VoiceAlloy),
+		Format: "wma",
+	})
+
+	var formatErr *InvalidAudioFormatError
+	if !errors.As(err, &formatErr) {
+		t.Fatalf("expected *InvalidAudioFormatError, got %v (%T)", err, err)
+	}
+}
+
+func TestOpenAIProvider_TextToSpeech_DefaultsContentTypeFromFormat(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Explicitly empty Content-Type, bypassing Go's automatic sniffing,
+		// to exercise the fallback.
+		w.Header().Set("Content-Type", "")
+		_, _ = w.Write([]byte("fake-audio-bytes"))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	resp, err := p.TextToSpeech(context.Background(), &TTSRequest{
+		Model:  string(TTSTTS1),
+		Input:  "hello",
+		Voice:  string(VoiceAlloy),
+		Format: string(AudioFormatFLAC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.ContentType != "audio/flac" {
+		t.Fatalf("expected defaulted ContentType audio/flac, got %q", resp.ContentType)
+	}
+}
+
+func TestOpenAIProvider_EditImage_DefaultsToGPTImageModel(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotModel string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/edits" {
+			t.Fatalf("expected /images/edits, got %s", r.URL.Path)
+		}
+		_ = r.ParseMultipartForm(10 << 20)
+		gotModel = r.FormValue("model")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"b64_json":"ZmFrZQ=="}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.EditImage(context.Background(), &ImageEditRequest{
+		Image:  []byte("fake-png"),
+		Prompt: "add a hat",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotModel != "gpt-image-1" {
+		t.Fatalf("expected default model gpt-image-1, got %q", gotModel)
+	}
+}
+
+func TestOpenAIProvider_ImageVariation_DefaultsToDallE2NotEditsModel(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	var gotModel string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/images/variations" {
+			t.Fatalf("expected /images/variations, got %s", r.URL.Path)
+		}
+		_ = r.ParseMultipartForm(10 << 20)
+		gotModel = r.FormValue("model")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"b64_json":"ZmFrZQ=="}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+
+	_, err := p.ImageVariation(context.Background(), &ImageVariationRequest{
+		Image: []byte("fake-png"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotModel != "dall-e-2" {
+		t.Fatalf("expected default model dall-e-2 (the only model /images/variations accepts), got %q", gotModel)
 	}
 }
@@ -29,10 +29,7 @@ func NewOllamaProvider(config ProviderConfig) *OllamaProvider {
 		config.BaseURL = ollamaDefaultURL
 	}
 	// Ollama doesn't need an API key by default
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
-	}
+	client := buildHTTPClient(config)
 	return &OllamaProvider{config: config, httpClient: client}
 }
 
@@ -52,6 +49,13 @@ func (p *OllamaProvider) Capabilities() ProviderCapabilities {
 	}
 }
 
+// CloseIdleConnections closes any idle connections on the underlying HTTP
+// transport, releasing them back to the OS instead of leaving them open
+// until they time out on their own. Client.Close calls this.
+func (p *OllamaProvider) CloseIdleConnections() {
+	p.httpClient.CloseIdleConnections()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Send
 // ═══════════════════════════════════════════════════════════════════════════
@@ -65,12 +69,17 @@ func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/chat", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/chat", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s (model: %s)\n", colorDim("→"), p.Name(), "/api/chat", req.Model)
@@ -91,7 +100,7 @@ func (p *OllamaProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponse(respBody)
+	return p.parseResponse(respBody, req.CaptureRaw)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -108,12 +117,17 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/chat", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/api/chat", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s (stream, model: %s)\n", colorDim("→"), p.Name(), "/api/chat", req.Model)
@@ -144,12 +158,15 @@ func (p *OllamaProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 	reader := bufio.NewReader(resp.Body)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		line, err := readWithContext(ctx, resp.Body, func() ([]byte, error) { return reader.ReadBytes('\n') })
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
+			if ctx.Err() != nil {
+				return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
+			}
+			return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
 		}
 
 		var chunk struct {
@@ -292,7 +309,12 @@ func (p *OllamaProvider) buildRequest(req *ProviderRequest) *ollamaRequest {
 	return ollamaReq
 }
 
-func (p *OllamaProvider) setHeaders(req *http.Request) {
+// BuildRawRequest implements RequestPreviewer.
+func (p *OllamaProvider) BuildRawRequest(req *ProviderRequest) any {
+	return p.buildRequest(req)
+}
+
+func (p *OllamaProvider) setHeaders(req *http.Request, extra map[string]string) {
 	req.Header.Set("Content-Type", "application/json")
 
 	// API key if set (some Ollama deployments use auth)
@@ -303,9 +325,10 @@ func (p *OllamaProvider) setHeaders(req *http.Request) {
 	for k, v := range p.config.Headers {
 		req.Header.Set(k, v)
 	}
+	setExtraHeaders(req, extra)
 }
 
-func (p *OllamaProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+func (p *OllamaProvider) parseResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
 	var result struct {
 		Model   string `json:"model"`
 		Message struct {
@@ -361,5 +384,6 @@ func (p *OllamaProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 		PromptTokens:     result.PromptEvalCount,
 		CompletionTokens: result.EvalCount,
 		TotalTokens:      result.PromptEvalCount + result.EvalCount,
+		Raw:              captureRawIfRequested(body, captureRaw),
 	}, nil
 }
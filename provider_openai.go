@@ -1,15 +1,19 @@
 package ai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -32,10 +36,13 @@ func NewOpenAIProvider(config ProviderConfig) *OpenAIProvider {
 	if config.APIKey == "" {
 		config.APIKey = os.Getenv("OPENAI_API_KEY")
 	}
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
+	if config.Organization == "" {
+		config.Organization = os.Getenv("OPENAI_ORG_ID")
 	}
+	if config.Project == "" {
+		config.Project = os.Getenv("OPENAI_PROJECT_ID")
+	}
+	client := buildHTTPClient(config)
 	return &OpenAIProvider{config: config, httpClient: client}
 }
 
@@ -68,6 +75,13 @@ func (p *OpenAIProvider) Capabilities() ProviderCapabilities {
 	}
 }
 
+// CloseIdleConnections closes any idle connections on the underlying HTTP
+// transport, releasing them back to the OS instead of leaving them open
+// until they time out on their own. Client.Close calls this.
+func (p *OpenAIProvider) CloseIdleConnections() {
+	p.httpClient.CloseIdleConnections()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Send
 // ═══════════════════════════════════════════════════════════════════════════
@@ -93,12 +107,17 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/chat/completions")
@@ -115,7 +134,14 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponse(respBody)
+	providerResp, parseErr := p.parseResponse(respBody, req.CaptureRaw)
+	if rl := parseRateLimitHeaders(resp.Header); rl != nil {
+		if providerResp != nil {
+			providerResp.RateLimit = rl
+		}
+		invokeRateLimitInfo(p.Name(), *rl)
+	}
+	return providerResp, parseErr
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -124,6 +150,23 @@ func (p *OpenAIProvider) Send(ctx context.Context, req *ProviderRequest) (*Provi
 
 // SendStream executes a streaming request and invokes callback for each chunk.
 func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	return p.sendStream(ctx, req, callback, nil)
+}
+
+// SendStreamWithToolDeltas behaves like SendStream, but additionally invokes
+// onToolDelta with each fragment of a tool call's name and arguments as
+// OpenAI streams them in, before the call is complete. Implements
+// ToolCallStreamer, used by Builder.StreamWithToolDeltas for a live
+// "the assistant is looking up X..." indicator.
+func (p *OpenAIProvider) SendStreamWithToolDeltas(ctx context.Context, req *ProviderRequest, callback StreamCallback, onToolDelta ToolCallDeltaCallback) (*ProviderResponse, error) {
+	return p.sendStream(ctx, req, callback, onToolDelta)
+}
+
+// sendStream is the shared chat-completions streaming implementation behind
+// SendStream and SendStreamWithToolDeltas. onToolDelta may be nil, in which
+// case tool call fragments are still assembled into the returned
+// ProviderResponse's ToolCalls but no callback is invoked per-fragment.
+func (p *OpenAIProvider) sendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback, onToolDelta ToolCallDeltaCallback) (*ProviderResponse, error) {
 	if p.config.APIKey == "" {
 		return nil, &ProviderError{
 			Provider: p.Name(),
@@ -131,6 +174,11 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		}
 	}
 
+	// Use the Responses API when built-in tools are present
+	if len(req.BuiltinTools) > 0 {
+		return p.sendResponsesStream(ctx, req, callback)
+	}
+
 	oaiReq := p.buildRequest(req)
 	oaiReq.Stream = true
 
@@ -139,12 +187,17 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/chat/completions")
@@ -166,59 +219,136 @@ func (p *OpenAIProvider) SendStream(ctx context.Context, req *ProviderRequest, c
 	}
 
 	var fullContent strings.Builder
-	reader := bufio.NewReader(resp.Body)
+	var finishReason string
+	var calls []ToolCall
+	sse := newSSEReader(resp.Body)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		data, err := readWithContext(ctx, resp.Body, func() (string, error) { return sse.Next(p.Name()) })
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
-		}
-
-		line = bytes.TrimSpace(line)
-		if !bytes.HasPrefix(line, []byte("data: ")) {
-			continue
+			if ctx.Err() != nil {
+				return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
+			}
+			return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
 		}
 
-		data := bytes.TrimPrefix(line, []byte("data: "))
-		if string(data) == "[DONE]" {
+		if data == "[DONE]" {
 			break
 		}
 
 		var chunk struct {
 			Choices []struct {
 				Delta struct {
-					Content string `json:"content"`
+					Content   string `json:"content"`
+					ToolCalls []struct {
+						Index    int    `json:"index"`
+						ID       string `json:"id"`
+						Function struct {
+							Name      string `json:"name"`
+							Arguments string `json:"arguments"`
+						} `json:"function"`
+					} `json:"tool_calls"`
 				} `json:"delta"`
+				FinishReason string `json:"finish_reason"`
 			} `json:"choices"`
+			Error *struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+			} `json:"error,omitempty"`
 		}
 
-		if err := json.Unmarshal(data, &chunk); err != nil {
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			if Debug {
+				fmt.Printf("%s [%s] skipping unparseable stream event: %s\n", colorYellow("⚠"), p.Name(), data)
+			}
 			continue
 		}
 
+		if chunk.Error != nil {
+			return partialResponse(fullContent.String()), &ProviderError{
+				Provider: p.Name(),
+				Code:     classifyErrorCode(chunk.Error.Code, chunk.Error.Message),
+				Message:  chunk.Error.Message,
+			}
+		}
+
 		if len(chunk.Choices) > 0 {
 			content := chunk.Choices[0].Delta.Content
 			fullContent.WriteString(content)
 			callback(content)
+
+			for _, tc := range chunk.Choices[0].Delta.ToolCalls {
+				for len(calls) <= tc.Index {
+					calls = append(calls, ToolCall{Type: "function"})
+				}
+				if tc.ID != "" {
+					calls[tc.Index].ID = tc.ID
+				}
+				if tc.Function.Name != "" {
+					calls[tc.Index].Function.Name = tc.Function.Name
+				}
+				calls[tc.Index].Function.Arguments += tc.Function.Arguments
+
+				if onToolDelta != nil {
+					onToolDelta(ToolCallDelta{
+						Index:          tc.Index,
+						ID:             tc.ID,
+						Name:           tc.Function.Name,
+						ArgumentsDelta: tc.Function.Arguments,
+					})
+				}
+			}
+
+			if chunk.Choices[0].FinishReason != "" {
+				finishReason = chunk.Choices[0].FinishReason
+			}
 		}
 	}
 
 	completionTokens := len(fullContent.String()) / 4
 
-	return &ProviderResponse{
+	streamResp := &ProviderResponse{
 		Content:          fullContent.String(),
+		ToolCalls:        calls,
 		CompletionTokens: completionTokens,
 		TotalTokens:      completionTokens,
-	}, nil
+		FinishReason:     finishReason,
+	}
+	if rl := parseRateLimitHeaders(resp.Header); rl != nil {
+		streamResp.RateLimit = rl
+		invokeRateLimitInfo(p.Name(), *rl)
+	}
+	return streamResp, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
 // Internal helpers
 // ═══════════════════════════════════════════════════════════════════════════
 
+// parseRateLimitHeaders parses OpenAI's x-ratelimit-* response headers into
+// a RateLimitInfo, returning nil if neither remaining-requests nor
+// remaining-tokens was reported (e.g. an older proxy in front of the API).
+func parseRateLimitHeaders(h http.Header) *RateLimitInfo {
+	remReq := h.Get("x-ratelimit-remaining-requests")
+	remTok := h.Get("x-ratelimit-remaining-tokens")
+	if remReq == "" && remTok == "" {
+		return nil
+	}
+
+	info := &RateLimitInfo{}
+	info.LimitRequests, _ = strconv.Atoi(h.Get("x-ratelimit-limit-requests"))
+	info.LimitTokens, _ = strconv.Atoi(h.Get("x-ratelimit-limit-tokens"))
+	info.RemainingRequests, _ = strconv.Atoi(remReq)
+	info.RemainingTokens, _ = strconv.Atoi(remTok)
+	info.ResetRequests, _ = time.ParseDuration(h.Get("x-ratelimit-reset-requests"))
+	info.ResetTokens, _ = time.ParseDuration(h.Get("x-ratelimit-reset-tokens"))
+	return info
+}
+
 type openAIRequest struct {
 	Model          string          `json:"model"`
 	Messages       []Message       `json:"messages"`
@@ -229,6 +359,24 @@ type openAIRequest struct {
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 	// OpenAI uses "reasoning_effort" for o1 models
 	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+	// SafetyIdentifier helps OpenAI detect abuse and apply per-user rate
+	// limits (formerly the "user" field). Set via Builder.EndUser.
+	SafetyIdentifier string `json:"safety_identifier,omitempty"`
+	// Text configures gpt-5.1+ answer verbosity, independent of max_tokens.
+	Text *textCfg `json:"text,omitempty"`
+	// ServiceTier requests "auto", "default", "flex", or "priority"
+	// processing, set via Builder.ServiceTier.
+	ServiceTier string `json:"service_tier,omitempty"`
+	// Metadata carries arbitrary key/value tags echoed back in OpenAI's
+	// dashboard and webhooks, set via Builder.Metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// textCfg controls gpt-5.1+'s text.verbosity setting ("low", "medium",
+// "high"), set via Builder.Verbosity. Shared between the chat completions
+// and Responses API request shapes, since both use the same field name.
+type textCfg struct {
+	Verbosity string `json:"verbosity,omitempty"`
 }
 
 func (p *OpenAIProvider) buildRequest(req *ProviderRequest) *openAIRequest {
@@ -237,13 +385,23 @@ func (p *OpenAIProvider) buildRequest(req *ProviderRequest) *openAIRequest {
 		Messages: req.Messages,
 	}
 
-	if req.Temperature != nil {
-		oaiReq.Temperature = req.Temperature
+	// Reasoning models (o1, o3, gpt-5, ...) reject "temperature" outright and
+	// return a 400 if it's present, so skip it for any model the capability
+	// registry marks as a thinking model.
+	if caps, ok := GetModelCapabilities(Model(req.Model)); !ok || !caps.Thinking {
+		if req.Temperature != nil {
+			oaiReq.Temperature = req.Temperature
+		}
+	} else if req.Temperature != nil && Debug {
+		fmt.Printf("openai: dropping unsupported temperature param for reasoning model %s\n", req.Model)
 	}
 
-	// OpenAI o1 models use reasoning_effort: low, medium, high
+	// OpenAI reasoning models use reasoning_effort: minimal, low, medium, high
+	// ("minimal" is gpt-5.1+ only, for the fastest reasoning path)
 	if req.Thinking != "" {
 		switch req.Thinking {
+		case ThinkingMinimal:
+			oaiReq.ReasoningEffort = "minimal"
 		case ThinkingLow:
 			oaiReq.ReasoningEffort = "low"
 		case ThinkingMedium:
@@ -256,25 +414,74 @@ func (p *OpenAIProvider) buildRequest(req *ProviderRequest) *openAIRequest {
 	if len(req.Tools) > 0 {
 		oaiReq.Tools = req.Tools
 		oaiReq.ToolChoice = "auto"
+		if name, ok := req.ToolChoice.(string); ok && name != "" {
+			oaiReq.ToolChoice = map[string]any{
+				"type":     "function",
+				"function": map[string]string{"name": name},
+			}
+		}
 	}
 
-	if req.JSONMode {
+	// Prefer schema-guaranteed structured output over plain JSON mode when a
+	// schema is set, since it lets the model enforce the shape instead of
+	// just being asked to produce JSON.
+	if req.Schema != nil {
+		oaiReq.ResponseFormat = &ResponseFormat{
+			Type: "json_schema",
+			JSONSchema: map[string]any{
+				"name":   "response",
+				"schema": resolveSchema(req.Schema),
+				"strict": true,
+			},
+		}
+	} else if req.JSONMode {
 		oaiReq.ResponseFormat = &ResponseFormat{Type: "json_object"}
 	}
 
+	if req.EndUser != "" {
+		oaiReq.SafetyIdentifier = req.EndUser
+	}
+
+	if req.Verbosity != "" {
+		oaiReq.Text = &textCfg{Verbosity: req.Verbosity}
+	}
+
+	oaiReq.ServiceTier = req.ServiceTier
+	oaiReq.Metadata = req.Metadata
+
 	return oaiReq
 }
 
-func (p *OpenAIProvider) setHeaders(req *http.Request) {
+// BuildRawRequest implements RequestPreviewer.
+func (p *OpenAIProvider) BuildRawRequest(req *ProviderRequest) any {
+	return p.buildRequest(req)
+}
+
+func (p *OpenAIProvider) setHeaders(req *http.Request, extra map[string]string) {
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	p.setAuthHeaders(req)
 
 	for k, v := range p.config.Headers {
 		req.Header.Set(k, v)
 	}
+	setExtraHeaders(req, extra)
+}
+
+// setAuthHeaders sets Authorization plus the optional OpenAI-Organization
+// and OpenAI-Project headers. Used directly by the multipart upload
+// endpoints, which set their own Content-Type and so don't go through
+// setHeaders.
+func (p *OpenAIProvider) setAuthHeaders(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	if p.config.Organization != "" {
+		req.Header.Set("OpenAI-Organization", p.config.Organization)
+	}
+	if p.config.Project != "" {
+		req.Header.Set("OpenAI-Project", p.config.Project)
+	}
 }
 
-func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+func (p *OpenAIProvider) parseResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
 	var result struct {
 		ID      string `json:"id"`
 		Choices []struct {
@@ -282,15 +489,26 @@ func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 				Role      string     `json:"role"`
 				Content   string     `json:"content"`
 				ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+				Audio     *struct {
+					Data       string `json:"data"`
+					Transcript string `json:"transcript"`
+				} `json:"audio,omitempty"`
 			} `json:"message"`
 			FinishReason string `json:"finish_reason"`
 		} `json:"choices"`
 		Usage struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
+			PromptTokens        int `json:"prompt_tokens"`
+			CompletionTokens    int `json:"completion_tokens"`
+			TotalTokens         int `json:"total_tokens"`
+			PromptTokensDetails struct {
+				CachedTokens int `json:"cached_tokens"`
+			} `json:"prompt_tokens_details"`
+			CompletionTokensDetails struct {
+				ReasoningTokens int `json:"reasoning_tokens"`
+			} `json:"completion_tokens_details"`
 		} `json:"usage"`
-		Error *struct {
+		ServiceTier string `json:"service_tier,omitempty"`
+		Error       *struct {
 			Message string `json:"message"`
 			Type    string `json:"type"`
 			Code    string `json:"code"`
@@ -307,7 +525,7 @@ func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	if result.Error != nil {
 		return nil, &ProviderError{
 			Provider: p.Name(),
-			Code:     result.Error.Code,
+			Code:     classifyErrorCode(result.Error.Code, result.Error.Message),
 			Message:  result.Error.Message,
 		}
 	}
@@ -320,13 +538,28 @@ func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 	}
 
 	choice := result.Choices[0]
+
+	var audioOutput *AudioOutput
+	if choice.Message.Audio != nil {
+		data, err := base64.StdEncoding.DecodeString(choice.Message.Audio.Data)
+		if err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("failed to decode audio output: %v", err)}
+		}
+		audioOutput = &AudioOutput{Data: data, Transcript: choice.Message.Audio.Transcript}
+	}
+
 	return &ProviderResponse{
 		Content:          choice.Message.Content,
 		ToolCalls:        choice.Message.ToolCalls,
 		PromptTokens:     result.Usage.PromptTokens,
 		CompletionTokens: result.Usage.CompletionTokens,
 		TotalTokens:      result.Usage.TotalTokens,
+		CachedTokens:     result.Usage.PromptTokensDetails.CachedTokens,
+		ReasoningTokens:  result.Usage.CompletionTokensDetails.ReasoningTokens,
 		FinishReason:     choice.FinishReason,
+		Raw:              captureRawIfRequested(body, captureRaw),
+		AudioOutput:      audioOutput,
+		ServiceTier:      result.ServiceTier,
 	}, nil
 }
 
@@ -336,36 +569,47 @@ func (p *OpenAIProvider) parseResponse(body []byte) (*ProviderResponse, error) {
 
 // responsesRequest is the request format for /v1/responses
 type responsesRequest struct {
-	Model        string        `json:"model"`
-	Input        any           `json:"input"` // string or []responsesInputItem
-	Instructions string        `json:"instructions,omitempty"`
-	Tools        []any         `json:"tools,omitempty"`
-	ToolChoice   string        `json:"tool_choice,omitempty"`
-	Reasoning    *reasoningCfg `json:"reasoning,omitempty"`
+	Model            string            `json:"model"`
+	Input            any               `json:"input"` // string or []responsesInputItem
+	Instructions     string            `json:"instructions,omitempty"`
+	Tools            []any             `json:"tools,omitempty"`
+	ToolChoice       any               `json:"tool_choice,omitempty"`
+	Reasoning        *reasoningCfg     `json:"reasoning,omitempty"`
+	Stream           bool              `json:"stream,omitempty"`
+	SafetyIdentifier string            `json:"safety_identifier,omitempty"`
+	Text             *textCfg          `json:"text,omitempty"`
+	Background       bool              `json:"background,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
 }
 
 type reasoningCfg struct {
-	Effort string `json:"effort,omitempty"` // "low", "medium", "high"
+	Effort  string `json:"effort,omitempty"`  // "low", "medium", "high"
+	Summary string `json:"summary,omitempty"` // "auto", "concise", "detailed"
 }
 
 // responsesInputItem for multi-turn conversations
 type responsesInputItem struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	Name    string `json:"name,omitempty"`
 }
 
-func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+// buildResponsesRequest converts a ProviderRequest into the /v1/responses
+// wire format, shared by sendResponses and StartBackground.
+// sendResponsesStream assembles its streaming request separately.
+func (p *OpenAIProvider) buildResponsesRequest(req *ProviderRequest) responsesRequest {
 	// Build input from messages
 	var input any
-	if len(req.Messages) == 1 && req.Messages[0].Role == "user" {
+	if len(req.InputItems) == 0 && len(req.Messages) == 1 && req.Messages[0].Role == "user" {
 		// Simple single message - use string input
 		if content, ok := req.Messages[0].Content.(string); ok {
 			input = content
 		}
 	}
 	if input == nil {
-		// Convert messages to input items
-		var items []responsesInputItem
+		// Convert messages to input items, followed by any pre-built items
+		// (e.g. FunctionCallOutput) posted back via Builder.InputItems.
+		items := make([]any, 0, len(req.Messages)+len(req.InputItems))
 		for _, msg := range req.Messages {
 			content := ""
 			if s, ok := msg.Content.(string); ok {
@@ -374,8 +618,10 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 			items = append(items, responsesInputItem{
 				Role:    msg.Role,
 				Content: content,
+				Name:    msg.Name,
 			})
 		}
+		items = append(items, req.InputItems...)
 		input = items
 	}
 
@@ -406,24 +652,52 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 		Instructions: instructions,
 		Tools:        tools,
 		ToolChoice:   "auto",
+		Background:   req.Background,
+	}
+	if name, ok := req.ToolChoice.(string); ok && name != "" {
+		respReq.ToolChoice = map[string]any{
+			"type": "function",
+			"name": name,
+		}
 	}
 
-	// Set reasoning effort if thinking is configured
-	if req.Thinking != "" {
-		respReq.Reasoning = &reasoningCfg{Effort: string(req.Thinking)}
+	// Set reasoning effort/summary if either is configured
+	if req.Thinking != "" || req.ReasoningSummary != "" {
+		respReq.Reasoning = &reasoningCfg{Effort: string(req.Thinking), Summary: req.ReasoningSummary}
+	}
+
+	if req.EndUser != "" {
+		respReq.SafetyIdentifier = req.EndUser
+	}
+
+	if req.Verbosity != "" {
+		respReq.Text = &textCfg{Verbosity: req.Verbosity}
 	}
 
+	respReq.Metadata = req.Metadata
+
+	return respReq
+}
+
+func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	respReq := p.buildResponsesRequest(req)
+
 	body, err := json.Marshal(respReq)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal responses request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/responses", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/responses", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/responses")
@@ -440,339 +714,1187 @@ func (p *OpenAIProvider) sendResponses(ctx context.Context, req *ProviderRequest
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponsesResponse(respBody)
+	return p.parseResponsesResponse(respBody, req.CaptureRaw)
 }
 
-// buildBuiltinTool converts BuiltinTool to the API format
-func (p *OpenAIProvider) buildBuiltinTool(bt BuiltinTool) map[string]any {
-	tool := map[string]any{
-		"type": bt.Type,
-	}
-
-	switch bt.Type {
-	case "web_search":
-		if bt.UserLocation != nil {
-			tool["user_location"] = bt.UserLocation
-		}
-		if bt.SearchFilter != nil {
-			tool["filters"] = bt.SearchFilter
-		}
-
-	case "file_search":
-		if len(bt.VectorStoreIDs) > 0 {
-			tool["vector_store_ids"] = bt.VectorStoreIDs
-		}
-		if bt.MaxNumResults > 0 {
-			tool["max_num_results"] = bt.MaxNumResults
-		}
-		if bt.FileFilter != nil {
-			tool["filters"] = bt.FileFilter
-		}
+// ═══════════════════════════════════════════════════════════════════════════
+// Background Responses (deep-research models, etc.)
+// ═══════════════════════════════════════════════════════════════════════════
 
-	case "code_interpreter":
-		if bt.Container != nil {
-			tool["container"] = bt.Container
-		}
+// StartBackground submits req as a background Responses API job and returns
+// its ID for later polling via PollResponse. It implements
+// BackgroundResponder, used by Builder.Background.
+func (p *OpenAIProvider) StartBackground(ctx context.Context, req *ProviderRequest) (string, error) {
+	if p.config.APIKey == "" {
+		return "", &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
 
-	case "mcp":
-		if bt.ServerLabel != "" {
-			tool["server_label"] = bt.ServerLabel
-		}
-		if bt.ServerURL != "" {
-			tool["server_url"] = bt.ServerURL
-		}
-		if bt.ServerDescription != "" {
-			tool["server_description"] = bt.ServerDescription
-		}
-		if bt.ConnectorID != "" {
-			tool["connector_id"] = bt.ConnectorID
-		}
-		if bt.Authorization != "" {
-			tool["authorization"] = bt.Authorization
-		}
-		if bt.RequireApproval != nil {
-			tool["require_approval"] = bt.RequireApproval
-		}
-		if len(bt.AllowedTools) > 0 {
-			tool["allowed_tools"] = bt.AllowedTools
-		}
+	respReq := p.buildResponsesRequest(req)
+	respReq.Background = true
 
-	case "image_generation":
-		if bt.ImageSize != "" {
-			tool["size"] = bt.ImageSize
-		}
-		if bt.ImageQuality != "" {
-			tool["quality"] = bt.ImageQuality
-		}
-		if bt.ImageFormat != "" {
-			tool["output_format"] = bt.ImageFormat
-		}
-		if bt.ImageCompression > 0 {
-			tool["compression"] = bt.ImageCompression
-		}
-		if bt.ImageBackground != "" {
-			tool["background"] = bt.ImageBackground
-		}
-		if bt.PartialImages > 0 {
-			tool["partial_images"] = bt.PartialImages
-		}
+	body, err := json.Marshal(respReq)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to marshal responses request", Err: err}
+	}
 
-	case "computer_use_preview":
-		if bt.DisplayWidth > 0 {
-			tool["display_width"] = bt.DisplayWidth
-		}
-		if bt.DisplayHeight > 0 {
-			tool["display_height"] = bt.DisplayHeight
-		}
-		if bt.Environment != "" {
-			tool["environment"] = bt.Environment
-		}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/responses", bytes.NewReader(body))
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+	p.setHeaders(httpReq, req.Headers)
 
-	case "shell":
-		// No additional configuration needed
+	if Debug {
+		fmt.Printf("%s [%s] POST %s (background)\n", colorDim("→"), p.Name(), "/responses")
+	}
 
-	case "apply_patch":
-		// No additional configuration needed
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
 	}
+	defer resp.Body.Close()
 
-	return tool
-}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
 
-// parseResponsesResponse parses the Responses API output
-func (p *OpenAIProvider) parseResponsesResponse(body []byte) (*ProviderResponse, error) {
 	var result struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-		Output []struct {
-			ID      string `json:"id"`
-			Type    string `json:"type"`
-			Status  string `json:"status,omitempty"`
-			CallID  string `json:"call_id,omitempty"`
-			Role    string `json:"role,omitempty"`
-			Content []struct {
-				Type        string `json:"type"`
-				Text        string `json:"text,omitempty"`
-				Annotations []struct {
-					Type       string `json:"type"`
-					URL        string `json:"url,omitempty"`
-					Title      string `json:"title,omitempty"`
-					FileID     string `json:"file_id,omitempty"`
-					Filename   string `json:"filename,omitempty"`
-					StartIndex int    `json:"start_index,omitempty"`
-					EndIndex   int    `json:"end_index,omitempty"`
-				} `json:"annotations,omitempty"`
-			} `json:"content,omitempty"`
-			// Tool call fields
-			ServerLabel string `json:"server_label,omitempty"`
-			Name        string `json:"name,omitempty"`
-			Arguments   string `json:"arguments,omitempty"`
-			OutputText  string `json:"output,omitempty"`
-			Error       string `json:"error,omitempty"`
-
-			// Image generation fields
-			RevisedPrompt string `json:"revised_prompt,omitempty"`
-			Result        string `json:"result,omitempty"` // base64 image
-
-			// Shared action field - used by both computer_call and shell_call with different structures
-			// We use json.RawMessage to handle the polymorphic nature
-			Action json.RawMessage `json:"action,omitempty"`
-
-			// Safety checks (computer use)
-			PendingSafetyChecks []struct {
-				ID      string `json:"id"`
-				Code    string `json:"code"`
-				Message string `json:"message"`
-			} `json:"pending_safety_checks,omitempty"`
-
-			// Apply patch fields
-			Operation *struct {
-				Type string `json:"type"`
-				Path string `json:"path"`
-				Diff string `json:"diff,omitempty"`
-			} `json:"operation,omitempty"`
-		} `json:"output"`
-		OutputText string `json:"output_text,omitempty"` // Convenience field
-		Usage      struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
-			TotalTokens  int `json:"total_tokens"`
-		} `json:"usage"`
+		ID    string `json:"id"`
 		Error *struct {
 			Message string `json:"message"`
 			Type    string `json:"type"`
 			Code    string `json:"code"`
 		} `json:"error,omitempty"`
 	}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Message:  fmt.Sprintf("parse error: %v\nBody: %s", err, string(body)),
-		}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("parse error: %v\nBody: %s", err, string(respBody))}
 	}
-
 	if result.Error != nil {
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     result.Error.Code,
-			Message:  result.Error.Message,
-		}
+		return "", &ProviderError{Provider: p.Name(), Code: classifyErrorCode(result.Error.Code, result.Error.Message), Message: result.Error.Message}
 	}
 
-	// Extract text content and build ResponsesOutput
-	var textContent string
-	var citations []Citation
-	var toolCalls []ResponsesToolCall
+	return result.ID, nil
+}
 
-	for _, item := range result.Output {
-		switch item.Type {
-		case "message":
-			for _, c := range item.Content {
-				if c.Type == "output_text" || c.Type == "text" {
-					textContent += c.Text
-					// Extract citations
-					for _, ann := range c.Annotations {
-						citations = append(citations, Citation{
-							Type:       ann.Type,
-							URL:        ann.URL,
-							Title:      ann.Title,
-							FileID:     ann.FileID,
-							Filename:   ann.Filename,
-							StartIndex: ann.StartIndex,
-							EndIndex:   ann.EndIndex,
-						})
-					}
-				}
-			}
+// PollResponse checks the status of a background job started with
+// StartBackground. resp is only populated once status is "completed".
+func (p *OpenAIProvider) PollResponse(ctx context.Context, id string) (*ProviderResponse, string, error) {
+	if p.config.APIKey == "" {
+		return nil, "", &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
 
-		case "web_search_call", "file_search_call", "mcp_call", "code_interpreter_call":
-			toolCalls = append(toolCalls, ResponsesToolCall{
-				ID:          item.ID,
-				Type:        item.Type,
-				Status:      item.Status,
-				CallID:      item.CallID,
-				ServerLabel: item.ServerLabel,
-				Name:        item.Name,
-				Arguments:   item.Arguments,
-				Output:      item.OutputText,
-				Error:       item.Error,
-			})
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.config.BaseURL+"/responses/"+id, nil)
+	if err != nil {
+		return nil, "", &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+	p.setAuthHeaders(httpReq)
 
-		case "image_generation_call":
-			toolCalls = append(toolCalls, ResponsesToolCall{
-				ID:            item.ID,
-				Type:          item.Type,
-				Status:        item.Status,
-				CallID:        item.CallID,
-				RevisedPrompt: item.RevisedPrompt,
-				ImageResult:   item.Result,
-			})
+	if Debug {
+		fmt.Printf("%s [%s] GET %s\n", colorDim("→"), p.Name(), "/responses/"+id)
+	}
 
-		case "computer_call":
-			tc := ResponsesToolCall{
-				ID:     item.ID,
-				Type:   item.Type,
-				Status: item.Status,
-				CallID: item.CallID,
-			}
-			if len(item.Action) > 0 {
-				var action ComputerAction
-				if err := json.Unmarshal(item.Action, &action); err == nil {
-					tc.Action = &action
-				}
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, "", &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+
+	var peek struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(respBody, &peek); err != nil {
+		return nil, "", &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("parse error: %v\nBody: %s", err, string(respBody))}
+	}
+
+	if peek.Status != "completed" {
+		return nil, peek.Status, nil
+	}
+
+	pr, err := p.parseResponsesResponse(respBody, false)
+	if err != nil {
+		return nil, peek.Status, err
+	}
+	return pr, peek.Status, nil
+}
+
+// sendResponsesStream is the streaming counterpart to sendResponses. It hits
+// /responses with stream: true and parses the resulting SSE event types,
+// driving callback for text deltas (response.output_text.delta) while
+// waiting for response.completed to build the final ResponsesOutput
+// (citations, tool calls, etc.) from the completed response snapshot it
+// carries.
+func (p *OpenAIProvider) sendResponsesStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	var input any
+	if len(req.InputItems) == 0 && len(req.Messages) == 1 && req.Messages[0].Role == "user" {
+		if content, ok := req.Messages[0].Content.(string); ok {
+			input = content
+		}
+	}
+	if input == nil {
+		items := make([]any, 0, len(req.Messages)+len(req.InputItems))
+		for _, msg := range req.Messages {
+			content := ""
+			if s, ok := msg.Content.(string); ok {
+				content = s
 			}
-			for _, sc := range item.PendingSafetyChecks {
-				tc.PendingSafetyChecks = append(tc.PendingSafetyChecks, SafetyCheck{
-					ID:      sc.ID,
-					Code:    sc.Code,
-					Message: sc.Message,
-				})
+			items = append(items, responsesInputItem{
+				Role:    msg.Role,
+				Content: content,
+				Name:    msg.Name,
+			})
+		}
+		items = append(items, req.InputItems...)
+		input = items
+	}
+
+	var tools []any
+	for _, bt := range req.BuiltinTools {
+		tools = append(tools, p.buildBuiltinTool(bt))
+	}
+	for _, ft := range req.Tools {
+		tools = append(tools, ft)
+	}
+
+	var instructions string
+	for _, msg := range req.Messages {
+		if msg.Role == "system" {
+			if s, ok := msg.Content.(string); ok {
+				instructions = s
 			}
-			toolCalls = append(toolCalls, tc)
+			break
+		}
+	}
 
-		case "shell_call":
-			tc := ResponsesToolCall{
-				ID:     item.ID,
-				Type:   item.Type,
-				Status: item.Status,
-				CallID: item.CallID,
+	respReq := responsesRequest{
+		Model:        resolveModel(ProviderOpenAI, Model(req.Model)),
+		Input:        input,
+		Instructions: instructions,
+		Tools:        tools,
+		ToolChoice:   "auto",
+		Stream:       true,
+	}
+	if name, ok := req.ToolChoice.(string); ok && name != "" {
+		respReq.ToolChoice = map[string]any{
+			"type": "function",
+			"name": name,
+		}
+	}
+
+	if req.Thinking != "" || req.ReasoningSummary != "" {
+		respReq.Reasoning = &reasoningCfg{Effort: string(req.Thinking), Summary: req.ReasoningSummary}
+	}
+
+	if req.EndUser != "" {
+		respReq.SafetyIdentifier = req.EndUser
+	}
+
+	if req.Verbosity != "" {
+		respReq.Text = &textCfg{Verbosity: req.Verbosity}
+	}
+
+	respReq.Metadata = req.Metadata
+
+	body, err := json.Marshal(respReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal responses request", Err: err}
+	}
+
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/responses", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	if Debug {
+		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/responses")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     fmt.Sprintf("%d", resp.StatusCode),
+			Message:  string(errBody),
+		}
+	}
+
+	var fullText strings.Builder
+	var finalResp *ProviderResponse
+	sse := newSSEReader(resp.Body)
+
+	for {
+		data, err := readWithContext(ctx, resp.Body, func() (string, error) { return sse.Next(p.Name()) })
+		if err != nil {
+			if err == io.EOF {
+				break
 			}
-			if len(item.Action) > 0 {
-				var action ShellAction
-				if err := json.Unmarshal(item.Action, &action); err == nil {
-					tc.ShellAction = &action
-				}
+			if ctx.Err() != nil {
+				return partialResponse(fullText.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
 			}
-			toolCalls = append(toolCalls, tc)
+			return partialResponse(fullText.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
+		}
 
-		case "apply_patch_call":
-			tc := ResponsesToolCall{
-				ID:     item.ID,
-				Type:   item.Type,
-				Status: item.Status,
-				CallID: item.CallID,
+		if data == "[DONE]" {
+			break
+		}
+
+		var evt struct {
+			Type     string          `json:"type"`
+			Delta    string          `json:"delta,omitempty"`
+			Response json.RawMessage `json:"response,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(data), &evt); err != nil {
+			if Debug {
+				fmt.Printf("%s [%s] skipping unparseable stream event: %s\n", colorYellow("⚠"), p.Name(), data)
 			}
-			if item.Operation != nil {
-				tc.PatchOperation = &PatchOperation{
-					Type: item.Operation.Type,
-					Path: item.Operation.Path,
-					Diff: item.Operation.Diff,
+			continue
+		}
+
+		switch evt.Type {
+		case "response.output_text.delta":
+			fullText.WriteString(evt.Delta)
+			callback(evt.Delta)
+
+		case "response.completed":
+			if len(evt.Response) > 0 {
+				if parsed, err := p.parseResponsesResponse(evt.Response, req.CaptureRaw); err == nil {
+					finalResp = parsed
 				}
 			}
-			toolCalls = append(toolCalls, tc)
 		}
+		// Other event types (response.web_search_call.*, response.file_search_call.*,
+		// response.code_interpreter_call.*, etc.) are progress notifications; the
+		// completed tool-call list is reconstructed from response.completed above.
+	}
+
+	if finalResp != nil {
+		return finalResp, nil
+	}
+
+	// No response.completed event was observed (e.g. connection cut short);
+	// fall back to whatever text was streamed.
+	return &ProviderResponse{Content: fullText.String()}, nil
+}
+
+// buildBuiltinTool converts BuiltinTool to the API format
+func (p *OpenAIProvider) buildBuiltinTool(bt BuiltinTool) map[string]any {
+	tool := map[string]any{
+		"type": bt.Type,
+	}
+
+	switch bt.Type {
+	case "web_search":
+		if bt.UserLocation != nil {
+			tool["user_location"] = bt.UserLocation
+		}
+		if bt.SearchFilter != nil {
+			tool["filters"] = bt.SearchFilter
+		}
+
+	case "file_search":
+		if len(bt.VectorStoreIDs) > 0 {
+			tool["vector_store_ids"] = bt.VectorStoreIDs
+		}
+		if bt.MaxNumResults > 0 {
+			tool["max_num_results"] = bt.MaxNumResults
+		}
+		if bt.FileFilter != nil {
+			tool["filters"] = bt.FileFilter
+		}
+
+	case "code_interpreter":
+		if bt.Container != nil {
+			tool["container"] = bt.Container
+		}
+
+	case "mcp":
+		if bt.ServerLabel != "" {
+			tool["server_label"] = bt.ServerLabel
+		}
+		if bt.ServerURL != "" {
+			tool["server_url"] = bt.ServerURL
+		}
+		if bt.ServerDescription != "" {
+			tool["server_description"] = bt.ServerDescription
+		}
+		if bt.ConnectorID != "" {
+			tool["connector_id"] = bt.ConnectorID
+		}
+		if bt.Authorization != "" {
+			tool["authorization"] = bt.Authorization
+		}
+		if bt.RequireApproval != nil {
+			tool["require_approval"] = bt.RequireApproval
+		}
+		if len(bt.AllowedTools) > 0 {
+			tool["allowed_tools"] = bt.AllowedTools
+		}
+
+	case "image_generation":
+		if bt.ImageSize != "" {
+			tool["size"] = bt.ImageSize
+		}
+		if bt.ImageQuality != "" {
+			tool["quality"] = bt.ImageQuality
+		}
+		if bt.ImageFormat != "" {
+			tool["output_format"] = bt.ImageFormat
+		}
+		if bt.ImageCompression > 0 {
+			tool["compression"] = bt.ImageCompression
+		}
+		if bt.ImageBackground != "" {
+			tool["background"] = bt.ImageBackground
+		}
+		if bt.PartialImages > 0 {
+			tool["partial_images"] = bt.PartialImages
+		}
+
+	case "computer_use_preview":
+		if bt.DisplayWidth > 0 {
+			tool["display_width"] = bt.DisplayWidth
+		}
+		if bt.DisplayHeight > 0 {
+			tool["display_height"] = bt.DisplayHeight
+		}
+		if bt.Environment != "" {
+			tool["environment"] = bt.Environment
+		}
+
+	case "shell":
+		// No additional configuration needed
+
+	case "apply_patch":
+		// No additional configuration needed
+	}
+
+	return tool
+}
+
+// parseResponsesResponse parses the Responses API output
+func (p *OpenAIProvider) parseResponsesResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
+	var result struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Output []struct {
+			ID      string `json:"id"`
+			Type    string `json:"type"`
+			Status  string `json:"status,omitempty"`
+			CallID  string `json:"call_id,omitempty"`
+			Role    string `json:"role,omitempty"`
+			Content []struct {
+				Type        string `json:"type"`
+				Text        string `json:"text,omitempty"`
+				Annotations []struct {
+					Type       string `json:"type"`
+					URL        string `json:"url,omitempty"`
+					Title      string `json:"title,omitempty"`
+					FileID     string `json:"file_id,omitempty"`
+					Filename   string `json:"filename,omitempty"`
+					StartIndex int    `json:"start_index,omitempty"`
+					EndIndex   int    `json:"end_index,omitempty"`
+				} `json:"annotations,omitempty"`
+			} `json:"content,omitempty"`
+			// Tool call fields
+			ServerLabel string `json:"server_label,omitempty"`
+			Name        string `json:"name,omitempty"`
+			Arguments   string `json:"arguments,omitempty"`
+			OutputText  string `json:"output,omitempty"`
+			Error       string `json:"error,omitempty"`
+
+			// Image generation fields
+			RevisedPrompt string `json:"revised_prompt,omitempty"`
+			Result        string `json:"result,omitempty"` // base64 image
+
+			// Shared action field - used by both computer_call and shell_call with different structures
+			// We use json.RawMessage to handle the polymorphic nature
+			Action json.RawMessage `json:"action,omitempty"`
+
+			// Safety checks (computer use)
+			PendingSafetyChecks []struct {
+				ID      string `json:"id"`
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"pending_safety_checks,omitempty"`
+
+			// Apply patch fields
+			Operation *struct {
+				Type string `json:"type"`
+				Path string `json:"path"`
+				Diff string `json:"diff,omitempty"`
+			} `json:"operation,omitempty"`
+
+			// Reasoning summary (reasoning models, when summaries are enabled)
+			Summary []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"summary,omitempty"`
+		} `json:"output"`
+		OutputText string `json:"output_text,omitempty"` // Convenience field
+		Usage      struct {
+			InputTokens        int `json:"input_tokens"`
+			OutputTokens       int `json:"output_tokens"`
+			TotalTokens        int `json:"total_tokens"`
+			InputTokensDetails struct {
+				CachedTokens int `json:"cached_tokens"`
+			} `json:"input_tokens_details"`
+			OutputTokensDetails struct {
+				ReasoningTokens int `json:"reasoning_tokens"`
+			} `json:"output_tokens_details"`
+		} `json:"usage"`
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error,omitempty"`
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Message:  fmt.Sprintf("parse error: %v\nBody: %s", err, string(body)),
+		}
+	}
+
+	if result.Error != nil {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     classifyErrorCode(result.Error.Code, result.Error.Message),
+			Message:  result.Error.Message,
+		}
+	}
+
+	// Extract text content and build ResponsesOutput
+	var textContent string
+	var citations []Citation
+	var toolCalls []ResponsesToolCall
+	var reasoning strings.Builder
+
+	for _, item := range result.Output {
+		switch item.Type {
+		case "reasoning":
+			for _, s := range item.Summary {
+				if reasoning.Len() > 0 {
+					reasoning.WriteString("\n")
+				}
+				reasoning.WriteString(s.Text)
+			}
+
+		case "message":
+			for _, c := range item.Content {
+				if c.Type == "output_text" || c.Type == "text" {
+					textContent += c.Text
+					// Extract citations
+					for _, ann := range c.Annotations {
+						citations = append(citations, Citation{
+							Type:       ann.Type,
+							URL:        ann.URL,
+							Title:      ann.Title,
+							FileID:     ann.FileID,
+							Filename:   ann.Filename,
+							StartIndex: ann.StartIndex,
+							EndIndex:   ann.EndIndex,
+						})
+					}
+				}
+			}
+
+		case "web_search_call", "file_search_call", "mcp_call", "code_interpreter_call":
+			toolCalls = append(toolCalls, ResponsesToolCall{
+				ID:          item.ID,
+				Type:        item.Type,
+				Status:      item.Status,
+				CallID:      item.CallID,
+				ServerLabel: item.ServerLabel,
+				Name:        item.Name,
+				Arguments:   item.Arguments,
+				Output:      item.OutputText,
+				Error:       item.Error,
+			})
+
+		case "mcp_approval_request":
+			// CallID carries the item's own ID here (not a separate call_id
+			// field on the wire), since that's what MCPApprovalResponse's
+			// ApprovalRequestID must echo back to approve or deny the call.
+			toolCalls = append(toolCalls, ResponsesToolCall{
+				ID:          item.ID,
+				Type:        item.Type,
+				Status:      item.Status,
+				CallID:      item.ID,
+				ServerLabel: item.ServerLabel,
+				Name:        item.Name,
+				Arguments:   item.Arguments,
+			})
+
+		case "image_generation_call":
+			toolCalls = append(toolCalls, ResponsesToolCall{
+				ID:            item.ID,
+				Type:          item.Type,
+				Status:        item.Status,
+				CallID:        item.CallID,
+				RevisedPrompt: item.RevisedPrompt,
+				ImageResult:   item.Result,
+			})
+
+		case "computer_call":
+			tc := ResponsesToolCall{
+				ID:     item.ID,
+				Type:   item.Type,
+				Status: item.Status,
+				CallID: item.CallID,
+			}
+			if len(item.Action) > 0 {
+				var action ComputerAction
+				if err := json.Unmarshal(item.Action, &action); err == nil {
+					tc.Action = &action
+				}
+			}
+			for _, sc := range item.PendingSafetyChecks {
+				tc.PendingSafetyChecks = append(tc.PendingSafetyChecks, SafetyCheck{
+					ID:      sc.ID,
+					Code:    sc.Code,
+					Message: sc.Message,
+				})
+			}
+			toolCalls = append(toolCalls, tc)
+
+		case "shell_call":
+			tc := ResponsesToolCall{
+				ID:     item.ID,
+				Type:   item.Type,
+				Status: item.Status,
+				CallID: item.CallID,
+			}
+			if len(item.Action) > 0 {
+				var action ShellAction
+				if err := json.Unmarshal(item.Action, &action); err == nil {
+					tc.ShellAction = &action
+				}
+			}
+			toolCalls = append(toolCalls, tc)
+
+		case "apply_patch_call":
+			tc := ResponsesToolCall{
+				ID:     item.ID,
+				Type:   item.Type,
+				Status: item.Status,
+				CallID: item.CallID,
+			}
+			if item.Operation != nil {
+				tc.PatchOperation = &PatchOperation{
+					Type: item.Operation.Type,
+					Path: item.Operation.Path,
+					Diff: item.Operation.Diff,
+				}
+			}
+			toolCalls = append(toolCalls, tc)
+		}
+	}
+
+	// Use output_text convenience field if available
+	if textContent == "" && result.OutputText != "" {
+		textContent = result.OutputText
+	}
+
+	return &ProviderResponse{
+		Content:          textContent,
+		PromptTokens:     result.Usage.InputTokens,
+		CompletionTokens: result.Usage.OutputTokens,
+		TotalTokens:      result.Usage.TotalTokens,
+		CachedTokens:     result.Usage.InputTokensDetails.CachedTokens,
+		ReasoningTokens:  result.Usage.OutputTokensDetails.ReasoningTokens,
+		ResponsesOutput: &ResponsesOutput{
+			Text:      textContent,
+			Citations: citations,
+			ToolCalls: toolCalls,
+		},
+		Reasoning: reasoning.String(),
+		Raw:       captureRawIfRequested(body, captureRaw),
+	}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Embeddings
+// ═══════════════════════════════════════════════════════════════════════════
+
+func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
+	if p.config.APIKey == "" {
+		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
+
+	oaiReq := struct {
+		Model          string   `json:"model"`
+		Input          []string `json:"input"`
+		Dimensions     int      `json:"dimensions,omitempty"`
+		EncodingFormat string   `json:"encoding_format,omitempty"`
+	}{
+		Model:          req.Model,
+		Input:          req.Input,
+		EncodingFormat: req.EncodingFormat,
+	}
+	if req.Dimensions > 0 {
+		oaiReq.Dimensions = req.Dimensions
+	}
+
+	body, err := json.Marshal(oaiReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+	}
+
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/embeddings", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setHeaders(httpReq, nil)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+
+	var result struct {
+		Data []struct {
+			Embedding json.RawMessage `json:"embedding"`
+			Index     int             `json:"index"`
+		} `json:"data"`
+		Model string `json:"model"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+			TotalTokens  int `json:"total_tokens"`
+		} `json:"usage"`
+		Error *struct {
+			Message string `json:"message"`
+			Code    string `json:"code"`
+		} `json:"error,omitempty"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "parse error", Err: err}
+	}
+
+	if result.Error != nil {
+		return nil, &ProviderError{Provider: p.Name(), Code: result.Error.Code, Message: result.Error.Message}
+	}
+
+	embeddings := make([][]float64, len(result.Data))
+	var dims int
+	for _, d := range result.Data {
+		vec, err := decodeEmbedding(d.Embedding, req.EncodingFormat)
+		if err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to decode embedding", Err: err}
+		}
+		embeddings[d.Index] = vec
+		if dims == 0 {
+			dims = len(vec)
+		}
+	}
+
+	return &EmbeddingResponse{
+		Embeddings:  embeddings,
+		Model:       result.Model,
+		TotalTokens: result.Usage.TotalTokens,
+		Dimensions:  dims,
+	}, nil
+}
+
+// decodeEmbedding parses a single embedding from an OpenAI embeddings
+// response. By default that's a JSON float array; with encodingFormat
+// "base64" it's instead a base64-encoded little-endian float32 payload,
+// about 4x smaller over the wire, which EmbeddingRequest.EncodingFormat
+// requests.
+func decodeEmbedding(raw json.RawMessage, encodingFormat string) ([]float64, error) {
+	if encodingFormat != "base64" {
+		var vec []float64
+		if err := json.Unmarshal(raw, &vec); err != nil {
+			return nil, err
+		}
+		return vec, nil
+	}
+
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	vec := make([]float64, len(data)/4)
+	for i := range vec {
+		bits := binary.LittleEndian.Uint32(data[i*4 : i*4+4])
+		vec[i] = float64(math.Float32frombits(bits))
+	}
+	return vec, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Text-to-Speech
+// ═══════════════════════════════════════════════════════════════════════════
+
+func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+	if p.config.APIKey == "" {
+		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
+
+	if req.Format != "" {
+		if err := ValidateAudioFormat(req.Format); err != nil {
+			return nil, err
+		}
+	}
+
+	oaiReq := struct {
+		Model          string  `json:"model"`
+		Input          string  `json:"input"`
+		Voice          string  `json:"voice"`
+		ResponseFormat string  `json:"response_format,omitempty"`
+		Speed          float64 `json:"speed,omitempty"`
+	}{
+		Model:          req.Model,
+		Input:          req.Input,
+		Voice:          req.Voice,
+		ResponseFormat: req.Format,
+		Speed:          req.Speed,
+	}
+
+	body, err := json.Marshal(oaiReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+	}
+
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/speech", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setHeaders(httpReq, nil)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     fmt.Sprintf("%d", resp.StatusCode),
+			Message:  string(errBody),
+		}
+	}
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read audio", Err: err}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = defaultContentType(req.Format)
+	}
+
+	return &TTSResponse{
+		Audio:       audio,
+		Format:      req.Format,
+		ContentType: contentType,
+	}, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Speech-to-Text
+// ═══════════════════════════════════════════════════════════════════════════
+
+func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error) {
+	if p.config.APIKey == "" {
+		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
+
+	// Create multipart form
+	var buf bytes.Buffer
+	writer := newMultipartWriter(&buf)
+
+	// Add file
+	filename := req.Filename
+	if filename == "" {
+		filename = "audio.mp3"
+	}
+	fw, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create form file", Err: err}
+	}
+	if _, err := fw.Write(req.Audio); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write audio", Err: err}
+	}
+
+	// Add model
+	if err := writer.WriteField("model", req.Model); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write model", Err: err}
+	}
+
+	// Optional fields
+	if req.Language != "" {
+		writer.WriteField("language", req.Language)
+	}
+	if req.Prompt != "" {
+		writer.WriteField("prompt", req.Prompt)
+	}
+	if req.Diarize {
+		writer.WriteField("response_format", "diarized_json")
+		if req.SpeakerCount > 0 {
+			writer.WriteField("known_speaker_count", strconv.Itoa(req.SpeakerCount))
+		}
+	} else if req.Timestamps {
+		writer.WriteField("timestamp_granularities[]", "word")
+		writer.WriteField("response_format", "verbose_json")
+	}
+
+	writer.Close()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/transcriptions", &buf)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setAuthHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     fmt.Sprintf("%d", resp.StatusCode),
+			Message:  string(respBody),
+		}
+	}
+
+	// Parse response
+	var result struct {
+		Text     string  `json:"text"`
+		Language string  `json:"language,omitempty"`
+		Duration float64 `json:"duration,omitempty"`
+		Words    []struct {
+			Word  string  `json:"word"`
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+		} `json:"words,omitempty"`
+		Segments []struct {
+			Speaker string  `json:"speaker"`
+			Text    string  `json:"text"`
+			Start   float64 `json:"start"`
+			End     float64 `json:"end"`
+		} `json:"segments,omitempty"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		// Simple text response
+		return &STTResponse{Text: string(respBody)}, nil
+	}
+
+	sttResp := &STTResponse{
+		Text:     result.Text,
+		Language: result.Language,
+		Duration: result.Duration,
+	}
+
+	for _, w := range result.Words {
+		sttResp.Words = append(sttResp.Words, WordTimestamp{
+			Word:  w.Word,
+			Start: w.Start,
+			End:   w.End,
+		})
+	}
+
+	for _, seg := range result.Segments {
+		sttResp.Segments = append(sttResp.Segments, TranscriptSegment{
+			Speaker: seg.Speaker,
+			Text:    seg.Text,
+			Start:   seg.Start,
+			End:     seg.End,
+		})
+	}
+
+	return sttResp, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// File Uploads
+// ═══════════════════════════════════════════════════════════════════════════
+
+// UploadFile implements FileUploader by uploading data to the Files API
+// (/v1/files) with purpose "user_data" and returning the resulting file ID,
+// so Builder.AttachFile can reference it in later requests instead of
+// inlining the file's content into the prompt.
+func (p *OpenAIProvider) UploadFile(ctx context.Context, name string, data []byte, mimeType string) (string, error) {
+	return p.uploadFile(ctx, name, data, "user_data")
+}
+
+// uploadFile posts data to the Files API under purpose, shared by UploadFile
+// (purpose "user_data") and SubmitBatch (purpose "batch").
+func (p *OpenAIProvider) uploadFile(ctx context.Context, name string, data []byte, purpose string) (string, error) {
+	if p.config.APIKey == "" {
+		return "", &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	}
+
+	var buf bytes.Buffer
+	writer := newMultipartWriter(&buf)
+
+	fw, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to create form file", Err: err}
+	}
+	if _, err := fw.Write(data); err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to write file data", Err: err}
+	}
+
+	if err := writer.WriteField("purpose", purpose); err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to write purpose", Err: err}
+	}
+
+	writer.Close()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/files", &buf)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+
+	p.setAuthHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if Debug {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/files")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &ProviderError{
+			Provider: p.Name(),
+			Code:     fmt.Sprintf("%d", resp.StatusCode),
+			Message:  string(respBody),
+		}
+	}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to parse upload response", Err: err}
+	}
+
+	return result.ID, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Batch API
+// ═══════════════════════════════════════════════════════════════════════════
+
+// batchRequestLine is one line of the JSONL file OpenAI's Batch API expects
+// as input, wrapping the normal chat-completions request body with the
+// custom_id used to line results back up to the request that produced them.
+type batchRequestLine struct {
+	CustomID string         `json:"custom_id"`
+	Method   string         `json:"method"`
+	URL      string         `json:"url"`
+	Body     *openAIRequest `json:"body"`
+}
+
+// batchResultLine is one line of the JSONL file downloaded from a completed
+// batch job's output_file_id.
+type batchResultLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// SubmitBatch implements BatchProcessor by serializing reqs to the Batch
+// API's JSONL format, uploading it via the Files API with purpose "batch",
+// and creating a batch job against it.
+func (p *OpenAIProvider) SubmitBatch(ctx context.Context, reqs []*ProviderRequest) (string, error) {
+	var buf bytes.Buffer
+	for i, req := range reqs {
+		line := batchRequestLine{
+			CustomID: fmt.Sprintf("req-%d", i),
+			Method:   "POST",
+			URL:      "/v1/chat/completions",
+			Body:     p.buildRequest(req),
+		}
+		data, err := json.Marshal(line)
+		if err != nil {
+			return "", &ProviderError{Provider: p.Name(), Message: "failed to serialize batch request", Err: err}
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	fileID, err := p.uploadFile(ctx, "batch.jsonl", buf.Bytes(), "batch")
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	})
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to serialize batch job", Err: err}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/batches", bytes.NewReader(body))
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+	p.setHeaders(httpReq, nil)
+
+	if Debug {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/batches")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &ProviderError{Provider: p.Name(), Code: fmt.Sprintf("%d", resp.StatusCode), Message: string(respBody)}
 	}
 
-	// Use output_text convenience field if available
-	if textContent == "" && result.OutputText != "" {
-		textContent = result.OutputText
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", &ProviderError{Provider: p.Name(), Message: "failed to parse batch response", Err: err}
 	}
 
-	return &ProviderResponse{
-		Content:          textContent,
-		PromptTokens:     result.Usage.InputTokens,
-		CompletionTokens: result.Usage.OutputTokens,
-		TotalTokens:      result.Usage.TotalTokens,
-		ResponsesOutput: &ResponsesOutput{
-			Text:      textContent,
-			Citations: citations,
-			ToolCalls: toolCalls,
-		},
-	}, nil
+	return result.ID, nil
 }
 
-// ═══════════════════════════════════════════════════════════════════════════
-// Embeddings
-// ═══════════════════════════════════════════════════════════════════════════
+// getBatch fetches the raw /v1/batches/{id} resource, shared by BatchStatus
+// and BatchResults (which also needs output_file_id off the same object).
+func (p *OpenAIProvider) getBatch(ctx context.Context, batchID string) (status, outputFileID string, err error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.config.BaseURL+"/batches/"+batchID, nil)
+	if err != nil {
+		return "", "", &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	}
+	p.setHeaders(httpReq, nil)
 
-func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*EmbeddingResponse, error) {
-	if p.config.APIKey == "" {
-		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return "", "", &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
 	}
+	defer resp.Body.Close()
 
-	oaiReq := struct {
-		Model      string   `json:"model"`
-		Input      []string `json:"input"`
-		Dimensions int      `json:"dimensions,omitempty"`
-	}{
-		Model: req.Model,
-		Input: req.Input,
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
-	if req.Dimensions > 0 {
-		oaiReq.Dimensions = req.Dimensions
+	if resp.StatusCode != http.StatusOK {
+		return "", "", &ProviderError{Provider: p.Name(), Code: fmt.Sprintf("%d", resp.StatusCode), Message: string(respBody)}
 	}
 
-	body, err := json.Marshal(oaiReq)
+	var result struct {
+		Status       string `json:"status"`
+		OutputFileID string `json:"output_file_id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", "", &ProviderError{Provider: p.Name(), Message: "failed to parse batch response", Err: err}
+	}
+
+	return result.Status, result.OutputFileID, nil
+}
+
+// BatchStatus implements BatchProcessor.
+func (p *OpenAIProvider) BatchStatus(ctx context.Context, batchID string) (string, error) {
+	status, _, err := p.getBatch(ctx, batchID)
+	return status, err
+}
+
+// BatchResults implements BatchProcessor by fetching the completed job's
+// output_file_id and downloading it, then parsing each JSONL line into a
+// ProviderResponse via the same parseResponse path Send uses, ordered by the
+// custom_id assigned in SubmitBatch.
+func (p *OpenAIProvider) BatchResults(ctx context.Context, batchID string) ([]*ProviderResponse, error) {
+	status, outputFileID, err := p.getBatch(ctx, batchID)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+		return nil, err
+	}
+	if status != "completed" {
+		return nil, &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("batch job is not complete (status: %s)", status)}
+	}
+	if outputFileID == "" {
+		return nil, &ProviderError{Provider: p.Name(), Message: "batch job completed with no output file"}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/embeddings", bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.config.BaseURL+"/files/"+outputFileID+"/content", nil)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
-
-	p.setHeaders(httpReq)
+	p.setAuthHeaders(httpReq)
 
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
@@ -784,169 +1906,193 @@ func (p *OpenAIProvider) Embed(ctx context.Context, req *EmbeddingRequest) (*Emb
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
-
-	var result struct {
-		Data []struct {
-			Embedding []float64 `json:"embedding"`
-			Index     int       `json:"index"`
-		} `json:"data"`
-		Model string `json:"model"`
-		Usage struct {
-			PromptTokens int `json:"prompt_tokens"`
-			TotalTokens  int `json:"total_tokens"`
-		} `json:"usage"`
-		Error *struct {
-			Message string `json:"message"`
-			Code    string `json:"code"`
-		} `json:"error,omitempty"`
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ProviderError{Provider: p.Name(), Code: fmt.Sprintf("%d", resp.StatusCode), Message: string(respBody)}
 	}
 
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "parse error", Err: err}
-	}
+	results := map[int]*ProviderResponse{}
+	maxIndex := -1
+	for _, raw := range strings.Split(strings.TrimSpace(string(respBody)), "\n") {
+		if raw == "" {
+			continue
+		}
 
-	if result.Error != nil {
-		return nil, &ProviderError{Provider: p.Name(), Code: result.Error.Code, Message: result.Error.Message}
-	}
+		var line batchResultLine
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to parse batch result line", Err: err}
+		}
 
-	embeddings := make([][]float64, len(result.Data))
-	var dims int
-	for _, d := range result.Data {
-		embeddings[d.Index] = d.Embedding
-		if dims == 0 {
-			dims = len(d.Embedding)
+		var index int
+		fmt.Sscanf(line.CustomID, "req-%d", &index)
+		if index > maxIndex {
+			maxIndex = index
+		}
+
+		if line.Error != nil {
+			results[index] = &ProviderResponse{FinishReason: "error: " + line.Error.Message}
+			continue
+		}
+		if line.Response == nil {
+			continue
+		}
+
+		providerResp, err := p.parseResponse(line.Response.Body, false)
+		if err != nil {
+			return nil, err
 		}
+		results[index] = providerResp
 	}
 
-	return &EmbeddingResponse{
-		Embeddings:  embeddings,
-		Model:       result.Model,
-		TotalTokens: result.Usage.TotalTokens,
-		Dimensions:  dims,
-	}, nil
+	out := make([]*ProviderResponse, maxIndex+1)
+	for i, resp := range results {
+		out[i] = resp
+	}
+
+	return out, nil
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
-// Text-to-Speech
+// Image Edit/Variation
 // ═══════════════════════════════════════════════════════════════════════════
 
-func (p *OpenAIProvider) TextToSpeech(ctx context.Context, req *TTSRequest) (*TTSResponse, error) {
+// openAIImageEditsModel is the default model used for /images/edits when
+// ImageEditRequest.Model is left empty.
+const openAIImageEditsModel = "gpt-image-1"
+
+// openAIImageVariationModel is the default model used for /images/variations
+// when ImageVariationRequest.Model is left empty. The variations endpoint
+// only accepts dall-e-2; gpt-image-1 is not valid there.
+const openAIImageVariationModel = "dall-e-2"
+
+// EditImage implements ImageProvider by editing req.Image (optionally
+// masked by req.Mask) via the standalone /images/edits endpoint, distinct
+// from the in-conversation image_generation tool.
+func (p *OpenAIProvider) EditImage(ctx context.Context, req *ImageEditRequest) (*ImageEditResponse, error) {
 	if p.config.APIKey == "" {
 		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
 	}
 
-	oaiReq := struct {
-		Model          string  `json:"model"`
-		Input          string  `json:"input"`
-		Voice          string  `json:"voice"`
-		ResponseFormat string  `json:"response_format,omitempty"`
-		Speed          float64 `json:"speed,omitempty"`
-	}{
-		Model:          req.Model,
-		Input:          req.Input,
-		Voice:          req.Voice,
-		ResponseFormat: req.Format,
-		Speed:          req.Speed,
-	}
+	var buf bytes.Buffer
+	writer := newMultipartWriter(&buf)
 
-	body, err := json.Marshal(oaiReq)
+	fw, err := writer.CreateFormFile("image", "image.png")
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create form file", Err: err}
+	}
+	if _, err := fw.Write(req.Image); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write image", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/speech", bytes.NewReader(body))
-	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
+	if len(req.Mask) > 0 {
+		mw, err := writer.CreateFormFile("mask", "mask.png")
+		if err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to create mask form file", Err: err}
+		}
+		if _, err := mw.Write(req.Mask); err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to write mask", Err: err}
+		}
 	}
 
-	p.setHeaders(httpReq)
+	model := req.Model
+	if model == "" {
+		model = openAIImageEditsModel
+	}
+	n := req.N
+	if n <= 0 {
+		n = 1
+	}
+	writer.WriteField("model", model)
+	writer.WriteField("prompt", req.Prompt)
+	writer.WriteField("n", strconv.Itoa(n))
+	if req.Size != "" {
+		writer.WriteField("size", req.Size)
+	}
+	if req.Format != "" {
+		writer.WriteField("output_format", req.Format)
+	}
 
-	resp, err := p.httpClient.Do(httpReq)
+	writer.Close()
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/images/edits", &buf)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		errBody, _ := io.ReadAll(resp.Body)
-		return nil, &ProviderError{
-			Provider: p.Name(),
-			Code:     fmt.Sprintf("%d", resp.StatusCode),
-			Message:  string(errBody),
-		}
+	p.setAuthHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	if Debug {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/images/edits")
 	}
 
-	audio, err := io.ReadAll(resp.Body)
+	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read audio", Err: err}
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
 	}
+	defer resp.Body.Close()
 
-	return &TTSResponse{
-		Audio:       audio,
-		Format:      req.Format,
-		ContentType: resp.Header.Get("Content-Type"),
-	}, nil
+	return p.parseImagesResponse(resp, req.Format)
 }
 
-// ═══════════════════════════════════════════════════════════════════════════
-// Speech-to-Text
-// ═══════════════════════════════════════════════════════════════════════════
-
-func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*STTResponse, error) {
+// ImageVariation implements ImageProvider by generating variations of
+// req.Image via the standalone /images/variations endpoint.
+func (p *OpenAIProvider) ImageVariation(ctx context.Context, req *ImageVariationRequest) (*ImageEditResponse, error) {
 	if p.config.APIKey == "" {
 		return nil, &ProviderError{Provider: p.Name(), Message: "OPENAI_API_KEY not set"}
 	}
 
-	// Create multipart form
 	var buf bytes.Buffer
 	writer := newMultipartWriter(&buf)
 
-	// Add file
-	filename := req.Filename
-	if filename == "" {
-		filename = "audio.mp3"
-	}
-	fw, err := writer.CreateFormFile("file", filename)
+	fw, err := writer.CreateFormFile("image", "image.png")
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create form file", Err: err}
 	}
-	if _, err := fw.Write(req.Audio); err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write audio", Err: err}
-	}
-
-	// Add model
-	if err := writer.WriteField("model", req.Model); err != nil {
-		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write model", Err: err}
+	if _, err := fw.Write(req.Image); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to write image", Err: err}
 	}
 
-	// Optional fields
-	if req.Language != "" {
-		writer.WriteField("language", req.Language)
+	model := req.Model
+	if model == "" {
+		model = openAIImageVariationModel
 	}
-	if req.Prompt != "" {
-		writer.WriteField("prompt", req.Prompt)
+	n := req.N
+	if n <= 0 {
+		n = 1
 	}
-	if req.Timestamps {
-		writer.WriteField("timestamp_granularities[]", "word")
-		writer.WriteField("response_format", "verbose_json")
+	writer.WriteField("model", model)
+	writer.WriteField("n", strconv.Itoa(n))
+	if req.Size != "" {
+		writer.WriteField("size", req.Size)
 	}
 
 	writer.Close()
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/audio/transcriptions", &buf)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/images/variations", &buf)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	httpReq.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	p.setAuthHeaders(httpReq)
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
+	if Debug {
+		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/images/variations")
+	}
+
 	resp, err := p.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
 	}
 	defer resp.Body.Close()
 
+	return p.parseImagesResponse(resp, req.Format)
+}
+
+// parseImagesResponse reads and decodes the shared /images/edits and
+// /images/variations response shape: a list of base64-encoded images.
+// format, if set, is echoed back on the response (OpenAI doesn't report it).
+func (p *OpenAIProvider) parseImagesResponse(resp *http.Response, format string) (*ImageEditResponse, error) {
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
@@ -960,36 +2106,96 @@ func (p *OpenAIProvider) SpeechToText(ctx context.Context, req *STTRequest) (*ST
 		}
 	}
 
-	// Parse response
 	var result struct {
-		Text     string  `json:"text"`
-		Language string  `json:"language,omitempty"`
-		Duration float64 `json:"duration,omitempty"`
-		Words    []struct {
-			Word  string  `json:"word"`
-			Start float64 `json:"start"`
-			End   float64 `json:"end"`
-		} `json:"words,omitempty"`
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+			URL     string `json:"url"`
+		} `json:"data"`
 	}
-
 	if err := json.Unmarshal(respBody, &result); err != nil {
-		// Simple text response
-		return &STTResponse{Text: string(respBody)}, nil
+		return nil, &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("parse error: %v\nBody: %s", err, string(respBody))}
 	}
 
-	sttResp := &STTResponse{
-		Text:     result.Text,
-		Language: result.Language,
-		Duration: result.Duration,
+	imgResp := &ImageEditResponse{Format: format}
+	for _, d := range result.Data {
+		if d.B64JSON == "" {
+			continue
+		}
+		img, err := base64.StdEncoding.DecodeString(d.B64JSON)
+		if err != nil {
+			return nil, &ProviderError{Provider: p.Name(), Message: "failed to decode image", Err: err}
+		}
+		imgResp.Images = append(imgResp.Images, img)
 	}
 
-	for _, w := range result.Words {
-		sttResp.Words = append(sttResp.Words, WordTimestamp{
-			Word:  w.Word,
-			Start: w.Start,
-			End:   w.End,
-		})
+	return imgResp, nil
+}
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Models
+// ═══════════════════════════════════════════════════════════════════════════
+
+// ListModels implements ModelLister by listing the models this API key has
+// access to via GET /v1/models.
+func (p *OpenAIProvider) ListModels(ctx context.Context) ([]ModelDescriptor, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", p.config.BaseURL+"/models", nil)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	return sttResp, nil
+	p.setAuthHeaders(httpReq)
+
+	if Debug {
+		fmt.Printf("%s [%s] GET %s\n", colorDim("→"), p.Name(), "/models")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResult struct {
+			Error *struct {
+				Message string `json:"message"`
+				Code    string `json:"code"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResult) == nil && errResult.Error != nil {
+			return nil, &ProviderError{Provider: p.Name(), Code: classifyErrorCode(errResult.Error.Code, errResult.Error.Message), Message: errResult.Error.Message}
+		}
+		return nil, &ProviderError{
+			Provider: p.Name(),
+			Code:     fmt.Sprintf("%d", resp.StatusCode),
+			Message:  string(respBody),
+		}
+	}
+
+	var result struct {
+		Data []struct {
+			ID      string `json:"id"`
+			OwnedBy string `json:"owned_by"`
+			Created int64  `json:"created"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to parse models response", Err: err}
+	}
+
+	models := make([]ModelDescriptor, len(result.Data))
+	for i, m := range result.Data {
+		models[i] = ModelDescriptor{
+			ID:      m.ID,
+			Owner:   m.OwnedBy,
+			Created: m.Created,
+		}
+	}
+
+	return models, nil
 }
@@ -1,7 +1,6 @@
 package ai
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -32,10 +31,7 @@ func NewOpenRouterProvider(config ProviderConfig) *OpenRouterProvider {
 	if config.APIKey == "" {
 		config.APIKey = os.Getenv("OPENROUTER_API_KEY")
 	}
-	client := http.DefaultClient
-	if config.Timeout > 0 {
-		client = &http.Client{Timeout: config.Timeout}
-	}
+	client := buildHTTPClient(config)
 	return &OpenRouterProvider{config: config, httpClient: client}
 }
 
@@ -55,6 +51,13 @@ func (p *OpenRouterProvider) Capabilities() ProviderCapabilities {
 	}
 }
 
+// CloseIdleConnections closes any idle connections on the underlying HTTP
+// transport, releasing them back to the OS instead of leaving them open
+// until they time out on their own. Client.Close calls this.
+func (p *OpenRouterProvider) CloseIdleConnections() {
+	p.httpClient.CloseIdleConnections()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Send
 // ═══════════════════════════════════════════════════════════════════════════
@@ -76,12 +79,17 @@ func (p *OpenRouterProvider) Send(ctx context.Context, req *ProviderRequest) (*P
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s\n", colorDim("→"), p.Name(), "/chat/completions")
@@ -98,7 +106,7 @@ func (p *OpenRouterProvider) Send(ctx context.Context, req *ProviderRequest) (*P
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to read response", Err: err}
 	}
 
-	return p.parseResponse(respBody)
+	return p.parseResponse(respBody, req.CaptureRaw)
 }
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -123,12 +131,17 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	reqBody, compressed := compressRequestBody(p.config, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.config.BaseURL+"/chat/completions", bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, &ProviderError{Provider: p.Name(), Message: "failed to create request", Err: err}
 	}
 
-	p.setHeaders(httpReq)
+	p.setHeaders(httpReq, req.Headers)
+	if compressed {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if Debug {
 		fmt.Printf("%s [%s] POST %s (stream)\n", colorDim("→"), p.Name(), "/chat/completions")
@@ -150,24 +163,21 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 	}
 
 	var fullContent strings.Builder
-	reader := bufio.NewReader(resp.Body)
+	sse := newSSEReader(resp.Body)
 
 	for {
-		line, err := reader.ReadBytes('\n')
+		data, err := readWithContext(ctx, resp.Body, func() (string, error) { return sse.Next(p.Name()) })
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
-			return nil, &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
-		}
-
-		line = bytes.TrimSpace(line)
-		if !bytes.HasPrefix(line, []byte("data: ")) {
-			continue
+			if ctx.Err() != nil {
+				return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream canceled", Err: ctx.Err()}
+			}
+			return partialResponse(fullContent.String()), &ProviderError{Provider: p.Name(), Message: "stream read error", Err: err}
 		}
 
-		data := bytes.TrimPrefix(line, []byte("data: "))
-		if string(data) == "[DONE]" {
+		if data == "[DONE]" {
 			break
 		}
 
@@ -179,7 +189,10 @@ func (p *OpenRouterProvider) SendStream(ctx context.Context, req *ProviderReques
 			} `json:"choices"`
 		}
 
-		if err := json.Unmarshal(data, &chunk); err != nil {
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			if Debug {
+				fmt.Printf("%s [%s] skipping unparseable stream event: %s\n", colorYellow("⚠"), p.Name(), data)
+			}
 			continue
 		}
 
@@ -239,7 +252,15 @@ func (p *OpenRouterProvider) buildRequest(req *ProviderRequest) *openRouterReque
 	return orReq
 }
 
-func (p *OpenRouterProvider) setHeaders(req *http.Request) {
+// BuildRawRequest implements RequestPreviewer.
+func (p *OpenRouterProvider) BuildRawRequest(req *ProviderRequest) any {
+	return p.buildRequest(req)
+}
+
+// setHeaders sets default HTTP-Referer/X-Title headers (used by OpenRouter for
+// attribution and leaderboard ranking), then applies config.Headers on top so
+// callers can override either from ProviderConfig.
+func (p *OpenRouterProvider) setHeaders(req *http.Request, extra map[string]string) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
 	req.Header.Set("HTTP-Referer", "gopkg.in/dragon-born/go-llm.v1")
@@ -248,11 +269,13 @@ func (p *OpenRouterProvider) setHeaders(req *http.Request) {
 	for k, v := range p.config.Headers {
 		req.Header.Set(k, v)
 	}
+	setExtraHeaders(req, extra)
 }
 
-func (p *OpenRouterProvider) parseResponse(body []byte) (*ProviderResponse, error) {
+func (p *OpenRouterProvider) parseResponse(body []byte, captureRaw bool) (*ProviderResponse, error) {
 	var result struct {
 		ID      string `json:"id"`
+		Model   string `json:"model"`
 		Choices []struct {
 			Message struct {
 				Role      string     `json:"role"`
@@ -282,7 +305,7 @@ func (p *OpenRouterProvider) parseResponse(body []byte) (*ProviderResponse, erro
 	if result.Error != nil {
 		return nil, &ProviderError{
 			Provider: p.Name(),
-			Code:     result.Error.Code,
+			Code:     classifyErrorCode(result.Error.Code, result.Error.Message),
 			Message:  result.Error.Message,
 		}
 	}
@@ -302,5 +325,7 @@ func (p *OpenRouterProvider) parseResponse(body []byte) (*ProviderResponse, erro
 		CompletionTokens: result.Usage.CompletionTokens,
 		TotalTokens:      result.Usage.TotalTokens,
 		FinishReason:     choice.FinishReason,
+		ServedModel:      result.Model,
+		Raw:              captureRawIfRequested(body, captureRaw),
 	}, nil
 }
@@ -3,10 +3,12 @@ package ai
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestProviders_SendStream_ParsesChunks(t *testing.T) {
@@ -25,9 +27,9 @@ func TestProviders_SendStream_ParsesChunks(t *testing.T) {
 			},
 			path: "/chat/completions",
 			writeBody: func(w http.ResponseWriter) {
-				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n"))
-				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n"))
-				_, _ = w.Write([]byte("data: [DONE]\n"))
+				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n"))
+				_, _ = w.Write([]byte("data: [DONE]\n\n"))
 			},
 			wantFull: "Hello",
 			wantTokens: func(full string) (int, int, int) {
@@ -43,9 +45,9 @@ func TestProviders_SendStream_ParsesChunks(t *testing.T) {
 			},
 			path: "/chat/completions",
 			writeBody: func(w http.ResponseWriter) {
-				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"A\"}}]}\n"))
-				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"B\"}}]}\n"))
-				_, _ = w.Write([]byte("data: [DONE]\n"))
+				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"A\"}}]}\n\n"))
+				_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"B\"}}]}\n\n"))
+				_, _ = w.Write([]byte("data: [DONE]\n\n"))
 			},
 			wantFull: "AB",
 			wantTokens: func(full string) (int, int, int) {
@@ -60,8 +62,8 @@ func TestProviders_SendStream_ParsesChunks(t *testing.T) {
 			},
 			path: "/messages",
 			writeBody: func(w http.ResponseWriter) {
-				_, _ = w.Write([]byte("data: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n"))
-				_, _ = w.Write([]byte("data: {\"type\":\"message_stop\"}\n"))
+				_, _ = w.Write([]byte("data: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n"))
+				_, _ = w.Write([]byte("data: {\"type\":\"message_stop\"}\n\n"))
 			},
 			wantFull: "Hi",
 			wantTokens: func(full string) (int, int, int) {
@@ -77,8 +79,8 @@ func TestProviders_SendStream_ParsesChunks(t *testing.T) {
 			// google uses full URL with query params; we'll match by prefix.
 			path: "/models/gemini-3-flash-preview:streamGenerateContent",
 			writeBody: func(w http.ResponseWriter) {
-				_, _ = w.Write([]byte("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Yo\"}]}}]}\n"))
-				_, _ = w.Write([]byte("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"!\"}]}}]}\n"))
+				_, _ = w.Write([]byte("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"Yo\"}]}}]}\n\n"))
+				_, _ = w.Write([]byte("data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"!\"}]}}]}\n\n"))
 			},
 			wantFull: "Yo!",
 			wantTokens: func(full string) (int, int, int) {
@@ -164,3 +166,250 @@ func TestProviders_SendStream_ParsesChunks(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenAIProvider_SendStreamWithToolDeltas_AssemblesFragments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_1","function":{"name":"get_weather","arguments":""}}]}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]}}]}` + "\n\n"))
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{},"finish_reason":"tool_calls"}]}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: server.URL})
+
+	var deltas []ToolCallDelta
+	resp, err := p.SendStreamWithToolDeltas(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "weather in Paris?"}},
+		Stream:   true,
+	}, func(chunk string) {}, func(delta ToolCallDelta) {
+		deltas = append(deltas, delta)
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deltas) != 3 {
+		t.Fatalf("expected 3 tool call delta fragments, got %d", len(deltas))
+	}
+	if deltas[0].Name != "get_weather" {
+		t.Errorf("expected first fragment to carry the function name, got %q", deltas[0].Name)
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("expected 1 assembled tool call, got %d", len(resp.ToolCalls))
+	}
+	call := resp.ToolCalls[0]
+	if call.ID != "call_1" || call.Function.Name != "get_weather" {
+		t.Errorf("unexpected assembled call: %+v", call)
+	}
+	if call.Function.Arguments != `{"city":"Paris"}` {
+		t.Errorf("expected assembled arguments %q, got %q", `{"city":"Paris"}`, call.Function.Arguments)
+	}
+}
+
+func TestOpenAIProvider_SendStream_ReturnsErrorEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+		_, _ = w.Write([]byte("data: {\"error\":{\"message\":\"content filtered\",\"type\":\"content_filter\",\"code\":\"content_filter\"}}\n\n"))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: server.URL})
+
+	var chunks []string
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Stream:   true,
+	}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+
+	if err == nil {
+		t.Fatal("expected an error from the mid-stream error event, got nil")
+	}
+	if resp == nil || resp.Content != "Hel" {
+		t.Fatalf("expected partial response %q alongside the error, got %+v", "Hel", resp)
+	}
+	var pErr *ProviderError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("expected a *ProviderError, got %T: %v", err, err)
+	}
+	if pErr.Message != "content filtered" {
+		t.Errorf("expected error message %q, got %q", "content filtered", pErr.Message)
+	}
+	if strings.Join(chunks, "") != "Hel" {
+		t.Errorf("expected partial content %q before the error event, got %q", "Hel", strings.Join(chunks, ""))
+	}
+}
+
+func TestOpenAIProvider_SendStream_ParsesFinishReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"length\"}]}\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: server.URL})
+
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Stream:   true,
+	}, func(chunk string) {})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.FinishReason != "length" {
+		t.Errorf("expected finish reason %q, got %q", "length", resp.FinishReason)
+	}
+}
+
+func TestOpenAIProvider_SendStream_ReturnsPartialContentOnDroppedConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		// Simulate a network drop mid-stream: hijack and sever the
+		// connection instead of closing it cleanly, so the client sees a
+		// read error rather than a clean EOF.
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			return
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer server.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: server.URL})
+
+	var chunks []string
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Stream:   true,
+	}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+
+	if err == nil {
+		t.Fatal("expected an error from the dropped connection, got nil")
+	}
+	if resp == nil || resp.Content != "Hel" {
+		t.Fatalf("expected the partial content streamed before the drop to be returned, got %+v", resp)
+	}
+	if strings.Join(chunks, "") != "Hel" {
+		t.Errorf("expected callback to have already delivered %q, got %q", "Hel", strings.Join(chunks, ""))
+	}
+}
+
+func TestOpenAIProvider_SendStream_CancelUnblocksPromptly(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		<-block // hang as if the connection stalled, so the only way out is cancellation
+	}))
+	defer server.Close()
+	defer close(block)
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	resp, err := p.SendStream(ctx, &ProviderRequest{
+		Model:    string(ModelGPT5),
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		Stream:   true,
+	}, func(chunk string) {})
+	elapsed := time.Since(start)
+
+	if resp == nil || resp.Content != "Hel" {
+		t.Fatalf("expected the partial content streamed before cancellation to be returned, got %+v", resp)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error wrapping context.Canceled, got %v", err)
+	}
+	if elapsed > 1*time.Second {
+		t.Fatalf("expected SendStream to return promptly after cancellation, took %s", elapsed)
+	}
+}
+
+func TestOpenAIProvider_SendStream_ResponsesAPIBuiltinTools(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/responses" {
+			t.Fatalf("expected path %q, got %q", "/responses", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"type\":\"response.output_text.delta\",\"delta\":\"The capital \"}\n\n"))
+		_, _ = w.Write([]byte("data: {\"type\":\"response.output_text.delta\",\"delta\":\"is Paris.\"}\n\n"))
+		_, _ = w.Write([]byte(`data: {"type":"response.completed","response":{"output":[` +
+			`{"type":"web_search_call","id":"ws_1","status":"completed"},` +
+			`{"type":"message","content":[{"type":"output_text","text":"The capital is Paris.",` +
+			`"annotations":[{"type":"url_citation","url":"https://example.com","title":"Paris"}]}]}` +
+			`],"usage":{"input_tokens":5,"output_tokens":7,"total_tokens":12}}}` + "\n\n"))
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(ProviderConfig{APIKey: "k", BaseURL: srv.URL})
+	var chunks []string
+	resp, err := p.SendStream(context.Background(), &ProviderRequest{
+		Model:        string(ModelGPT5),
+		Messages:     []Message{{Role: "user", Content: "What is the capital of France?"}},
+		Stream:       true,
+		BuiltinTools: []BuiltinTool{{Type: "web_search"}},
+	}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantFull := "The capital is Paris."
+	joined := strings.Join(chunks, "")
+	if joined != wantFull {
+		t.Fatalf("expected streamed chunks %q, got %q", wantFull, joined)
+	}
+
+	if resp.Content != wantFull {
+		t.Fatalf("expected full content %q, got %q", wantFull, resp.Content)
+	}
+	if resp.PromptTokens != 5 || resp.CompletionTokens != 7 || resp.TotalTokens != 12 {
+		t.Fatalf("unexpected tokens: prompt=%d completion=%d total=%d", resp.PromptTokens, resp.CompletionTokens, resp.TotalTokens)
+	}
+
+	if resp.ResponsesOutput == nil {
+		t.Fatalf("expected ResponsesOutput to be populated")
+	}
+	if len(resp.ResponsesOutput.Citations) != 1 || resp.ResponsesOutput.Citations[0].URL != "https://example.com" {
+		t.Fatalf("expected one citation, got %+v", resp.ResponsesOutput.Citations)
+	}
+	if len(resp.ResponsesOutput.ToolCalls) != 1 || resp.ResponsesOutput.ToolCalls[0].Type != "web_search_call" {
+		t.Fatalf("expected one web_search_call tool call, got %+v", resp.ResponsesOutput.ToolCalls)
+	}
+}
@@ -97,7 +97,10 @@ func OpenRouter() *Client {
 // Default Client Management
 // ═══════════════════════════════════════════════════════════════════════════
 
-// getDefaultClient returns the default client based on DefaultProvider
+// getDefaultClient returns the default client based on DefaultProvider. It's
+// a process-wide singleton lazily created on first use and reused by every
+// call that doesn't supply its own Client via WithClient - don't call
+// Client.Close on it unless the whole process is shutting down.
 func getDefaultClient() *Client {
 	clientsMu.Lock()
 	defer clientsMu.Unlock()
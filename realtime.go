@@ -0,0 +1,196 @@
+package ai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Realtime API (WebSocket)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// realtimeBaseURL is the WebSocket endpoint for OpenAI's Realtime API.
+// Var (not const) so tests can point it at a local fake server.
+var realtimeBaseURL = "wss://api.openai.com/v1/realtime"
+
+// RealtimeConfig configures a Realtime session via RealtimeSession.Update,
+// mirroring the "session" object of the session.update event.
+type RealtimeConfig struct {
+	Modalities   []string // e.g. []string{"text"}, []string{"text", "audio"}
+	Instructions string
+	Voice        string // e.g. "alloy", only meaningful when Modalities includes "audio"
+}
+
+// RealtimeSession is a duplex connection to OpenAI's Realtime API, handling
+// the session.update / input_audio_buffer.append / response.create event
+// protocol over a WebSocket and surfacing incremental output as channels.
+//
+// Text and audio deltas are delivered on TextDeltas and AudioDeltas as they
+// arrive; Errors carries any "error" events the server sends; Done is
+// closed once the connection ends (server close or a fatal read error).
+// Callers should drain all three channels (e.g. in a select loop) until
+// Done closes.
+type RealtimeSession struct {
+	conn *wsConn
+
+	TextDeltas  chan string
+	AudioDeltas chan []byte
+	Errors      chan error
+	Done        chan struct{}
+}
+
+// ConnectRealtime opens a Realtime session for model (e.g. ModelGPTRealtime)
+// and starts the background read loop that feeds TextDeltas, AudioDeltas,
+// and Errors.
+func ConnectRealtime(ctx context.Context, model Model) (*RealtimeSession, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, &ProviderError{Provider: "openai", Message: "OPENAI_API_KEY not set"}
+	}
+
+	resolved := resolveModel(ProviderOpenAI, model)
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer "+apiKey)
+	headers.Set("OpenAI-Beta", "realtime=v1")
+
+	conn, err := dialWebSocket(ctx, realtimeBaseURL+"?model="+resolved, headers)
+	if err != nil {
+		return nil, &ProviderError{Provider: "openai", Message: "failed to connect to realtime API", Err: err}
+	}
+
+	s := &RealtimeSession{
+		conn:        conn,
+		TextDeltas:  make(chan string, 16),
+		AudioDeltas: make(chan []byte, 16),
+		Errors:      make(chan error, 4),
+		Done:        make(chan struct{}),
+	}
+
+	go s.readLoop()
+
+	return s, nil
+}
+
+// Update sends a session.update event, configuring modalities, voice, and
+// instructions for the remainder of the session.
+func (s *RealtimeSession) Update(config RealtimeConfig) error {
+	return s.send(map[string]any{
+		"type": "session.update",
+		"session": map[string]any{
+			"modalities":   config.Modalities,
+			"instructions": config.Instructions,
+			"voice":        config.Voice,
+		},
+	})
+}
+
+// SendText appends a user text message to the conversation and asks the
+// model to respond, via conversation.item.create followed by
+// response.create.
+func (s *RealtimeSession) SendText(text string) error {
+	if err := s.send(map[string]any{
+		"type": "conversation.item.create",
+		"item": map[string]any{
+			"type": "message",
+			"role": "user",
+			"content": []map[string]any{
+				{"type": "input_text", "text": text},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+	return s.CreateResponse()
+}
+
+// AppendAudio appends raw PCM audio to the server's input audio buffer via
+// input_audio_buffer.append.
+func (s *RealtimeSession) AppendAudio(audio []byte) error {
+	return s.send(map[string]any{
+		"type":  "input_audio_buffer.append",
+		"audio": base64.StdEncoding.EncodeToString(audio),
+	})
+}
+
+// CommitAudio commits the input audio buffer via input_audio_buffer.commit,
+// signaling the end of a turn of user audio.
+func (s *RealtimeSession) CommitAudio() error {
+	return s.send(map[string]any{"type": "input_audio_buffer.commit"})
+}
+
+// CreateResponse asks the model to generate a response via response.create.
+func (s *RealtimeSession) CreateResponse() error {
+	return s.send(map[string]any{"type": "response.create"})
+}
+
+// Close sends a close frame and closes the underlying connection.
+func (s *RealtimeSession) Close() error {
+	s.conn.WriteClose(1000)
+	return s.conn.Close()
+}
+
+// send marshals event as JSON and writes it as a single text frame.
+func (s *RealtimeSession) send(event map[string]any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("realtime: failed to marshal event: %w", err)
+	}
+	return s.conn.WriteText(data)
+}
+
+// readLoop reads server events until the connection closes, dispatching
+// text/audio deltas and errors to their respective channels. It closes
+// Done (and all three channels) on exit.
+func (s *RealtimeSession) readLoop() {
+	defer close(s.Done)
+	defer close(s.TextDeltas)
+	defer close(s.AudioDeltas)
+	defer close(s.Errors)
+
+	for {
+		op, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if op == wsOpClose {
+			return
+		}
+		if op != wsOpText {
+			continue
+		}
+
+		var event struct {
+			Type  string `json:"type"`
+			Delta string `json:"delta"`
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			if Debug {
+				fmt.Printf("%s [realtime] skipping unparseable event: %s\n", colorYellow("⚠"), payload)
+			}
+			continue
+		}
+
+		switch event.Type {
+		case "response.output_text.delta", "response.text.delta":
+			s.TextDeltas <- event.Delta
+		case "response.output_audio.delta", "response.audio.delta":
+			audio, err := base64.StdEncoding.DecodeString(event.Delta)
+			if err != nil {
+				continue
+			}
+			s.AudioDeltas <- audio
+		case "error":
+			if event.Error != nil {
+				s.Errors <- fmt.Errorf("realtime: %s", event.Error.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFakeRealtimeServer starts an HTTP server that upgrades every request to
+// a WebSocket connection and hands it to handle, returning the server's
+// ws:// URL.
+func newFakeRealtimeServer(t *testing.T, handle func(conn *wsConn)) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("response writer does not support hijacking")
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAcceptKey(key))
+		rw.Flush()
+
+		handle(&wsConn{conn: conn, br: rw.Reader})
+	}))
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func withRealtimeTestServer(t *testing.T, handle func(conn *wsConn)) {
+	t.Helper()
+
+	oldKey := os.Getenv("OPENAI_API_KEY")
+	os.Setenv("OPENAI_API_KEY", "test-key")
+	t.Cleanup(func() { os.Setenv("OPENAI_API_KEY", oldKey) })
+
+	oldBaseURL := realtimeBaseURL
+	realtimeBaseURL = newFakeRealtimeServer(t, handle)
+	t.Cleanup(func() { realtimeBaseURL = oldBaseURL })
+}
+
+func TestConnectRealtime_SendTextDeliversTextDeltas(t *testing.T) {
+	withRealtimeTestServer(t, func(conn *wsConn) {
+		conn.ReadMessage() // conversation.item.create
+		conn.ReadMessage() // response.create
+		conn.WriteText([]byte(`{"type":"response.output_text.delta","delta":"Hi"}`))
+		conn.WriteText([]byte(`{"type":"response.output_text.delta","delta":" there"}`))
+	})
+
+	session, err := ConnectRealtime(context.Background(), Model("gpt-realtime"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	if err := session.SendText("hello"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for i := 0; i < 2; i++ {
+		select {
+		case delta := <-session.TextDeltas:
+			got = append(got, delta)
+		case err := <-session.Errors:
+			t.Fatalf("unexpected error event: %v", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for text delta")
+		}
+	}
+
+	if got[0] != "Hi" || got[1] != " there" {
+		t.Fatalf("expected deltas [%q %q], got %v", "Hi", " there", got)
+	}
+}
+
+func TestRealtimeSession_ErrorEventDeliveredOnErrorsChannel(t *testing.T) {
+	withRealtimeTestServer(t, func(conn *wsConn) {
+		conn.ReadMessage() // response.create
+		conn.WriteText([]byte(`{"type":"error","error":{"message":"boom"}}`))
+	})
+
+	session, err := ConnectRealtime(context.Background(), Model("gpt-realtime"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	if err := session.CreateResponse(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case err := <-session.Errors:
+		if err == nil || !strings.Contains(err.Error(), "boom") {
+			t.Fatalf("expected error containing %q, got %v", "boom", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for error event")
+	}
+}
+
+func TestConnectRealtime_ReturnsErrorWhenAPIKeyMissing(t *testing.T) {
+	oldKey := os.Getenv("OPENAI_API_KEY")
+	os.Unsetenv("OPENAI_API_KEY")
+	defer os.Setenv("OPENAI_API_KEY", oldKey)
+
+	_, err := ConnectRealtime(context.Background(), Model("gpt-realtime"))
+	if err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Fatalf("expected missing API key error, got %v", err)
+	}
+}
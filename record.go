@@ -0,0 +1,148 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Recording / Replay - record real provider exchanges to replay offline
+// ═══════════════════════════════════════════════════════════════════════════
+
+// RecordTo wraps the client's provider so every Send/SendStream
+// request/response pair is written to dir as a JSON fixture, keyed by a
+// hash of the request. Pair with ReplayProvider to get deterministic
+// offline tests without a mock HTTP server.
+func (c *Client) RecordTo(dir string) *Client {
+	c.provider = &recordingProvider{inner: c.provider, dir: dir}
+	return c
+}
+
+// recordingProvider wraps a Provider, persisting each request/response
+// pair to disk before returning it to the caller.
+type recordingProvider struct {
+	inner Provider
+	dir   string
+}
+
+func (p *recordingProvider) Name() string { return p.inner.Name() }
+
+func (p *recordingProvider) Capabilities() ProviderCapabilities { return p.inner.Capabilities() }
+
+func (p *recordingProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	resp, err := p.inner.Send(ctx, req)
+	if err == nil {
+		p.record(req, resp)
+	}
+	return resp, err
+}
+
+func (p *recordingProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	resp, err := p.inner.SendStream(ctx, req, callback)
+	if err == nil {
+		p.record(req, resp)
+	}
+	return resp, err
+}
+
+// recordedFixture is the on-disk shape written by recordingProvider and
+// read back by ReplayProvider.
+type recordedFixture struct {
+	Request  *ProviderRequest  `json:"request"`
+	Response *ProviderResponse `json:"response"`
+}
+
+func (p *recordingProvider) record(req *ProviderRequest, resp *ProviderResponse) {
+	data, err := json.MarshalIndent(recordedFixture{Request: req, Response: resp}, "", "  ")
+	if err != nil {
+		if Debug {
+			fmt.Printf("%s Error marshaling fixture: %v\n", colorRed("✗"), err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		if Debug {
+			fmt.Printf("%s Error creating fixture dir %s: %v\n", colorRed("✗"), p.dir, err)
+		}
+		return
+	}
+
+	path := filepath.Join(p.dir, requestHash(req)+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		if Debug {
+			fmt.Printf("%s Error writing fixture %s: %v\n", colorRed("✗"), path, err)
+		}
+	}
+}
+
+// requestHash deterministically hashes the parts of req that determine its
+// outcome (model and messages), so the same logical request maps to the
+// same fixture file across recording and replay runs.
+func requestHash(req *ProviderRequest) string {
+	data, _ := json.Marshal(struct {
+		Model    string    `json:"model"`
+		Messages []Message `json:"messages"`
+	}{req.Model, req.Messages})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReplayProvider implements Provider by reading fixtures recorded by
+// RecordTo from dir, keyed by the same request hash, instead of making
+// live HTTP calls.
+type ReplayProvider struct {
+	dir string
+}
+
+// NewReplayProvider creates a ReplayProvider that reads fixtures from dir.
+func NewReplayProvider(dir string) *ReplayProvider {
+	return &ReplayProvider{dir: dir}
+}
+
+// Name returns the provider identifier ("replay").
+func (p *ReplayProvider) Name() string { return "replay" }
+
+// Capabilities reports streaming support. Fixtures only store the final
+// response, so SendStream replays it as a single callback invocation.
+func (p *ReplayProvider) Capabilities() ProviderCapabilities {
+	return ProviderCapabilities{Streaming: true}
+}
+
+// Send returns the recorded response for req, or an error if no matching
+// fixture exists in dir.
+func (p *ReplayProvider) Send(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+	return p.load(req)
+}
+
+// SendStream replays the recorded response for req as a single callback
+// invocation with the full content, then returns it.
+func (p *ReplayProvider) SendStream(ctx context.Context, req *ProviderRequest, callback StreamCallback) (*ProviderResponse, error) {
+	resp, err := p.load(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Content != "" {
+		callback(resp.Content)
+	}
+	return resp, nil
+}
+
+func (p *ReplayProvider) load(req *ProviderRequest) (*ProviderResponse, error) {
+	path := filepath.Join(p.dir, requestHash(req)+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: fmt.Sprintf("no fixture for request: %v", err), Err: err}
+	}
+
+	var fixture recordedFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, &ProviderError{Provider: p.Name(), Message: "failed to parse fixture", Err: err}
+	}
+	return fixture.Response, nil
+}
@@ -0,0 +1,75 @@
+package ai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientRecordTo_WritesFixtureOnSuccess(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "hello"}, nil
+		},
+	}
+
+	client := &Client{provider: p, providerType: ProviderOpenAI}
+	client.RecordTo(dir)
+
+	req := &ProviderRequest{Model: "gpt-5", Messages: []Message{{Role: "user", Content: "hi"}}}
+	resp, err := client.provider.Send(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "hello" {
+		t.Fatalf("expected content %q, got %q", "hello", resp.Content)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 fixture file in %s, got %v (err=%v)", dir, entries, err)
+	}
+}
+
+func TestReplayProvider_ReturnsRecordedResponse(t *testing.T) {
+	dir := t.TempDir()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "recorded answer"}, nil
+		},
+	}
+	client := &Client{provider: p, providerType: ProviderOpenAI}
+	client.RecordTo(dir)
+
+	req := &ProviderRequest{Model: "gpt-5", Messages: []Message{{Role: "user", Content: "hi"}}}
+	if _, err := client.provider.Send(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error recording: %v", err)
+	}
+
+	replay := NewReplayProvider(dir)
+	resp, err := replay.Send(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error replaying: %v", err)
+	}
+	if resp.Content != "recorded answer" {
+		t.Fatalf("expected replayed content %q, got %q", "recorded answer", resp.Content)
+	}
+}
+
+func TestReplayProvider_ErrorsOnMissingFixture(t *testing.T) {
+	replay := NewReplayProvider(filepath.Join(t.TempDir(), "empty"))
+
+	_, err := replay.Send(context.Background(), &ProviderRequest{Model: "gpt-5", Messages: []Message{{Role: "user", Content: "hi"}}})
+	if err == nil {
+		t.Fatal("expected an error for a missing fixture, got nil")
+	}
+}
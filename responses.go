@@ -135,6 +135,23 @@ type MCPOptions struct {
 	AllowedTools    []string // Limit to specific tools
 }
 
+// MCPApprovalResponse is the input you send back to approve or deny a
+// pending mcp_approval_request item (see ResponsesToolCall), for an
+// MCPWith(RequireApproval: "always") conversation.
+type MCPApprovalResponse struct {
+	ApprovalRequestID string `json:"approval_request_id"`
+	Approve           bool   `json:"approve"`
+}
+
+// InputItem returns r as a Responses API input item, for posting it back
+// via Builder.InputItems.
+func (r MCPApprovalResponse) InputItem() any {
+	return struct {
+		Type string `json:"type"`
+		MCPApprovalResponse
+	}{"mcp_approval_response", r}
+}
+
 // ImageGenerationOptions configures image generation.
 type ImageGenerationOptions struct {
 	Size          string // Image dimensions: "1024x1024", "1024x1536", "auto"
@@ -559,6 +576,15 @@ type ComputerCallOutput struct {
 	AcknowledgedSafetyChecks []SafetyCheck `json:"acknowledged_safety_checks,omitempty"`
 }
 
+// InputItem returns out as a Responses API input item, for posting it back
+// via Builder.InputItems.
+func (out ComputerCallOutput) InputItem() any {
+	return struct {
+		Type string `json:"type"`
+		ComputerCallOutput
+	}{"computer_call_output", out}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Shell Types
 // ═══════════════════════════════════════════════════════════════════════════
@@ -577,6 +603,15 @@ type ShellCallOutput struct {
 	Output          []ShellCommandResult `json:"output"`
 }
 
+// InputItem returns out as a Responses API input item, for posting it back
+// via Builder.InputItems.
+func (out ShellCallOutput) InputItem() any {
+	return struct {
+		Type string `json:"type"`
+		ShellCallOutput
+	}{"shell_call_output", out}
+}
+
 // ShellCommandResult represents the result of a single shell command.
 type ShellCommandResult struct {
 	Stdout  string       `json:"stdout"`
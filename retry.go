@@ -15,16 +15,56 @@ import (
 // Retry Configuration
 // ═══════════════════════════════════════════════════════════════════════════
 
+// JitterMode selects the randomization strategy calculateBackoff applies to
+// the exponential backoff delay, set via RetryConfig.JitterMode. It's an
+// alternative to the amplitude-based Jitter field, for callers who want one
+// of the well-known backoff/jitter algorithms by name instead of tuning a
+// fraction themselves.
+type JitterMode string
+
+const (
+	// JitterModeDefault keeps the existing behavior: the amplitude-based
+	// Jitter field controls how much the delay is randomized, if at all.
+	JitterModeDefault JitterMode = ""
+
+	// JitterNone uses the capped exponential delay with no randomization.
+	// Useful for deterministic load tests.
+	JitterNone JitterMode = "none"
+
+	// JitterFull picks a random delay in [0, cappedDelay). Best for
+	// avoiding thundering herds, at the cost of some requests retrying
+	// almost immediately.
+	JitterFull JitterMode = "full"
+
+	// JitterEqual picks a random delay in [cappedDelay/2, cappedDelay).
+	// A middle ground between JitterNone and JitterFull: still spreads
+	// retries out, but keeps a backoff floor.
+	JitterEqual JitterMode = "equal"
+)
+
 // RetryConfig defines the strategy for retrying failed requests.
 // It supports exponential backoff, jitter, and selective retries based on error types.
 type RetryConfig struct {
-	MaxRetries    int           // Maximum number of retry attempts (default: 3)
-	InitialDelay  time.Duration // Delay before the first retry (default: 1s)
-	MaxDelay      time.Duration // Maximum delay between retries (default: 60s)
-	Multiplier    float64       // Exponential backoff multiplier (default: 2.0)
-	Jitter        float64       // Random jitter factor (0.0 - 1.0) to avoid thundering herd (default: 0.1)
-	RetryOnStatus []int         // List of HTTP status codes that trigger a retry
-	RetryOnErrors []string      // List of error substrings that trigger a retry
+	MaxRetries   int           // Maximum number of retry attempts (default: 3)
+	InitialDelay time.Duration // Delay before the first retry (default: 1s)
+	MaxDelay     time.Duration // Maximum delay between retries (default: 60s)
+	Multiplier   float64       // Exponential backoff multiplier (default: 2.0)
+	Jitter       float64       // Random jitter factor (0.0 - 1.0) to avoid thundering herd (default: 0.1)
+
+	// JitterMode, if set, picks one of the named backoff/jitter algorithms
+	// (none/full/equal) and overrides Jitter. Leave unset to keep using
+	// the amplitude-based Jitter field.
+	JitterMode JitterMode
+
+	RetryOnStatus []int    // List of HTTP status codes that trigger a retry
+	RetryOnErrors []string // List of error substrings that trigger a retry
+
+	// MaxElapsed caps the total wall-clock time WithRetry spends retrying,
+	// measured from its first attempt. Once the elapsed time would exceed
+	// MaxElapsed, WithRetry stops retrying and returns the last error
+	// instead of waiting out another backoff delay. Zero (the default)
+	// means no cap - only MaxRetries bounds the loop.
+	MaxElapsed time.Duration
 }
 
 // DefaultRetryConfig returns a sensible default configuration for most use cases.
@@ -81,6 +121,17 @@ func (b *Builder) NoRetry() *Builder {
 	return b
 }
 
+// OnRetry registers a callback invoked before each re-attempt, whether
+// retries are driven by RetryConfig/RetryWithBackoff (smart retry) or Retry
+// (legacy retry). attempt is the 1-based retry number (not counting the
+// initial attempt) and err is the error that triggered it, letting callers
+// surface progress like "retrying (attempt 2/3)..." to a UI instead of only
+// seeing the final result.
+func (b *Builder) OnRetry(fn func(attempt int, err error)) *Builder {
+	b.onRetry = fn
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Retry Logic
 // ═══════════════════════════════════════════════════════════════════════════
@@ -109,11 +160,20 @@ func calculateBackoff(config *RetryConfig, attempt int) time.Duration {
 		delay = float64(config.MaxDelay)
 	}
 
-	// Add jitter: delay * (1 ± jitter)
-	if config.Jitter > 0 {
-		jitterRange := delay * config.Jitter
-		jitter := (rand.Float64() * 2 * jitterRange) - jitterRange
-		delay += jitter
+	switch config.JitterMode {
+	case JitterNone:
+		// No randomization: use the capped exponential delay as-is.
+	case JitterFull:
+		delay = rand.Float64() * delay
+	case JitterEqual:
+		delay = delay/2 + rand.Float64()*(delay/2)
+	default:
+		// Amplitude-based jitter: delay * (1 ± Jitter)
+		if config.Jitter > 0 {
+			jitterRange := delay * config.Jitter
+			jitter := (rand.Float64() * 2 * jitterRange) - jitterRange
+			delay += jitter
+		}
 	}
 
 	// Ensure non-negative
@@ -179,6 +239,7 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 	}
 
 	var lastErr error
+	var elapsedExceeded bool
 	start := time.Now()
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
@@ -212,6 +273,16 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 		// Calculate delay
 		delay := calculateBackoff(config, attempt)
 
+		// Stop retrying once the next wait would push total elapsed time
+		// past MaxElapsed, rather than retrying into a caller's deadline.
+		if config.MaxElapsed > 0 && time.Since(start)+delay > config.MaxElapsed {
+			if Debug {
+				fmt.Printf("%s Not retrying: max elapsed time (%v) would be exceeded\n", colorRed("✗"), config.MaxElapsed)
+			}
+			elapsedExceeded = true
+			break
+		}
+
 		if Debug {
 			fmt.Printf("%s Retry %d/%d after %v (error: %v)\n",
 				colorYellow("↻"), attempt+1, config.MaxRetries, delay.Round(time.Millisecond), err)
@@ -230,6 +301,9 @@ func WithRetry[T any](ctx context.Context, config *RetryConfig, fn RetryFunc[T])
 			colorRed("✗"), config.MaxRetries, time.Since(start).Round(time.Millisecond))
 	}
 
+	if elapsedExceeded {
+		return zero, fmt.Errorf("max elapsed time (%v) exceeded: %w", config.MaxElapsed, lastErr)
+	}
 	return zero, fmt.Errorf("max retries (%d) exceeded: %w", config.MaxRetries, lastErr)
 }
 
@@ -290,6 +364,20 @@ func (c *RetryConfig) WithJitter(j float64) *RetryConfig {
 	return c
 }
 
+// WithMaxElapsed caps the total wall-clock time spent retrying. Zero (the
+// default) disables the cap.
+func (c *RetryConfig) WithMaxElapsed(d time.Duration) *RetryConfig {
+	c.MaxElapsed = d
+	return c
+}
+
+// WithJitterMode picks a named backoff/jitter algorithm (JitterNone,
+// JitterFull, or JitterEqual), overriding the amplitude-based Jitter field.
+func (c *RetryConfig) WithJitterMode(mode JitterMode) *RetryConfig {
+	c.JitterMode = mode
+	return c
+}
+
 // WithRetryOnStatus adds HTTP status codes that should trigger a retry.
 func (c *RetryConfig) WithRetryOnStatus(codes ...int) *RetryConfig {
 	c.RetryOnStatus = append(c.RetryOnStatus, codes...)
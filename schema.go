@@ -19,8 +19,10 @@ func (b *Builder) Schema(v any) *Builder {
 	return b
 }
 
-// Into sends a prompt and unmarshals the JSON response into target.
-// It enables JSON mode and strips any surrounding markdown code fences.
+// Into sends a prompt and unmarshals the JSON response into target. It
+// enables JSON mode and, unless StrictJSON was set, applies the same lenient
+// extraction AskJSON does (markdown fences, surrounding prose, trailing
+// commas) before decoding.
 func (b *Builder) Into(prompt string, target any) error {
 	// Generate schema from target type
 	schema := structToSchema(target)
@@ -31,8 +33,11 @@ func (b *Builder) Into(prompt string, target any) error {
 		return err
 	}
 
-	// Clean response (remove markdown if present)
-	resp = cleanJSONResponse(resp)
+	if b.strictJSONDecode {
+		resp = cleanJSONResponse(resp)
+	} else {
+		resp = lenientJSON(resp)
+	}
 
 	return json.Unmarshal([]byte(resp), target)
 }
@@ -56,6 +61,16 @@ func structToSchema(v any) map[string]any {
 	return typeToSchema(t)
 }
 
+// resolveSchema returns v as a JSON Schema map, passing it through unchanged
+// if it's already one (e.g. a hand-written schema or Builder.Schema(map))
+// and generating one via structToSchema otherwise.
+func resolveSchema(v any) map[string]any {
+	if m, ok := v.(map[string]any); ok {
+		return m
+	}
+	return structToSchema(v)
+}
+
 func typeToSchema(t reflect.Type) map[string]any {
 	switch t.Kind() {
 	case reflect.Struct:
@@ -156,6 +171,100 @@ func cleanJSONResponse(resp string) string {
 	return resp
 }
 
+// lenientJSON cleans markdown fences, then extracts the first balanced JSON
+// object or array from resp via a brace-matching scan (tolerating a leading
+// or trailing sentence of prose around it) and strips trailing commas before
+// the closing brace/bracket, for AskJSON/Into's default leniency. Returns
+// resp unchanged (just cleaned) if no balanced object/array is found.
+func lenientJSON(resp string) string {
+	resp = cleanJSONResponse(resp)
+	if balanced, ok := extractBalancedJSON(resp); ok {
+		resp = balanced
+	}
+	return stripTrailingCommas(resp)
+}
+
+// extractBalancedJSON scans s for the first '{' or '[' and returns the
+// substring up to its matching closing brace/bracket, respecting string
+// literals so braces inside quoted text don't throw off the count. ok is
+// false if s has no object/array start, or it's never closed.
+func extractBalancedJSON(s string) (string, bool) {
+	start := strings.IndexAny(s, "{[")
+	if start == -1 {
+		return "", false
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return s[start : i+1], true
+			}
+		}
+	}
+	return "", false
+}
+
+// stripTrailingCommas removes a comma that's immediately followed (modulo
+// whitespace) by a closing '}' or ']', which Go's json package otherwise
+// rejects outright even though it's a common model mistake.
+func stripTrailingCommas(s string) string {
+	var b strings.Builder
+	inString := false
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			b.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			b.WriteByte(c)
+			continue
+		}
+		if c == ',' {
+			j := i + 1
+			for j < len(s) && (s[j] == ' ' || s[j] == '\t' || s[j] == '\n' || s[j] == '\r') {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue // drop the trailing comma
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Quick Extraction Helpers
 // ═══════════════════════════════════════════════════════════════════════════
@@ -93,6 +93,97 @@ func TestInto_CleansMarkdownAndUnmarshals(t *testing.T) {
 	}
 }
 
+func TestInto_LenientlyExtractsJSONFromSurroundingProseAndTrailingCommas(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{JSON: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{
+				Content: "Sure, here's the analysis:\n{\"sentiment\":\"positive\",\"score\":0.9,}\nLet me know if you need anything else.",
+			}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI})
+
+	var out struct {
+		Sentiment string  `json:"sentiment"`
+		Score     float64 `json:"score"`
+	}
+
+	if err := b.Into("analyze", &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Sentiment != "positive" || out.Score != 0.9 {
+		t.Fatalf("unexpected parsed result: %+v", out)
+	}
+}
+
+func TestInto_StrictJSONRejectsSurroundingProse(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{JSON: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{
+				Content: "Sure, here's the analysis:\n{\"sentiment\":\"positive\",\"score\":0.9}",
+			}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).StrictJSON()
+
+	var out struct {
+		Sentiment string  `json:"sentiment"`
+		Score     float64 `json:"score"`
+	}
+
+	if err := b.Into("analyze", &out); err == nil {
+		t.Fatal("expected StrictJSON to reject a response with surrounding prose")
+	}
+}
+
+func TestAskJSON_LenientByDefaultStrictWhenOptedIn(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	content := "Here you go: {\"name\":\"alice\",\"age\":30,} Hope that helps!"
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{JSON: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: content}, nil
+		},
+	}
+
+	var lenient struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI})
+	if err := b.AskJSON("extract", &lenient); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lenient.Name != "alice" || lenient.Age != 30 {
+		t.Fatalf("unexpected result: %+v", lenient)
+	}
+
+	var strict struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	sb := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).StrictJSON()
+	if err := sb.AskJSON("extract", &strict); err == nil {
+		t.Fatal("expected StrictJSON to fail on prose-wrapped JSON with a trailing comma")
+	}
+}
+
 func TestIntoWithRetry_RetriesOnParseErrorAndAddsCorrectionMessages(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -176,6 +267,37 @@ func TestIntoWithRetry_RetriesOnStructValidation(t *testing.T) {
 	}
 }
 
+func TestClassifyLabel_RetriesWhenLabelOutsideAllowedSet(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	call := 0
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{JSON: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			call++
+			if call == 1 {
+				return &ProviderResponse{Content: `{"label":"spam"}`}, nil
+			}
+			return &ProviderResponse{Content: `{"label":"refund"}`}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI})
+
+	label, err := ClassifyLabel(b, "I want my money back", []string{"refund", "complaint", "question"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if label != "refund" {
+		t.Fatalf("expected label %q, got %q", "refund", label)
+	}
+	if call != 2 {
+		t.Fatalf("expected a retry after the out-of-set label, got %d calls", call)
+	}
+}
+
 func TestExtractors_CodeBlocksAndJSON(t *testing.T) {
 	resp := "text\n```python\nprint('hi')\n```\nmore\n```go\nfmt.Println(\"x\")\n```"
 	if got := ExtractCodeBlock(resp, "python"); !strings.Contains(got, "print('hi')") {
@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Server-Sent Events
+// ═══════════════════════════════════════════════════════════════════════════
+
+// sseReader decodes a Server-Sent Events stream per the spec: lines ending
+// in "\r\n" or "\n", multi-line "data:" fields accumulated (joined by "\n")
+// until a blank line marks the end of an event, and lines starting with
+// ':' ignored as comments. This replaces the naive "read one line, assume
+// it's one complete data: frame" approach, which breaks when a proxy
+// splits frames across reads or a provider emits multi-line data.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+// newSSEReader wraps r for Server-Sent Events decoding.
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next event's accumulated "data:" payload. It returns
+// io.EOF once the stream has ended with no event left to dispatch.
+func (s *sseReader) Next(providerName string) (string, error) {
+	var data [][]byte
+
+	for {
+		line, err := s.r.ReadBytes('\n')
+		line = bytes.TrimRight(line, "\r\n")
+
+		switch {
+		case len(line) == 0:
+			if len(data) > 0 {
+				return string(bytes.Join(data, []byte("\n"))), nil
+			}
+		case bytes.HasPrefix(line, []byte(":")):
+			if Debug {
+				fmt.Printf("%s [%s] SSE comment: %s\n", colorDim("·"), providerName, bytes.TrimPrefix(line, []byte(":")))
+			}
+		case bytes.HasPrefix(line, []byte("data:")):
+			data = append(data, bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" ")))
+		default:
+			// Other SSE fields (event:, id:, retry:) aren't used by these
+			// providers' APIs and are ignored.
+			if len(line) > 0 && Debug {
+				fmt.Printf("%s [%s] skipping unrecognized SSE line: %s\n", colorYellow("⚠"), providerName, line)
+			}
+		}
+
+		if err != nil {
+			if len(data) > 0 {
+				return string(bytes.Join(data, []byte("\n"))), nil
+			}
+			return "", err
+		}
+	}
+}
+
+// readWithContext runs a blocking read (fn) in a goroutine and races it
+// against ctx. If ctx is canceled or times out first, it closes closer to
+// unblock the pending read and returns ctx.Err() immediately rather than
+// waiting for the next chunk; the read goroutine's eventual result is
+// discarded. This is what lets streaming reads (sseReader.Next, Ollama's
+// NDJSON reader) honor context cancellation without each provider
+// reimplementing the race.
+func readWithContext[T any](ctx context.Context, closer io.Closer, fn func() (T, error)) (T, error) {
+	type result struct {
+		val T
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		val, err := fn()
+		ch <- result{val, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		closer.Close()
+		var zero T
+		return zero, ctx.Err()
+	case res := <-ch:
+		return res.val, res.err
+	}
+}
+
+// partialResponse wraps whatever text a stream accumulated before it failed,
+// so callers can salvage it alongside the error instead of losing it. It
+// returns nil if nothing was accumulated, matching the pre-error behavior of
+// returning a bare error for failures that happen before any content arrives.
+func partialResponse(content string) *ProviderResponse {
+	if content == "" {
+		return nil
+	}
+	return &ProviderResponse{Content: content}
+}
@@ -0,0 +1,76 @@
+package ai
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSSEReader_HandlesCRLFLineEndings(t *testing.T) {
+	r := newSSEReader(strings.NewReader("data: {\"a\":1}\r\n\r\ndata: {\"a\":2}\r\n\r\n"))
+
+	first, err := r.Next("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first != `{"a":1}` {
+		t.Errorf("expected first event %q, got %q", `{"a":1}`, first)
+	}
+
+	second, err := r.Next("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second != `{"a":2}` {
+		t.Errorf("expected second event %q, got %q", `{"a":2}`, second)
+	}
+}
+
+func TestSSEReader_AccumulatesMultiLineData(t *testing.T) {
+	r := newSSEReader(strings.NewReader("data: line one\ndata: line two\n\n"))
+
+	event, err := r.Next("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event != "line one\nline two" {
+		t.Errorf("expected joined multi-line data, got %q", event)
+	}
+}
+
+func TestSSEReader_SkipsCommentLines(t *testing.T) {
+	r := newSSEReader(strings.NewReader(": keep-alive\ndata: {\"a\":1}\n\n"))
+
+	event, err := r.Next("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event != `{"a":1}` {
+		t.Errorf("expected comment line to be skipped, got %q", event)
+	}
+}
+
+func TestSSEReader_ReturnsEOFAtEndOfStream(t *testing.T) {
+	r := newSSEReader(strings.NewReader("data: {\"a\":1}\n\n"))
+
+	if _, err := r.Next("test"); err != nil {
+		t.Fatalf("unexpected error on first event: %v", err)
+	}
+	if _, err := r.Next("test"); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestSSEReader_DispatchesTrailingEventWithoutBlankLine(t *testing.T) {
+	// A connection can be cut right after the last data: line, with no
+	// trailing blank line before EOF; the event should still be delivered.
+	r := newSSEReader(strings.NewReader("data: {\"a\":1}\n"))
+
+	event, err := r.Next("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event != `{"a":1}` {
+		t.Errorf("expected trailing event to be dispatched, got %q", event)
+	}
+}
@@ -9,6 +9,22 @@ import (
 // StreamCallback is a function called for each chunk of a streamed response.
 type StreamCallback func(chunk string)
 
+// ToolCallDelta carries a fragment of a tool call as it streams in, before
+// the call is complete. Index identifies which call it belongs to (a model
+// can stream several calls in parallel); ID and Name are typically only set
+// on the first fragment for a given Index, while ArgumentsDelta arrives
+// piecemeal across many fragments and must be concatenated by the caller.
+type ToolCallDelta struct {
+	Index          int
+	ID             string
+	Name           string
+	ArgumentsDelta string
+}
+
+// ToolCallDeltaCallback is invoked for each fragment of a tool call as it
+// streams in. See Builder.StreamWithToolDeltas.
+type ToolCallDeltaCallback func(delta ToolCallDelta)
+
 // Stream sends a request and prints the response chunks to stdout in real-time.
 // It is a convenience method for simple streaming to the console.
 func (b *Builder) Stream(prompt string) (string, error) {
@@ -21,7 +37,6 @@ func (b *Builder) Stream(prompt string) (string, error) {
 // It handles rate limiting, error checking, and optional debug output.
 // Returns the full concatenated response string upon completion.
 func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
-	msgs := b.buildMessages()
 	start := time.Now()
 
 	// Get the client to use
@@ -30,15 +45,18 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 		client = getDefaultClient()
 	}
 
+	msgs := b.buildMessages(client.providerType)
+
 	// Build provider request
 	req := &ProviderRequest{
-		Model:       string(b.model),
-		Messages:    msgs,
-		Temperature: b.temperature,
-		Thinking:    b.thinking,
-		Tools:       b.tools,
-		JSONMode:    b.jsonMode,
-		Stream:      true,
+		Model:                string(b.model),
+		Messages:             msgs,
+		Temperature:          b.temperature,
+		Thinking:             b.thinking,
+		ThinkingBudgetTokens: b.thinkingBudget,
+		Tools:                b.tools,
+		JSONMode:             b.jsonMode,
+		Stream:               true,
 	}
 
 	// Get context
@@ -75,6 +93,9 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 	waitForRateLimit()
 	resp, err := client.provider.SendStream(ctx, req, callback)
 	if err != nil {
+		if resp != nil {
+			return resp.Content, err
+		}
 		return "", err
 	}
 
@@ -91,6 +112,7 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 		Tokens:           resp.TotalTokens,
 		PromptTokens:     resp.PromptTokens,
 		CompletionTokens: resp.CompletionTokens,
+		FinishReason:     resp.FinishReason,
 	})
 
 	return resp.Content, nil
@@ -99,7 +121,6 @@ func (b *Builder) StreamResponse(callback StreamCallback) (string, error) {
 // StreamWithMeta sends a request, streams the response via callback, and returns full metadata.
 // This is useful when you need token usage stats or latency information along with the streamed content.
 func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error) {
-	msgs := b.buildMessages()
 	start := time.Now()
 
 	client := b.client
@@ -107,14 +128,17 @@ func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error)
 		client = getDefaultClient()
 	}
 
+	msgs := b.buildMessages(client.providerType)
+
 	req := &ProviderRequest{
-		Model:       string(b.model),
-		Messages:    msgs,
-		Temperature: b.temperature,
-		Thinking:    b.thinking,
-		Tools:       b.tools,
-		JSONMode:    b.jsonMode,
-		Stream:      true,
+		Model:                string(b.model),
+		Messages:             msgs,
+		Temperature:          b.temperature,
+		Thinking:             b.thinking,
+		ThinkingBudgetTokens: b.thinkingBudget,
+		Tools:                b.tools,
+		JSONMode:             b.jsonMode,
+		Stream:               true,
 	}
 
 	ctx := b.ctx
@@ -129,7 +153,76 @@ func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error)
 	waitForRateLimit()
 	resp, err := client.provider.SendStream(ctx, req, callback)
 	if err != nil {
-		return &ResponseMeta{Error: err, Model: b.model, Latency: time.Since(start)}, err
+		meta := &ResponseMeta{Error: err, Model: b.model, Latency: time.Since(start)}
+		if resp != nil {
+			meta.Content = resp.Content
+		}
+		return meta, err
+	}
+
+	meta := &ResponseMeta{
+		Content:          resp.Content,
+		Model:            b.model,
+		Latency:          time.Since(start),
+		Tokens:           resp.TotalTokens,
+		PromptTokens:     resp.PromptTokens,
+		CompletionTokens: resp.CompletionTokens,
+		FinishReason:     resp.FinishReason,
+	}
+
+	trackRequest(meta)
+	return meta, nil
+}
+
+// StreamWithToolDeltas behaves like StreamWithMeta, but additionally invokes
+// onToolDelta with each fragment of a tool call's name and arguments as it
+// streams in, before the call is complete - useful for a live
+// "the assistant is looking up X..." indicator. Providers that can't report
+// tool-call deltas incrementally (anything but ToolCallStreamer) fall back
+// to StreamWithMeta, and onToolDelta is never called.
+func (b *Builder) StreamWithToolDeltas(callback StreamCallback, onToolDelta ToolCallDeltaCallback) (*ResponseMeta, error) {
+	start := time.Now()
+
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+
+	streamer, ok := client.provider.(ToolCallStreamer)
+	if !ok {
+		return b.StreamWithMeta(callback)
+	}
+
+	msgs := b.buildMessages(client.providerType)
+
+	req := &ProviderRequest{
+		Model:                string(b.model),
+		Messages:             msgs,
+		Temperature:          b.temperature,
+		Thinking:             b.thinking,
+		ThinkingBudgetTokens: b.thinkingBudget,
+		Tools:                b.tools,
+		JSONMode:             b.jsonMode,
+		Stream:               true,
+	}
+
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if Debug {
+		printDebugRequest(b.model, msgs)
+	}
+
+	waitForRateLimit()
+	resp, err := streamer.SendStreamWithToolDeltas(ctx, req, callback, onToolDelta)
+	if err != nil {
+		meta := &ResponseMeta{Error: err, Model: b.model, Latency: time.Since(start)}
+		if resp != nil {
+			meta.Content = resp.Content
+		}
+		return meta, err
 	}
 
 	meta := &ResponseMeta{
@@ -139,6 +232,8 @@ func (b *Builder) StreamWithMeta(callback StreamCallback) (*ResponseMeta, error)
 		Tokens:           resp.TotalTokens,
 		PromptTokens:     resp.PromptTokens,
 		CompletionTokens: resp.CompletionTokens,
+		FinishReason:     resp.FinishReason,
+		ToolCalls:        resp.ToolCalls,
 	}
 
 	trackRequest(meta)
@@ -0,0 +1,138 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// PartialJSONCallback is called with the best-effort partial object decoded
+// from a streaming JSON-mode response so far. The value is typically a
+// map[string]any or []any, missing whatever fields haven't arrived yet.
+type PartialJSONCallback func(partial any)
+
+// StreamJSON streams a JSON-mode response, feeding the accumulated text
+// through a lenient incremental parser after every delta and invoking
+// callback with the best-effort partial object whenever enough has arrived
+// to parse - closing any braces/brackets still open and dropping a trailing
+// incomplete key or value. It builds on StreamResponse and Builder.JSON, so
+// a UI can render a form as structured data arrives instead of waiting for
+// the full completion.
+func (b *Builder) StreamJSON(callback PartialJSONCallback) (string, error) {
+	var buf strings.Builder
+	return b.JSON().StreamResponse(func(chunk string) {
+		buf.WriteString(chunk)
+		if partial, ok := parsePartialJSON(buf.String()); ok {
+			callback(partial)
+		}
+	})
+}
+
+// parsePartialJSON attempts a lenient parse of buf, an in-progress JSON
+// response, by closing any still-open strings/objects/arrays - dropping a
+// trailing incomplete key or value first if that's what it takes to get
+// something that parses. Returns ok=false if nothing of buf parses yet.
+func parsePartialJSON(buf string) (any, bool) {
+	s := strings.TrimSpace(cleanJSONResponse(buf))
+	if s == "" {
+		return nil, false
+	}
+
+	commas := topLevelCommaPositions(s)
+	for {
+		if result, ok := tryParseClosed(s); ok {
+			return result, true
+		}
+		if len(commas) == 0 {
+			return nil, false
+		}
+		// Drop the trailing, still-incomplete key or value by truncating at
+		// the last complete top-level comma and trying again.
+		cut := commas[len(commas)-1]
+		commas = commas[:len(commas)-1]
+		s = strings.TrimRight(s[:cut], " \t\n\r")
+	}
+}
+
+// tryParseClosed closes any unterminated string and any still-open
+// objects/arrays in s, then attempts to unmarshal the result.
+func tryParseClosed(s string) (any, bool) {
+	closed := closeJSON(s)
+	var result any
+	if err := json.Unmarshal([]byte(closed), &result); err != nil {
+		return nil, false
+	}
+	return result, true
+}
+
+// closeJSON appends whatever's needed to make a truncated JSON document
+// syntactically closed: a closing quote if s ends mid-string, then a
+// closing brace/bracket for each object/array still open.
+func closeJSON(s string) string {
+	var stack []byte
+	inString := false
+	escaped := false
+	for _, r := range s {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inString = false
+			}
+			continue
+		}
+		switch r {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+
+	closed := s
+	if inString {
+		closed += `"`
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		closed += string(stack[i])
+	}
+	return closed
+}
+
+// topLevelCommaPositions returns the byte offsets of every comma in s that
+// isn't inside a string, in order. Used to find where to truncate s back to
+// its last complete element when the full string doesn't parse even after
+// closeJSON.
+func topLevelCommaPositions(s string) []int {
+	var positions []int
+	inString := false
+	escaped := false
+	for i, r := range s {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inString = false
+			}
+			continue
+		}
+		switch r {
+		case '"':
+			inString = true
+		case ',':
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStreamJSON_InvokesCallbackWithBestEffortPartials(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	chunks := []string{`{"name":`, `"Ada`, `Lovelace","age":`, `36}`}
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Streaming: true},
+		streamFn: func(ctx context.Context, req *ProviderRequest, cb StreamCallback) (*ProviderResponse, error) {
+			var full string
+			for _, c := range chunks {
+				full += c
+				cb(c)
+			}
+			return &ProviderResponse{Content: full}, nil
+		},
+	}
+
+	var partials []any
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("describe Ada")
+
+	full, err := b.StreamJSON(func(partial any) {
+		partials = append(partials, partial)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if full != `{"name":"AdaLovelace","age":36}` {
+		t.Fatalf("unexpected full response: %q", full)
+	}
+	if len(partials) == 0 {
+		t.Fatal("expected at least one partial callback invocation")
+	}
+
+	last, ok := partials[len(partials)-1].(map[string]any)
+	if !ok {
+		t.Fatalf("expected final partial to be a map, got %T", partials[len(partials)-1])
+	}
+	if last["name"] != "AdaLovelace" || last["age"] != float64(36) {
+		t.Fatalf("unexpected final partial: %#v", last)
+	}
+}
+
+func TestParsePartialJSON_ClosesOpenObjectsAndDropsIncompleteTrailers(t *testing.T) {
+	cases := []struct {
+		in     string
+		wantOK bool
+		want   map[string]any
+	}{
+		{`{"a":1,"b":2}`, true, map[string]any{"a": 1.0, "b": 2.0}},
+		{`{"a":1,`, true, map[string]any{"a": 1.0}},
+		{`{"a":1,"b":`, true, map[string]any{"a": 1.0}},
+		{`{"a":"unterminated`, true, map[string]any{"a": "unterminated"}},
+		{``, false, nil},
+		{`{"a":`, false, nil},
+	}
+	for _, c := range cases {
+		got, ok := parsePartialJSON(c.in)
+		if ok != c.wantOK {
+			t.Errorf("parsePartialJSON(%q) ok = %v, want %v (got %#v)", c.in, ok, c.wantOK, got)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		m, isMap := got.(map[string]any)
+		if !isMap {
+			t.Errorf("parsePartialJSON(%q) = %#v, want a map", c.in, got)
+			continue
+		}
+		if len(m) != len(c.want) {
+			t.Errorf("parsePartialJSON(%q) = %#v, want %#v", c.in, m, c.want)
+			continue
+		}
+		for k, v := range c.want {
+			if m[k] != v {
+				t.Errorf("parsePartialJSON(%q)[%q] = %#v, want %#v", c.in, k, m[k], v)
+			}
+		}
+	}
+}
+
+func TestParsePartialJSON_ClosesOpenArray(t *testing.T) {
+	got, ok := parsePartialJSON(`[1,2,`)
+	if !ok {
+		t.Fatal("expected ok=true")
+	}
+	arr, isArr := got.([]any)
+	if !isArr || len(arr) != 2 {
+		t.Fatalf("unexpected result: %#v", got)
+	}
+}
@@ -0,0 +1,51 @@
+package ai
+
+import "sync"
+
+// SweepResult holds one temperature's response from Builder.Sweep.
+type SweepResult struct {
+	Temperature      float64
+	Content          string
+	Error            error
+	Tokens           int
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Sweep runs the builder's prompt once per temperature in temps, cloning the
+// builder so each run is independent, and collects each one's content and
+// token usage. Runs are made concurrently and results are returned in the
+// same order as temps, regardless of completion order.
+func (b *Builder) Sweep(temps ...float64) ([]SweepResult, error) {
+	results := make([]SweepResult, len(temps))
+	var wg sync.WaitGroup
+
+	for i, temp := range temps {
+		wg.Add(1)
+		go func(idx int, t float64) {
+			defer wg.Done()
+
+			bldr := b.Clone()
+			bldr.temperature = &t
+			meta := bldr.SendWithMeta()
+
+			results[idx] = SweepResult{
+				Temperature:      t,
+				Content:          meta.Content,
+				Error:            meta.Error,
+				Tokens:           meta.Tokens,
+				PromptTokens:     meta.PromptTokens,
+				CompletionTokens: meta.CompletionTokens,
+			}
+		}(i, temp)
+	}
+
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Error != nil {
+			return results, r.Error
+		}
+	}
+	return results, nil
+}
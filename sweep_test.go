@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestBuilderSweep_RunsEachTemperatureAndCollectsResults(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{
+				Content:      fmt.Sprintf("temp=%.1f", *req.Temperature),
+				TotalTokens:  10,
+				PromptTokens: 4,
+			}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hi")
+
+	results, err := b.Sweep(0.0, 0.5, 1.0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	for i, wantTemp := range []float64{0.0, 0.5, 1.0} {
+		if results[i].Temperature != wantTemp {
+			t.Errorf("result %d: expected temperature %.1f, got %.1f", i, wantTemp, results[i].Temperature)
+		}
+		wantContent := fmt.Sprintf("temp=%.1f", wantTemp)
+		if results[i].Content != wantContent {
+			t.Errorf("result %d: expected content %q, got %q", i, wantContent, results[i].Content)
+		}
+		if results[i].Tokens != 10 {
+			t.Errorf("result %d: expected 10 tokens, got %d", i, results[i].Tokens)
+		}
+	}
+}
+
+func TestBuilderSweep_ReturnsFirstErrorButKeepsAllResults(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			if *req.Temperature == 0.5 {
+				return nil, fmt.Errorf("boom")
+			}
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).User("hi")
+
+	results, err := b.Sweep(0.0, 0.5, 1.0)
+	if err == nil {
+		t.Fatal("expected an error from the failing temperature")
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results despite the error, got %d", len(results))
+	}
+	if results[1].Error == nil {
+		t.Error("expected result at index 1 to carry the error")
+	}
+}
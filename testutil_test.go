@@ -180,6 +180,93 @@ func (p *stubAudioProvider) STTRequests() []*STTRequest {
 	return out
 }
 
+type stubBackgroundProvider struct {
+	*stubProvider
+
+	mu                sync.Mutex
+	startBackgroundFn func(ctx context.Context, req *ProviderRequest) (string, error)
+	pollResponseFn    func(ctx context.Context, id string) (*ProviderResponse, string, error)
+}
+
+func (p *stubBackgroundProvider) StartBackground(ctx context.Context, req *ProviderRequest) (string, error) {
+	p.mu.Lock()
+	fn := p.startBackgroundFn
+	p.mu.Unlock()
+	if fn == nil {
+		return "", nil
+	}
+	return fn(ctx, req)
+}
+
+func (p *stubBackgroundProvider) PollResponse(ctx context.Context, id string) (*ProviderResponse, string, error) {
+	p.mu.Lock()
+	fn := p.pollResponseFn
+	p.mu.Unlock()
+	if fn == nil {
+		return &ProviderResponse{}, "completed", nil
+	}
+	return fn(ctx, id)
+}
+
+type stubImageProvider struct {
+	*stubProvider
+
+	mu            sync.Mutex
+	editImageFn   func(ctx context.Context, req *ImageEditRequest) (*ImageEditResponse, error)
+	imageVariFn   func(ctx context.Context, req *ImageVariationRequest) (*ImageEditResponse, error)
+	editReqs      []*ImageEditRequest
+	imageVariReqs []*ImageVariationRequest
+}
+
+func (p *stubImageProvider) EditImage(ctx context.Context, req *ImageEditRequest) (*ImageEditResponse, error) {
+	p.mu.Lock()
+	p.editReqs = append(p.editReqs, req)
+	fn := p.editImageFn
+	p.mu.Unlock()
+	if fn == nil {
+		return &ImageEditResponse{}, nil
+	}
+	return fn(ctx, req)
+}
+
+func (p *stubImageProvider) ImageVariation(ctx context.Context, req *ImageVariationRequest) (*ImageEditResponse, error) {
+	p.mu.Lock()
+	p.imageVariReqs = append(p.imageVariReqs, req)
+	fn := p.imageVariFn
+	p.mu.Unlock()
+	if fn == nil {
+		return &ImageEditResponse{}, nil
+	}
+	return fn(ctx, req)
+}
+
+type stubFileUploaderProvider struct {
+	*stubProvider
+
+	mu         sync.Mutex
+	uploadFn   func(ctx context.Context, name string, data []byte, mimeType string) (string, error)
+	uploadReqs []string // names of uploaded files, in call order
+}
+
+func (p *stubFileUploaderProvider) UploadFile(ctx context.Context, name string, data []byte, mimeType string) (string, error) {
+	p.mu.Lock()
+	p.uploadReqs = append(p.uploadReqs, name)
+	fn := p.uploadFn
+	p.mu.Unlock()
+	if fn == nil {
+		return "file-stub-id", nil
+	}
+	return fn(ctx, name, data, mimeType)
+}
+
+func (p *stubFileUploaderProvider) UploadedFiles() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]string, len(p.uploadReqs))
+	copy(out, p.uploadReqs)
+	return out
+}
+
 // Helps keep retry tests fast (no sleep).
 func noSleepRetryConfig(maxRetries int) *RetryConfig {
 	cfg := DefaultRetryConfig()
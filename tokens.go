@@ -0,0 +1,69 @@
+package ai
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Token Estimation
+// ═══════════════════════════════════════════════════════════════════════════
+
+// tiktokenLikeToken matches a run of alphanumerics (a "word") or a single
+// punctuation/symbol character, mirroring how BPE tokenizers like OpenAI's
+// tiktoken split text before merging sub-word pieces.
+var tiktokenLikeToken = regexp.MustCompile(`[A-Za-z0-9]+|[^\sA-Za-z0-9]`)
+
+// EstimateTokens estimates the number of tokens text would consume for
+// model m. OpenAI models use a tiktoken-style estimate (splitting into
+// word/punctuation runs, the way cl100k_base-family tokenizers do, rather
+// than a flat character ratio); every other provider falls back to the
+// ~4-characters-per-token heuristic used elsewhere in this package. This is
+// still an approximation, not an exact encoder, but is noticeably closer
+// for English prose and code than the flat heuristic alone.
+func EstimateTokens(text string, m Model) int {
+	if strings.HasPrefix(string(m), "openai/") {
+		return estimateTokensTiktokenStyle(text)
+	}
+	return estimateTokens(text)
+}
+
+// estimateTokensTiktokenStyle approximates cl100k-style tokenization: each
+// run of alphanumerics counts as one token per ~4 characters (long words get
+// split into sub-word pieces by BPE), and each punctuation/symbol character
+// counts as its own token.
+func estimateTokensTiktokenStyle(text string) int {
+	if text == "" {
+		return 0
+	}
+
+	tokens := 0
+	for _, tok := range tiktokenLikeToken.FindAllString(text, -1) {
+		if n := (len(tok) + 3) / 4; n > 0 {
+			tokens += n
+		} else {
+			tokens++
+		}
+	}
+	return tokens
+}
+
+// CountTokens estimates the token count of the request as currently built
+// (system prompt, context, and messages), using a model-appropriate
+// estimator for b.model. Use this to budget a request or decide whether to
+// trim history before sending, without making an API call.
+func (b *Builder) CountTokens() (int, error) {
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+	msgs := b.buildMessages(client.providerType)
+
+	estimator := func(s string) int { return EstimateTokens(s, b.model) }
+
+	total := 0
+	for _, m := range msgs {
+		total += estimateMessageTokensWith(m, estimator)
+	}
+	return total, nil
+}
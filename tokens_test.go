@@ -0,0 +1,37 @@
+package ai
+
+import "testing"
+
+func TestEstimateTokens_OpenAIUsesTiktokenStyle(t *testing.T) {
+	got := EstimateTokens("Hello, world!", ModelGPT4o)
+	if got <= 0 {
+		t.Fatalf("expected positive token estimate, got %d", got)
+	}
+	// "Hello" + "," + " " (skipped) + "world" + "!" = 4 runs; each 5-letter
+	// word counts as 2 tokens, so the estimate is 2+1+2+1 = 6.
+	want := estimateTokensTiktokenStyle("Hello, world!")
+	if got != want {
+		t.Fatalf("expected tiktoken-style estimate %d, got %d", want, got)
+	}
+}
+
+func TestEstimateTokens_NonOpenAIUsesCharHeuristic(t *testing.T) {
+	text := "Hello, world!"
+	got := EstimateTokens(text, ModelClaudeOpus)
+	want := estimateTokens(text)
+	if got != want {
+		t.Fatalf("expected char-heuristic estimate %d, got %d", want, got)
+	}
+}
+
+func TestBuilderCountTokens(t *testing.T) {
+	b := New(ModelGPT4o).System("You are helpful.").User("Hello there, how are you?")
+
+	count, err := b.CountTokens()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count <= 0 {
+		t.Fatalf("expected positive token count, got %d", count)
+	}
+}
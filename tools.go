@@ -3,6 +3,8 @@ package ai
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -39,6 +41,22 @@ type ToolResult struct {
 	Content    string `json:"content"`
 }
 
+// FunctionCallOutput is the Responses API analog of ToolResult: the output
+// you send back after executing a function tool call (ToolCall.ID), for
+// posting it back via Builder.InputItems.
+type FunctionCallOutput struct {
+	CallID string `json:"call_id"`
+	Output string `json:"output"`
+}
+
+// InputItem returns out as a Responses API input item.
+func (out FunctionCallOutput) InputItem() any {
+	return struct {
+		Type string `json:"type"`
+		FunctionCallOutput
+	}{"function_call_output", out}
+}
+
 // ToolHandler is a callback function that handles tool execution.
 // It takes a map of arguments and returns a string result or error.
 type ToolHandler func(args map[string]any) (string, error)
@@ -87,6 +105,48 @@ func (b *Builder) ToolDef(def ToolDef) *Builder {
 	return b
 }
 
+// ToolFromFile reads a tool definition from a JSON file shaped
+// {"name", "description", "parameters"} (parameters being a JSON Schema
+// object), registers it with handler, and appends it like ToolDef. This
+// decouples tool definitions from Go code, for schemas generated by
+// another service. If the file can't be read or doesn't parse as a valid
+// schema, the error is printed when Debug is enabled and the tool is
+// skipped rather than the builder failing.
+func (b *Builder) ToolFromFile(path string, handler ToolHandler) *Builder {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if Debug {
+			fmt.Printf("%s Error loading tool from %s: %v\n", colorRed("✗"), path, err)
+		}
+		return b
+	}
+
+	var spec struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		if Debug {
+			fmt.Printf("%s Error parsing tool schema from %s: %v\n", colorRed("✗"), path, err)
+		}
+		return b
+	}
+	if spec.Name == "" {
+		if Debug {
+			fmt.Printf("%s Tool schema in %s is missing a name\n", colorRed("✗"), path)
+		}
+		return b
+	}
+
+	return b.ToolDef(ToolDef{
+		Name:        spec.Name,
+		Description: spec.Description,
+		Parameters:  spec.Parameters,
+		Handler:     handler,
+	})
+}
+
 // Tools adds multiple tool definitions at once.
 func (b *Builder) Tools(tools ...Tool) *Builder {
 	b.tools = append(b.tools, tools...)
@@ -103,6 +163,33 @@ func (b *Builder) OnToolCall(name string, handler ToolHandler) *Builder {
 	return b
 }
 
+// RegisteredTools returns the tool definitions registered on this builder so
+// far, via Tool, ToolDef, Tools, or ToolFromFile. Named RegisteredTools
+// rather than Tools, since Tools is already the method that registers tool
+// definitions.
+func (b *Builder) RegisteredTools() []Tool {
+	out := make([]Tool, len(b.tools))
+	copy(out, b.tools)
+	return out
+}
+
+// HasToolHandler reports whether a handler is registered for the named
+// tool, via ToolDef or OnToolCall. Use this to validate the model's tool
+// calls before RunTools/RunToolsWith hits its "no handler for tool" error,
+// or to show a user which tools an agent can actually execute.
+func (b *Builder) HasToolHandler(name string) bool {
+	_, ok := b.toolHandlers[name]
+	return ok
+}
+
+// ForceTool forces the model to call the named tool instead of deciding on
+// its own whether and which tool to use. name must match a tool already
+// registered via Tool, ToolDef, or Tools.
+func (b *Builder) ForceTool(name string) *Builder {
+	b.forceTool = name
+	return b
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Tool Schema Helpers - DX-friendly parameter builders
 // ═══════════════════════════════════════════════════════════════════════════
@@ -235,7 +322,11 @@ func (r *ToolResponse) HasToolCalls() bool {
 // SendWithTools executes the request and returns a ToolResponse.
 // This is used for manual handling of tool calls. For automatic execution, use RunTools.
 func (b *Builder) SendWithTools() (*ToolResponse, error) {
-	msgs := b.buildMessages()
+	client := b.client
+	if client == nil {
+		client = getDefaultClient()
+	}
+	msgs := b.buildMessages(client.providerType)
 
 	content, resp, toolCalls, err := SendWithTools(b.model, msgs, b.tools, SendOptions{
 		Temperature: b.temperature,
@@ -257,10 +348,44 @@ func (b *Builder) SendWithTools() (*ToolResponse, error) {
 	return result, nil
 }
 
+// ToolErrorAction controls how RunToolsWith responds when a tool handler
+// errors, panics, or times out.
+type ToolErrorAction int
+
+const (
+	// ToolErrorFeedBack feeds the failure back to the model as the tool's
+	// result (role "tool"), so it can recover gracefully (e.g. retry with
+	// different arguments) instead of aborting the whole conversation.
+	// This is RunTools' default behavior.
+	ToolErrorFeedBack ToolErrorAction = iota
+
+	// ToolErrorAbort stops the loop and returns the failure immediately.
+	ToolErrorAbort
+)
+
+// RunToolsOptions configures RunToolsWith's handling of individual tool
+// calls.
+type RunToolsOptions struct {
+	// Timeout bounds each individual tool handler call. Zero (the default)
+	// means no timeout. A handler that doesn't return in time is abandoned;
+	// RunToolsWith moves on without waiting for it.
+	Timeout time.Duration
+
+	// OnToolError controls what happens when a handler returns an error,
+	// panics, or times out. Defaults to ToolErrorFeedBack.
+	OnToolError ToolErrorAction
+}
+
 // RunTools executes the request in an "agentic" loop.
 // It automatically executes tool calls and feeds the results back to the model.
 // It continues until the model provides a final text response or maxIterations is reached.
 func (b *Builder) RunTools(maxIterations int) (string, error) {
+	return b.RunToolsWith(maxIterations, RunToolsOptions{})
+}
+
+// RunToolsWith is RunTools with control over per-tool timeouts and how
+// handler failures are handled, via opts.
+func (b *Builder) RunToolsWith(maxIterations int, opts RunToolsOptions) (string, error) {
 	if maxIterations <= 0 {
 		maxIterations = 10 // sensible default
 	}
@@ -296,9 +421,12 @@ func (b *Builder) RunTools(maxIterations int) (string, error) {
 				fmt.Printf("%s Calling tool: %s(%v)\n", colorYellow("🔧"), tc.Function.Name, args)
 			}
 
-			// Execute handler
-			result, err := handler(args)
+			// Execute handler, bounded by opts.Timeout and recovered from panics
+			result, err := runToolHandler(handler, args, opts.Timeout)
 			if err != nil {
+				if opts.OnToolError == ToolErrorAbort {
+					return "", fmt.Errorf("tool %s failed: %w", tc.Function.Name, err)
+				}
 				result = fmt.Sprintf("Error: %v", err)
 			}
 
@@ -324,6 +452,39 @@ func (b *Builder) RunTools(maxIterations int) (string, error) {
 	return "", fmt.Errorf("max tool iterations (%d) reached", maxIterations)
 }
 
+// runToolHandler calls handler with args, recovering from panics and
+// enforcing timeout (when positive) by abandoning the handler goroutine
+// rather than waiting for it, since ToolHandler has no context to cancel.
+func runToolHandler(handler ToolHandler, args map[string]any, timeout time.Duration) (string, error) {
+	type outcome struct {
+		result string
+		err    error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("tool handler panicked: %v", r)}
+			}
+		}()
+		result, err := handler(args)
+		done <- outcome{result: result, err: err}
+	}()
+
+	if timeout <= 0 {
+		o := <-done
+		return o.result, o.err
+	}
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-time.After(timeout):
+		return "", fmt.Errorf("tool handler timed out after %s", timeout)
+	}
+}
+
 func truncate(s string, max int) string {
 	if len(s) <= max {
 		return s
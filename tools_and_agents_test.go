@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -140,6 +141,131 @@ func TestAgent_Run_UsesToolsAndExtractsFinalAnswer(t *testing.T) {
 	}
 }
 
+func TestRunToolsWith_TimeoutAbandonsHangingHandler(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	call := 0
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Tools: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			call++
+			if call == 1 {
+				return &ProviderResponse{
+					ToolCalls: []ToolCall{
+						{
+							ID:   "tc_1",
+							Type: "function",
+							Function: struct {
+								Name      string `json:"name"`
+								Arguments string `json:"arguments"`
+							}{Name: "slow", Arguments: `{}`},
+						},
+					},
+				}, nil
+			}
+			return &ProviderResponse{Content: "done", FinishReason: "stop"}, nil
+		},
+	}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	out, err := New(ModelGPT5).
+		Tool("slow", "Hangs", Params().Build()).
+		OnToolCall("slow", func(args map[string]any) (string, error) {
+			time.Sleep(200 * time.Millisecond)
+			return "too late", nil
+		}).
+		User("go").
+		RunToolsWith(5, RunToolsOptions{Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "done" {
+		t.Fatalf("unexpected final output: %q", out)
+	}
+}
+
+func TestRunToolsWith_RecoversFromHandlerPanic(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	call := 0
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Tools: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			call++
+			if call == 1 {
+				return &ProviderResponse{
+					ToolCalls: []ToolCall{
+						{
+							ID:   "tc_1",
+							Type: "function",
+							Function: struct {
+								Name      string `json:"name"`
+								Arguments string `json:"arguments"`
+							}{Name: "boom", Arguments: `{}`},
+						},
+					},
+				}, nil
+			}
+			return &ProviderResponse{Content: "recovered", FinishReason: "stop"}, nil
+		},
+	}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	out, err := New(ModelGPT5).
+		Tool("boom", "Panics", Params().Build()).
+		OnToolCall("boom", func(args map[string]any) (string, error) {
+			panic("kaboom")
+		}).
+		User("go").
+		RunToolsWith(5, RunToolsOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "recovered" {
+		t.Fatalf("unexpected final output: %q", out)
+	}
+}
+
+func TestRunToolsWith_AbortStopsLoopOnToolError(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{Tools: true},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{
+				ToolCalls: []ToolCall{
+					{
+						ID:   "tc_1",
+						Type: "function",
+						Function: struct {
+							Name      string `json:"name"`
+							Arguments string `json:"arguments"`
+						}{Name: "fails", Arguments: `{}`},
+					},
+				},
+			}, nil
+		},
+	}
+	setDefaultClientForTest(t, p, ProviderOpenAI)
+
+	_, err := New(ModelGPT5).
+		Tool("fails", "Fails", Params().Build()).
+		OnToolCall("fails", func(args map[string]any) (string, error) {
+			return "", fmt.Errorf("boom")
+		}).
+		User("go").
+		RunToolsWith(5, RunToolsOptions{OnToolError: ToolErrorAbort})
+	if err == nil {
+		t.Fatal("expected error when OnToolError is ToolErrorAbort")
+	}
+}
+
 func TestToolArguments_JSONUnmarshalErrorsAreHandled(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
@@ -0,0 +1,32 @@
+package ai
+
+import "testing"
+
+func TestBuilderRegisteredTools_ReflectsRegistrationsAndIsACopy(t *testing.T) {
+	b := New(ModelGPT5).Tool("get_weather", "Gets the weather", map[string]any{"type": "object"})
+
+	tools := b.RegisteredTools()
+	if len(tools) != 1 || tools[0].Function.Name != "get_weather" {
+		t.Fatalf("expected one registered tool named get_weather, got %+v", tools)
+	}
+
+	tools[0].Function.Name = "mutated"
+	if b.tools[0].Function.Name != "get_weather" {
+		t.Fatalf("expected RegisteredTools to return a copy, builder state was mutated")
+	}
+}
+
+func TestBuilderHasToolHandler(t *testing.T) {
+	b := New(ModelGPT5).
+		Tool("get_weather", "Gets the weather", map[string]any{"type": "object"}).
+		OnToolCall("get_weather", func(args map[string]any) (string, error) {
+			return "sunny", nil
+		})
+
+	if !b.HasToolHandler("get_weather") {
+		t.Fatal("expected HasToolHandler to report true for a registered handler")
+	}
+	if b.HasToolHandler("unknown_tool") {
+		t.Fatal("expected HasToolHandler to report false for a tool with no handler")
+	}
+}
@@ -0,0 +1,29 @@
+package ai
+
+import "fmt"
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Output Transforms
+// ═══════════════════════════════════════════════════════════════════════════
+
+// Transform registers a post-processor applied to the final response content,
+// after validation succeeds. Unlike validators, transforms always rewrite the
+// content rather than gate it; they run in the order they were added. Use
+// this to normalize model output (trim whitespace, dedent, fix code fences,
+// run a formatter) without wrapping every call site.
+func (b *Builder) Transform(fn func(string) (string, error)) *Builder {
+	b.transforms = append(b.transforms, fn)
+	return b
+}
+
+// runTransforms applies all registered transforms to content in order.
+func (b *Builder) runTransforms(content string) (string, error) {
+	for _, fn := range b.transforms {
+		transformed, err := fn(content)
+		if err != nil {
+			return content, fmt.Errorf("transform failed: %w", err)
+		}
+		content = transformed
+	}
+	return content, nil
+}
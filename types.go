@@ -1,5 +1,7 @@
 package ai
 
+import "encoding/json"
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Core Types
 // ═══════════════════════════════════════════════════════════════════════════
@@ -10,18 +12,72 @@ type Message struct {
 	Role    string `json:"role"`
 	Content any    `json:"content"` // string or []ContentPart for vision
 
+	// Name distinguishes multiple participants sharing the same Role (e.g.
+	// "Alice" and "Bob" both speaking as "user" in a multi-persona
+	// simulation), set via Builder.UserAs. OpenAI and OpenRouter send it;
+	// the other providers ignore it.
+	Name string `json:"name,omitempty"`
+
 	// Tool calling fields
 	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
 	ToolCallID string     `json:"tool_call_id,omitempty"`
 }
 
+// UnmarshalJSON decodes a Message, restoring Content as either a string or a
+// []ContentPart depending on its shape, since the field is typed as `any` to
+// support both plain text and multimodal messages.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var alias struct {
+		Role       string          `json:"role"`
+		Content    json.RawMessage `json:"content"`
+		Name       string          `json:"name,omitempty"`
+		ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+		ToolCallID string          `json:"tool_call_id,omitempty"`
+	}
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+
+	m.Role = alias.Role
+	m.Name = alias.Name
+	m.ToolCalls = alias.ToolCalls
+	m.ToolCallID = alias.ToolCallID
+
+	if len(alias.Content) == 0 || string(alias.Content) == "null" {
+		m.Content = nil
+		return nil
+	}
+
+	var asString string
+	if err := json.Unmarshal(alias.Content, &asString); err == nil {
+		m.Content = asString
+		return nil
+	}
+
+	var asParts []ContentPart
+	if err := json.Unmarshal(alias.Content, &asParts); err == nil {
+		m.Content = asParts
+		return nil
+	}
+
+	// Forward-compatible fallback for content shapes we don't special-case.
+	var generic any
+	if err := json.Unmarshal(alias.Content, &generic); err != nil {
+		return err
+	}
+	m.Content = generic
+	return nil
+}
+
 // ContentPart represents a segment of a multimodal message.
-// Used for combining text, images, and documents in a single message.
+// Used for combining text, images, documents, and files in a single message.
 type ContentPart struct {
-	Type     string       `json:"type"` // "text", "image_url", or "document"
-	Text     string       `json:"text,omitempty"`
-	ImageURL *ImageURL    `json:"image_url,omitempty"`
-	Document *DocumentRef `json:"document,omitempty"`
+	Type       string       `json:"type"` // "text", "image_url", "document", "file", or "input_audio"
+	Text       string       `json:"text,omitempty"`
+	ImageURL   *ImageURL    `json:"image_url,omitempty"`
+	Document   *DocumentRef `json:"document,omitempty"`
+	File       *FileRef     `json:"file,omitempty"`
+	InputAudio *InputAudio  `json:"input_audio,omitempty"`
 }
 
 // DocumentRef represents a reference to a document file (e.g., PDF).
@@ -39,6 +95,13 @@ type ImageURL struct {
 	Detail string `json:"detail,omitempty"` // "auto", "low", "high"
 }
 
+// InputAudio represents audio embedded in a multimodal message, for
+// audio-capable chat models.
+type InputAudio struct {
+	Data   string `json:"data"`   // base64-encoded audio
+	Format string `json:"format"` // "wav", "mp3"
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Thinking Level (Reasoning Effort)
 // ═══════════════════════════════════════════════════════════════════════════
@@ -56,6 +119,60 @@ const (
 	ThinkingHigh    ThinkingLevel = "high"
 )
 
+// ═══════════════════════════════════════════════════════════════════════════
+// Safety Settings (Gemini)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// SafetyCategory is a Gemini content-safety harm category.
+type SafetyCategory string
+
+const (
+	SafetyHarassment SafetyCategory = "HARM_CATEGORY_HARASSMENT"
+	SafetyHateSpeech SafetyCategory = "HARM_CATEGORY_HATE_SPEECH"
+	SafetySexual     SafetyCategory = "HARM_CATEGORY_SEXUALLY_EXPLICIT"
+	SafetyDangerous  SafetyCategory = "HARM_CATEGORY_DANGEROUS_CONTENT"
+)
+
+// SafetyThreshold controls how aggressively Gemini blocks a SafetyCategory,
+// from blocking almost nothing to blocking anything with a nonzero risk.
+type SafetyThreshold string
+
+const (
+	SafetyBlockNone   SafetyThreshold = "BLOCK_NONE"
+	SafetyBlockLow    SafetyThreshold = "BLOCK_LOW_AND_ABOVE"
+	SafetyBlockMedium SafetyThreshold = "BLOCK_MEDIUM_AND_ABOVE"
+	SafetyBlockHigh   SafetyThreshold = "BLOCK_ONLY_HIGH"
+)
+
+// SafetySetting overrides Gemini's default blocking threshold for one harm
+// category, set via Builder.SafetySettings. Ignored by providers other than
+// Google.
+type SafetySetting struct {
+	Category  SafetyCategory
+	Threshold SafetyThreshold
+}
+
+// ContextPlacement controls where Builder.Context/ContextString/ContextWith
+// injected file content is placed relative to the rest of the request, set
+// via Builder.ContextPlacement.
+type ContextPlacement string
+
+const (
+	// ContextPlacementDefault keeps the existing behavior: context is
+	// appended to the end of the system prompt, after other system
+	// instructions.
+	ContextPlacementDefault ContextPlacement = ""
+
+	// ContextBefore puts context at the very top of the system prompt,
+	// ahead of other system instructions.
+	ContextBefore ContextPlacement = "before"
+
+	// ContextAfter puts context in a separate trailing user message,
+	// after the system prompt and conversation history, so the documents
+	// are the last thing in the request.
+	ContextAfter ContextPlacement = "after"
+)
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Request Types (for legacy compatibility)
 // ═══════════════════════════════════════════════════════════════════════════
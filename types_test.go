@@ -0,0 +1,56 @@
+package ai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessage_JSONRoundTrip_PreservesContentPartType(t *testing.T) {
+	msg := Message{
+		Role: "user",
+		Content: []ContentPart{
+			{Type: "text", Text: "What's in this image?"},
+			{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/cat.png"}},
+		},
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	parts, ok := decoded.Content.([]ContentPart)
+	if !ok {
+		t.Fatalf("expected Content to round-trip as []ContentPart, got %T", decoded.Content)
+	}
+	if len(parts) != 2 || parts[0].Text != "What's in this image?" {
+		t.Errorf("text part did not round-trip correctly: %+v", parts)
+	}
+	if parts[1].ImageURL == nil || parts[1].ImageURL.URL != "https://example.com/cat.png" {
+		t.Errorf("image part did not round-trip correctly: %+v", parts)
+	}
+}
+
+func TestMessage_JSONRoundTrip_PreservesPlainString(t *testing.T) {
+	msg := Message{Role: "user", Content: "hello there"}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	content, ok := decoded.Content.(string)
+	if !ok || content != "hello there" {
+		t.Errorf("expected Content to round-trip as string %q, got %T %v", "hello there", decoded.Content, decoded.Content)
+	}
+}
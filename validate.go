@@ -365,8 +365,10 @@ func (b *Builder) ConciseResponse(maxWords int) *Builder {
 	return b.NoEmptyResponse().WordCount(1, maxWords)
 }
 
-// StrictJSON ensures valid JSON output
+// StrictJSON ensures valid JSON output, and opts AskJSON/Into back into
+// exact decoding instead of their default lenient brace-matching extraction.
 func (b *Builder) StrictJSON() *Builder {
+	b.strictJSONDecode = true
 	return b.JSON().MustBeJSON()
 }
 
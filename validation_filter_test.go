@@ -2,6 +2,7 @@ package ai
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -52,6 +53,52 @@ func TestBuilder_WithFilter_TransformsContent(t *testing.T) {
 	}
 }
 
+func TestBuilder_Transform_AppliesInOrderAfterValidation(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "  Hello  "}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Transform(func(s string) (string, error) { return strings.TrimSpace(s), nil }).
+		Transform(func(s string) (string, error) { return strings.ToUpper(s), nil })
+
+	meta := b.User("hi").SendWithMeta()
+	if meta.Error != nil {
+		t.Fatalf("unexpected error: %v", meta.Error)
+	}
+	if meta.Content != "HELLO" {
+		t.Fatalf("expected transformed content 'HELLO', got %q", meta.Content)
+	}
+}
+
+func TestBuilder_Transform_ErrorIsSurfaced(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		caps: ProviderCapabilities{},
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "hello"}, nil
+		},
+	}
+
+	b := New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}).
+		Transform(func(s string) (string, error) { return "", fmt.Errorf("boom") })
+
+	meta := b.User("hi").SendWithMeta()
+	if meta.Error == nil {
+		t.Fatalf("expected transform error to be surfaced")
+	}
+}
+
 func TestBuilder_StrictJSON_ValidatesJSONEvenWithFences(t *testing.T) {
 	cleanup := withTestGlobals(t)
 	defer cleanup()
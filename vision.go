@@ -41,6 +41,8 @@ func (b *Builder) Image(path string) *Builder {
 
 // ImageWithDetail adds a local image file with a specific detail level.
 func (b *Builder) ImageWithDetail(path string, detail ImageDetail) *Builder {
+	validateImageDetail(detail)
+
 	dataURI, err := fileToDataURI(path)
 	if err != nil {
 		fmt.Printf("%s Error loading image %s: %v\n", colorRed("✗"), path, err)
@@ -62,6 +64,8 @@ func (b *Builder) ImageURL(url string) *Builder {
 
 // ImageURLWithDetail adds a remote image URL with a specific detail level.
 func (b *Builder) ImageURLWithDetail(url string, detail ImageDetail) *Builder {
+	validateImageDetail(detail)
+
 	b.images = append(b.images, ImageInput{
 		URL:    url,
 		Detail: string(detail),
@@ -92,6 +96,19 @@ func (b *Builder) Images(paths ...string) *Builder {
 // Internal Helpers
 // ═══════════════════════════════════════════════════════════════════════════
 
+// validateImageDetail warns in Debug if detail isn't one of the values the
+// API actually accepts ("auto", "low", "high"), since an unrecognized value
+// is silently ignored by the provider rather than rejected.
+func validateImageDetail(detail ImageDetail) {
+	switch detail {
+	case ImageDetailAuto, ImageDetailLow, ImageDetailHigh, "":
+		return
+	}
+	if Debug {
+		fmt.Printf("%s Unknown image detail %q, expected \"auto\", \"low\", or \"high\"\n", colorYellow("⚠"), detail)
+	}
+}
+
 // fileToDataURI reads a file and converts it to a base64 data URI.
 func fileToDataURI(path string) (string, error) {
 	data, err := os.ReadFile(path)
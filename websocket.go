@@ -0,0 +1,288 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// WebSocket Client (RFC 6455, minimal)
+// ═══════════════════════════════════════════════════════════════════════════
+
+// wsOpcode identifies a WebSocket frame's payload type.
+type wsOpcode byte
+
+const (
+	wsOpText   wsOpcode = 0x1
+	wsOpBinary wsOpcode = 0x2
+	wsOpClose  wsOpcode = 0x8
+	wsOpPing   wsOpcode = 0x9
+	wsOpPong   wsOpcode = 0xA
+)
+
+// wsMaxFrameSize bounds the payload length readFrame will allocate for.
+// The extended-length header can claim up to 2^63-1 bytes; without a cap, a
+// malformed or hostile frame from the server would trigger an enormous
+// allocation before any validation runs. The Realtime API's JSON events are
+// nowhere near this size in practice.
+const wsMaxFrameSize = 16 << 20 // 16 MiB
+
+// wsConn is a minimal RFC 6455 WebSocket client connection: enough to dial,
+// exchange unfragmented text/binary frames, and respond to pings. It does
+// not support extensions (e.g. compression) or fragmented messages, which
+// the Realtime API's JSON event protocol doesn't need.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+// dialWebSocket opens a connection to urlStr (TLS for scheme "wss", plain
+// TCP for "ws") and performs the WebSocket upgrade handshake, sending extraHeaders (e.g. Authorization)
+// on the initial HTTP request.
+func dialWebSocket(ctx context.Context, urlStr string, extraHeaders http.Header) (*wsConn, error) {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: invalid URL: %w", err)
+	}
+	if u.Scheme != "wss" && u.Scheme != "ws" {
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+
+	host := u.Host
+	if !strings.Contains(host, ":") {
+		if u.Scheme == "wss" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+
+	dialer := net.Dialer{}
+	rawConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("websocket: dial failed: %w", err)
+	}
+
+	var conn net.Conn = rawConn
+	if u.Scheme == "wss" {
+		conn = tls.Client(rawConn, &tls.Config{ServerName: u.Hostname()})
+	}
+
+	key, err := wsGenerateKey()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: failed to generate key: %w", err)
+	}
+
+	requestURI := u.Path
+	if requestURI == "" {
+		requestURI = "/"
+	}
+	if u.RawQuery != "" {
+		requestURI += "?" + u.RawQuery
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "GET %s HTTP/1.1\r\n", requestURI)
+	fmt.Fprintf(&sb, "Host: %s\r\n", u.Host)
+	sb.WriteString("Upgrade: websocket\r\n")
+	sb.WriteString("Connection: Upgrade\r\n")
+	fmt.Fprintf(&sb, "Sec-WebSocket-Key: %s\r\n", key)
+	sb.WriteString("Sec-WebSocket-Version: 13\r\n")
+	for name, values := range extraHeaders {
+		for _, v := range values {
+			fmt.Fprintf(&sb, "%s: %s\r\n", name, v)
+		}
+	}
+	sb.WriteString("\r\n")
+
+	if _, err := conn.Write([]byte(sb.String())); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake write failed: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, &http.Request{Method: "GET"})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake read failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(resp.Body)
+		conn.Close()
+		return nil, fmt.Errorf("websocket: handshake rejected: %s: %s", resp.Status, string(body))
+	}
+
+	wantAccept := wsAcceptKey(key)
+	if resp.Header.Get("Sec-WebSocket-Accept") != wantAccept {
+		conn.Close()
+		return nil, fmt.Errorf("websocket: invalid Sec-WebSocket-Accept from server")
+	}
+
+	return &wsConn{conn: conn, br: br}, nil
+}
+
+// wsGenerateKey returns a random base64-encoded 16-byte Sec-WebSocket-Key.
+func wsGenerateKey() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// wsAcceptKey computes the Sec-WebSocket-Accept value the server must
+// return for a given Sec-WebSocket-Key, per RFC 6455 section 1.3.
+func wsAcceptKey(key string) string {
+	const magic = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	h := sha1.Sum([]byte(key + magic))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// WriteText sends data as a single unfragmented, masked text frame.
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// WriteClose sends a close frame with the given status code.
+func (c *wsConn) WriteClose(code uint16) error {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, code)
+	return c.writeFrame(wsOpClose, payload)
+}
+
+// writeFrame writes a single unfragmented frame. Per RFC 6455, frames sent
+// by a client must be masked.
+func (c *wsConn) writeFrame(op wsOpcode, payload []byte) error {
+	var header []byte
+	header = append(header, 0x80|byte(op)) // FIN=1, opcode
+
+	n := len(payload)
+	switch {
+	case n <= 125:
+		header = append(header, 0x80|byte(n)) // MASK=1
+	case n <= 0xFFFF:
+		header = append(header, 0x80|126)
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(n))
+		header = append(header, ext...)
+	default:
+		header = append(header, 0x80|127)
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(n))
+		header = append(header, ext...)
+	}
+
+	mask := make([]byte, 4)
+	if _, err := rand.Read(mask); err != nil {
+		return fmt.Errorf("websocket: failed to generate mask: %w", err)
+	}
+	header = append(header, mask...)
+
+	masked := make([]byte, n)
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+
+	if _, err := c.conn.Write(header); err != nil {
+		return fmt.Errorf("websocket: write failed: %w", err)
+	}
+	if _, err := c.conn.Write(masked); err != nil {
+		return fmt.Errorf("websocket: write failed: %w", err)
+	}
+	return nil
+}
+
+// ReadMessage reads the next complete message, transparently answering
+// pings with pongs and looping past them. It returns wsOpClose when the
+// server closes the connection.
+func (c *wsConn) ReadMessage() (wsOpcode, []byte, error) {
+	for {
+		op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		default:
+			return op, payload, nil
+		}
+	}
+}
+
+// readFrame reads a single frame. Server-to-client frames are never masked.
+func (c *wsConn) readFrame() (wsOpcode, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, header); err != nil {
+		return 0, nil, err
+	}
+
+	op := wsOpcode(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > wsMaxFrameSize {
+		return 0, nil, fmt.Errorf("websocket: frame length %d exceeds max %d", length, wsMaxFrameSize)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	return op, payload, nil
+}
+
+// Close closes the underlying connection without sending a close frame.
+// Callers that want a clean shutdown should send WriteClose first.
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,124 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestWsAcceptKey_MatchesRFC6455Example(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	got := wsAcceptKey("dGhlIHNhbXBsZSBub25jZQ==")
+	want := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestWsConn_WriteTextAndReadMessage_RoundTrip(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	a := &wsConn{conn: c1, br: bufio.NewReader(c1)}
+	b := &wsConn{conn: c2, br: bufio.NewReader(c2)}
+
+	go func() {
+		if err := a.WriteText([]byte("hello")); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	op, payload, err := b.ReadMessage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op != wsOpText {
+		t.Fatalf("expected text opcode, got %v", op)
+	}
+	if string(payload) != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", payload)
+	}
+}
+
+func TestWsConn_ReadMessage_AutoRepliesToPing(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	a := &wsConn{conn: c1, br: bufio.NewReader(c1)}
+	b := &wsConn{conn: c2, br: bufio.NewReader(c2)}
+
+	type pongResult struct {
+		op      wsOpcode
+		payload []byte
+		err     error
+	}
+	pongCh := make(chan pongResult, 1)
+
+	// b's auto-pong write (inside ReadMessage, below) blocks on the pipe
+	// until something reads it, so that read must happen concurrently
+	// rather than after ReadMessage returns.
+	go func() {
+		op, payload, err := a.readFrame()
+		pongCh <- pongResult{op, payload, err}
+	}()
+
+	go func() {
+		if err := a.writeFrame(wsOpPing, []byte("ping-data")); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := a.WriteText([]byte("after-ping")); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	// b should transparently skip the ping (replying with a pong on its
+	// own connection) and surface the text message that follows it.
+	op, payload, err := b.ReadMessage()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op != wsOpText || string(payload) != "after-ping" {
+		t.Fatalf("expected text %q, got op=%v payload=%q", "after-ping", op, payload)
+	}
+
+	pong := <-pongCh
+	if pong.err != nil {
+		t.Fatalf("unexpected error reading pong: %v", pong.err)
+	}
+	if pong.op != wsOpPong || string(pong.payload) != "ping-data" {
+		t.Fatalf("expected pong echoing ping data, got op=%v payload=%q", pong.op, pong.payload)
+	}
+}
+
+func TestWsConn_ReadFrame_RejectsOversizedFrameLength(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	b := &wsConn{conn: c2, br: bufio.NewReader(c2)}
+
+	// Hand-craft an unmasked server-to-client frame header claiming a
+	// payload length of 2^40 bytes via the 8-byte extended-length field,
+	// without ever writing that much actual payload.
+	header := []byte{0x82, 127}
+	ext := make([]byte, 8)
+	binary.BigEndian.PutUint64(ext, 1<<40)
+	header = append(header, ext...)
+
+	go func() {
+		_, _ = c1.Write(header)
+	}()
+
+	_, _, err := b.readFrame()
+	if err == nil {
+		t.Fatal("expected readFrame to reject an oversized frame length")
+	}
+	if !strings.Contains(err.Error(), "exceeds max") {
+		t.Fatalf("expected an exceeds-max error, got %v", err)
+	}
+}
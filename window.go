@@ -0,0 +1,160 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ═══════════════════════════════════════════════════════════════════════════
+// Conversation Window Management
+// ═══════════════════════════════════════════════════════════════════════════
+
+// WindowStrategy determines how Conversation.TrimToTokens drops history once
+// it exceeds the token budget.
+type WindowStrategy string
+
+const (
+	// WindowDropOldest removes the oldest turns first (the default).
+	WindowDropOldest WindowStrategy = "drop-oldest"
+	// WindowKeepSystem behaves like WindowDropOldest but always preserves the
+	// very first message in history (commonly a pinned instruction/anchor turn).
+	WindowKeepSystem WindowStrategy = "keep-system"
+	// WindowSummarize compresses the oldest turns into a single system note by
+	// asking the model to summarize them, instead of discarding them outright.
+	WindowSummarize WindowStrategy = "summarize"
+)
+
+// WithWindowStrategy sets the strategy used by TrimToTokens when the
+// conversation exceeds its token budget. Defaults to WindowDropOldest.
+func (c *Conversation) WithWindowStrategy(strategy WindowStrategy) *Conversation {
+	c.windowStrategy = strategy
+	return c
+}
+
+// EstimatedTokens returns a rough estimate of the token count of the system
+// prompt plus the conversation history, using a ~4-characters-per-token
+// heuristic. It does not call the model or a real tokenizer.
+func (c *Conversation) EstimatedTokens() int {
+	total := estimateTokens(c.builder.system)
+	for _, m := range c.history {
+		total += estimateMessageTokens(m)
+	}
+	return total
+}
+
+// TrimToTokens reduces the conversation history until its EstimatedTokens is
+// at or below max, using the configured WindowStrategy (WithWindowStrategy).
+// It is a no-op if the conversation is already within budget. It returns nil
+// while still over max when nothing more can be dropped (the system prompt,
+// plus the first message under WindowKeepSystem, alone exceeds max) or when
+// WindowSummarize is down to two messages.
+func (c *Conversation) TrimToTokens(max int) error {
+	if c.EstimatedTokens() <= max {
+		return nil
+	}
+
+	switch c.windowStrategy {
+	case WindowSummarize:
+		return c.summarizeOldest(max)
+	case WindowKeepSystem:
+		return c.dropOldest(max, true)
+	default:
+		return c.dropOldest(max, false)
+	}
+}
+
+// dropOldest removes messages from the front of history until the estimated
+// token count is within max. If keepFirst is true, the very first message is
+// never dropped.
+func (c *Conversation) dropOldest(max int, keepFirst bool) error {
+	start := 0
+	if keepFirst && len(c.history) > 0 {
+		start = 1
+	}
+	for c.EstimatedTokens() > max && len(c.history) > start {
+		c.history = append(c.history[:start], c.history[start+1:]...)
+	}
+	return nil
+}
+
+// summarizeOldest repeatedly compresses the oldest half of history into a
+// single system-role summary note until the budget is met. It bails with an
+// error if a compression pass fails to shrink history any further, rather
+// than looping forever on a tail that alone exceeds max.
+func (c *Conversation) summarizeOldest(max int) error {
+	for c.EstimatedTokens() > max && len(c.history) > 2 {
+		before := len(c.history)
+		if _, err := c.compressOldestHalf(c.builder.model); err != nil {
+			return err
+		}
+		if len(c.history) >= before {
+			return fmt.Errorf("summarize conversation: unable to shrink history below %d messages, but still over token budget", len(c.history))
+		}
+	}
+	return nil
+}
+
+// compressOldestHalf summarizes the oldest half of history into a single
+// system-role note, generated using model, and reports how many turns were
+// compressed.
+func (c *Conversation) compressOldestHalf(model Model) (int, error) {
+	cut := len(c.history) / 2
+	if cut < 1 {
+		cut = 1
+	}
+
+	var transcript strings.Builder
+	for _, m := range c.history[:cut] {
+		if text, ok := m.Content.(string); ok {
+			fmt.Fprintf(&transcript, "%s: %s\n", m.Role, text)
+		}
+	}
+
+	summary, err := c.builder.Clone().
+		Model(model).
+		System("Summarize the following conversation turns concisely, preserving important facts, decisions, and open questions.").
+		Ask(transcript.String())
+	if err != nil {
+		return 0, fmt.Errorf("summarize conversation: %w", err)
+	}
+
+	note := Message{Role: "system", Content: "Earlier conversation summary: " + summary}
+	c.history = append([]Message{note}, c.history[cut:]...)
+	return cut, nil
+}
+
+// estimateTokens approximates the token count of a string using a
+// ~4-characters-per-token heuristic.
+func estimateTokens(s string) int {
+	if s == "" {
+		return 0
+	}
+	return (len(s) + 3) / 4
+}
+
+// estimateMessageTokens approximates the token count of a single message,
+// accounting for multimodal content parts.
+func estimateMessageTokens(m Message) int {
+	return estimateMessageTokensWith(m, estimateTokens)
+}
+
+// estimateMessageTokensWith is like estimateMessageTokens but delegates text
+// counting to estimator, so callers (e.g. CountTokens) can plug in a
+// model-aware estimator instead of the flat ~4-chars-per-token heuristic.
+func estimateMessageTokensWith(m Message, estimator func(string) int) int {
+	switch content := m.Content.(type) {
+	case string:
+		return estimator(content)
+	case []ContentPart:
+		total := 0
+		for _, p := range content {
+			total += estimator(p.Text)
+			if p.ImageURL != nil {
+				total += 85 // flat per-image overhead, rough heuristic
+			}
+		}
+		return total
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConversationEstimatedTokens(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "user", Content: "Hello there"},
+		},
+	}
+
+	if got := chat.EstimatedTokens(); got <= 0 {
+		t.Errorf("expected positive token estimate, got %d", got)
+	}
+}
+
+func TestConversationTrimToTokensDropOldest(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "user", Content: "first message here"},
+			{Role: "assistant", Content: "first response here"},
+			{Role: "user", Content: "second message here"},
+			{Role: "assistant", Content: "second response here"},
+		},
+	}
+
+	if err := chat.TrimToTokens(5); err != nil {
+		t.Fatalf("TrimToTokens failed: %v", err)
+	}
+
+	if len(chat.history) == 0 {
+		t.Fatal("expected some history to survive trimming")
+	}
+	if chat.history[len(chat.history)-1].Content != "second response here" {
+		t.Errorf("expected the most recent turn to survive, got %v", chat.history[len(chat.history)-1].Content)
+	}
+}
+
+func TestConversationTrimToTokensKeepSystem(t *testing.T) {
+	chat := (&Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "user", Content: "anchor instruction that should stick around"},
+			{Role: "assistant", Content: "ack"},
+			{Role: "user", Content: "filler turn one"},
+			{Role: "assistant", Content: "filler response one"},
+		},
+	}).WithWindowStrategy(WindowKeepSystem)
+
+	if err := chat.TrimToTokens(3); err != nil {
+		t.Fatalf("TrimToTokens failed: %v", err)
+	}
+
+	if len(chat.history) == 0 || chat.history[0].Content != "anchor instruction that should stick around" {
+		t.Errorf("expected first message to be preserved, got %+v", chat.history)
+	}
+}
+
+func TestConversationTrimToTokensNoOp(t *testing.T) {
+	chat := &Conversation{
+		builder: New(ModelGPT5),
+		history: []Message{
+			{Role: "user", Content: "hi"},
+		},
+	}
+
+	if err := chat.TrimToTokens(1000); err != nil {
+		t.Fatalf("TrimToTokens failed: %v", err)
+	}
+	if len(chat.history) != 1 {
+		t.Error("history should be unchanged when already within budget")
+	}
+}
+
+func TestConversationTrimToTokensSummarize(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "ok"}, nil
+		},
+	}
+	chat := (&Conversation{
+		builder: New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}),
+		history: []Message{
+			{Role: "user", Content: "first message here"},
+			{Role: "assistant", Content: "first response here"},
+			{Role: "user", Content: "second message here"},
+			{Role: "assistant", Content: "second response here"},
+		},
+	}).WithWindowStrategy(WindowSummarize)
+
+	if err := chat.TrimToTokens(19); err != nil {
+		t.Fatalf("TrimToTokens failed: %v", err)
+	}
+
+	if len(chat.history) == 0 || chat.history[0].Role != "system" {
+		t.Fatalf("expected a leading system summary note, got %+v", chat.history)
+	}
+	if chat.history[0].Content != "Earlier conversation summary: ok" {
+		t.Errorf("unexpected summary note content: %v", chat.history[0].Content)
+	}
+}
+
+func TestConversationTrimToTokensSummarize_BailsWhenStuck(t *testing.T) {
+	cleanup := withTestGlobals(t)
+	defer cleanup()
+
+	p := &stubProvider{
+		name: "stub",
+		sendFn: func(ctx context.Context, req *ProviderRequest) (*ProviderResponse, error) {
+			return &ProviderResponse{Content: "a summary so long it still exceeds any reasonable token budget on its own, over and over"}, nil
+		},
+	}
+	chat := (&Conversation{
+		builder: New(ModelGPT5).WithClient(&Client{provider: p, providerType: ProviderOpenAI}),
+		history: []Message{
+			{Role: "user", Content: "first message here"},
+			{Role: "assistant", Content: "second message here"},
+			{Role: "user", Content: "third message here"},
+		},
+	}).WithWindowStrategy(WindowSummarize)
+
+	if err := chat.TrimToTokens(1); err == nil {
+		t.Fatal("expected TrimToTokens to bail with an error instead of looping forever")
+	}
+}